@@ -0,0 +1,224 @@
+package passval
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// analysis holds the forms and statistics of a password shared by the rule
+// checks and the penalty detectors. It is computed once per validation so
+// that detectors do not each lowercase and rescan the input.
+type analysis struct {
+	password string
+	lower    string
+	runes    []rune    // lower as runes; rune i starts at byte offsets[i] of password
+	offsets  []int     // byte offset in password of each rune, followed by len(password)
+	reversed string    // lower read backwards, for reversed dictionary words
+	leet     leetTable // substitutions for dictionary and banned term matching
+
+	lowerCount, upperCount, numberCount, symbolCount int
+
+	uniqueRunes int // distinct runes in lower, or distinct clusters
+	maxRepeat   int // longest run of one repeated rune, or cluster
+	repeatAt    int // rune index where that run starts
+	repeatEnd   int // rune index where that run ends
+	maxSequence int // longest run of runes ascending or descending by one
+	sequenceAt  int // rune index where that run starts
+
+	// clusters holds the rune index where each grapheme cluster starts,
+	// followed by len(runes), with WithGraphemeAnalysis; nil otherwise.
+	clusters []int
+
+	buffers [][]byte // working copies of the password zeroed by wipe
+}
+
+// analyze computes the shared analysis of password, matching leet-speak with table.
+func analyze(password string, table leetTable) *analysis {
+	lower := strings.ToLower(password)
+	return newAnalysis(password, lower, reverseString(lower), table)
+}
+
+// analyzeSecret is like analyze for a password held in a caller's buffer. The
+// buffer is read in place rather than copied into a string, and the lowercase
+// and reversed forms are built in buffers that wipe zeroes. password must not
+// be modified until the analysis is wiped.
+func analyzeSecret(password []byte, table leetTable) *analysis {
+	lower := bytes.ToLower(password)
+	reversed := make([]byte, 0, len(lower))
+	for i := len(lower); i > 0; {
+		r, size := utf8.DecodeLastRune(lower[:i])
+		reversed = utf8.AppendRune(reversed, r)
+		i -= size
+	}
+	a := newAnalysis(bytesView(password), bytesView(lower), bytesView(reversed), table)
+	a.buffers = [][]byte{lower, reversed}
+	return a
+}
+
+// bytesView returns b as a string without copying it.
+func bytesView(b []byte) string {
+	return unsafe.String(unsafe.SliceData(b), len(b))
+}
+
+// wipe zeroes the working copies of the password held by the analysis. It is
+// best-effort: strings derived during matching are left to the garbage collector.
+func (a *analysis) wipe() {
+	clear(a.runes)
+	for _, b := range a.buffers {
+		clear(b)
+	}
+}
+
+func newAnalysis(password, lower, reversed string, table leetTable) *analysis {
+	a := &analysis{
+		password: password,
+		lower:    lower,
+		reversed: reversed,
+		leet:     table,
+	}
+	// strings.ToLower maps rune by rune, so rune indices of lower and
+	// password agree even where their byte lengths differ
+	a.runes = []rune(a.lower)
+	for i := range password {
+		a.offsets = append(a.offsets, i)
+	}
+	a.offsets = append(a.offsets, len(password))
+	a.lowerCount, a.upperCount, a.numberCount, a.symbolCount = charClassCounts(password)
+
+	unique := make(map[rune]bool)
+	for _, r := range a.runes {
+		unique[r] = true
+	}
+	a.uniqueRunes = len(unique)
+
+	if len(a.runes) > 0 {
+		a.maxRepeat, a.maxSequence = 1, 1
+	}
+	repeat, seq := 1, 1
+	for i := 1; i < len(a.runes); i++ {
+		if a.runes[i] == a.runes[i-1] {
+			repeat++
+		} else {
+			repeat = 1
+		}
+		if diff := a.runes[i] - a.runes[i-1]; diff == 1 || diff == -1 {
+			seq++
+		} else {
+			seq = 1
+		}
+		if repeat > a.maxRepeat {
+			a.maxRepeat, a.repeatAt = repeat, i-repeat+1
+		}
+		if seq > a.maxSequence {
+			a.maxSequence, a.sequenceAt = seq, i-seq+1
+		}
+	}
+	a.repeatEnd = a.repeatAt + a.maxRepeat
+	return a
+}
+
+// prefix returns the analysis of the longest prefix of the password of at
+// most n bytes that ends on a rune boundary. Its working copies are zeroed by
+// its own wipe.
+func (a *analysis) prefix(n int) *analysis {
+	k, _ := slices.BinarySearch(a.offsets, n+1)
+	k-- // offsets[k] <= n
+	lower := []byte(string(a.runes[:k]))
+	reversed := make([]byte, 0, len(lower))
+	for i := k - 1; i >= 0; i-- {
+		reversed = utf8.AppendRune(reversed, a.runes[i])
+	}
+	p := newAnalysis(a.password[:a.offsets[k]], bytesView(lower), bytesView(reversed), a.leet)
+	p.buffers = [][]byte{lower, reversed}
+	if a.clusters != nil {
+		p.useGraphemes()
+	}
+	return p
+}
+
+// length returns the length of the password for the length rules: its
+// grapheme clusters with WithGraphemeAnalysis, otherwise its bytes.
+func (a *analysis) length() int {
+	if a.clusters != nil {
+		return len(a.clusters) - 1
+	}
+	return len(a.password)
+}
+
+// spanned sets the span of p to runes [start, end) of the password, as byte
+// offsets, and returns p.
+func (a *analysis) spanned(p *PenaltyDetail, start, end int) *PenaltyDetail {
+	p.Start, p.End = a.offsets[start], a.offsets[end]
+	return p
+}
+
+// whole sets the span of p to the whole password and returns p.
+func (a *analysis) whole(p *PenaltyDetail) *PenaltyDetail {
+	return a.spanned(p, 0, len(a.runes))
+}
+
+// form is a reading of the password for dictionary matching, with a note
+// appended to penalty descriptions.
+type form struct {
+	s, note  string
+	reversed bool
+}
+
+// forms returns the lowercase password and its reversal, the oldest mangling rule.
+func (a *analysis) forms() []form {
+	return []form{{a.lower, "", false}, {a.reversed, " (reversed)", true}}
+}
+
+// spanOf returns the rune span of m in the password, mapping matches in the
+// reversed form back to forward positions.
+func (a *analysis) spanOf(m dictMatch, f form) (start, end int) {
+	if f.reversed {
+		return len(a.runes) - m.end, len(a.runes) - m.start
+	}
+	return m.start, m.end
+}
+
+// boundary reports whether a word of the password can start or end at rune
+// index i: at either end of the password, between characters of different
+// classes (letter, digit, other), or where a lowercase letter is followed by
+// an uppercase one ("Paradise|Lost").
+func (a *analysis) boundary(i int) bool {
+	if i <= 0 || i >= len(a.runes) {
+		return true
+	}
+	prev, next := a.rune(i-1), a.rune(i)
+	return runeClass(prev) != runeClass(next) || unicode.IsLower(prev) && unicode.IsUpper(next)
+}
+
+// inCapitalizedWord reports whether rune index i lies in a word of the
+// password, as delimited by boundary, that is capitalized: an uppercase letter
+// followed by a lowercase one ("Strangers", not "ÄÖdragon").
+func (a *analysis) inCapitalizedWord(i int) bool {
+	for s := i; s >= 0; s-- {
+		if a.boundary(s) {
+			return s+1 < len(a.runes) && unicode.IsUpper(a.rune(s)) && unicode.IsLower(a.rune(s+1))
+		}
+	}
+	return false
+}
+
+// rune returns rune index i of the password in its original case.
+func (a *analysis) rune(i int) rune {
+	r, _ := utf8.DecodeRuneInString(a.password[a.offsets[i]:])
+	return r
+}
+
+// runeClass classifies r as a letter (0), a digit (1) or anything else (2).
+func runeClass(r rune) int {
+	switch {
+	case unicode.IsLetter(r):
+		return 0
+	case unicode.IsDigit(r):
+		return 1
+	}
+	return 2
+}
@@ -0,0 +1,223 @@
+package passval
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// auditTopN is the number of entries kept in the top-N lists of an AuditReport.
+const auditTopN = 10
+
+// maxAuditLine is the longest line the Auditor reads; longer lines are an error.
+const maxAuditLine = 1 << 20
+
+// Auditor validates password dumps against a policy and aggregates statistics,
+// e.g. to assess how an existing user base would fare under a new policy.
+type Auditor struct {
+	// Workers is the number of passwords ValidateStream validates
+	// concurrently; 0 means runtime.GOMAXPROCS(0). Set it before use.
+	Workers int
+
+	v Validator
+}
+
+// NewAuditor creates an auditor for the given validator's policy.
+func NewAuditor(v Validator) *Auditor {
+	return &Auditor{v: v}
+}
+
+// validate validates password without reporting audited passwords to the
+// metrics of a *PasswordValidator.
+func (a *Auditor) validate(password string) *Result {
+	if v, ok := a.v.(*PasswordValidator); ok {
+		return v.validate(password)
+	}
+	return a.v.ValidateResult(password)
+}
+
+// CountEntry is a key with its number of occurrences.
+type CountEntry struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// AuditReport holds the aggregate statistics of an audit. It never contains
+// the audited passwords themselves, except as matched dictionary words.
+type AuditReport struct {
+	Total    int     `json:"total"`
+	Passed   int     `json:"passed"`
+	PassRate float64 `json:"pass_rate"`
+
+	// ScoreHistogram counts scores in buckets of 10: [0-9], [10-19], ... [90-100].
+	ScoreHistogram [10]int `json:"score_histogram"`
+
+	// ReasonCounts counts failed rule codes and applied penalty identifiers.
+	ReasonCounts map[string]int `json:"reason_counts"`
+	// TopReasons lists the most frequent reasons, most frequent first.
+	TopReasons []CountEntry `json:"top_reasons"`
+	// TopDictionaryHits lists the most frequently matched common passwords and
+	// dictionary words, most frequent first.
+	TopDictionaryHits []CountEntry `json:"top_dictionary_hits"`
+
+	dictionaryHits map[string]int
+}
+
+// Audit reads newline-delimited passwords from r, validates each one and
+// returns the aggregate report. Empty lines are skipped.
+func (a *Auditor) Audit(r io.Reader) (*AuditReport, error) {
+	return a.AuditTo(r, nil)
+}
+
+// AuditTo is like Audit and also writes the record of each password to rw,
+// e.g. a CSV report for a BI tool. rw is flushed before returning; a nil rw
+// writes nothing.
+func (a *Auditor) AuditTo(r io.Reader, rw *ReportWriter) (*AuditReport, error) {
+	report := newAuditReport()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxAuditLine)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		res := a.validate(line)
+		if rw != nil {
+			if err := rw.Write(report.Total, line, res); err != nil {
+				return nil, err
+			}
+		}
+		report.add(res)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if rw != nil {
+		if err := rw.Flush(); err != nil {
+			return nil, err
+		}
+	}
+
+	report.finish()
+	return report, nil
+}
+
+// ValidateStream validates the passwords received from in on Workers
+// goroutines and sends their results to the returned channel, in the order
+// the passwords were received. At most Workers passwords are in flight: a
+// slow consumer stops the reading of in, so audits of any size run in
+// constant memory. The channel is closed after in is closed and drained, or
+// once ctx is done, when remaining passwords are not validated.
+func (a *Auditor) ValidateStream(ctx context.Context, in <-chan string) <-chan Result {
+	workers := a.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	// pending holds one channel per password in flight, in input order; its
+	// capacity bounds the concurrency.
+	pending := make(chan chan Result, workers)
+	go func() {
+		defer close(pending)
+		for {
+			var password string
+			select {
+			case p, ok := <-in:
+				if !ok {
+					return
+				}
+				password = p
+			case <-ctx.Done():
+				return
+			}
+			res := make(chan Result, 1)
+			select {
+			case pending <- res:
+			case <-ctx.Done():
+				return
+			}
+			go func() { res <- *a.validate(password) }()
+		}
+	}()
+
+	out := make(chan Result)
+	go func() {
+		defer close(out)
+		for res := range pending {
+			r := <-res
+			select {
+			case out <- r:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func newAuditReport() *AuditReport {
+	return &AuditReport{
+		ReasonCounts:   make(map[string]int),
+		dictionaryHits: make(map[string]int),
+	}
+}
+
+// add accumulates one validation result.
+func (rep *AuditReport) add(r *Result) {
+	rep.Total++
+	if r.Pass {
+		rep.Passed++
+	}
+
+	bucket := r.Score / 10
+	if bucket > 9 {
+		bucket = 9
+	}
+	rep.ScoreHistogram[bucket]++
+
+	for _, code := range r.Codes() {
+		rep.ReasonCounts[code]++
+	}
+	for _, p := range r.Penalties {
+		if p.Match != "" && isDictionaryPenalty(p.Rule) {
+			rep.dictionaryHits[p.Match]++
+		}
+	}
+}
+
+// isDictionaryPenalty reports whether the Match of penalty rule is a listed
+// word, rather than a part of the password such as an email address.
+func isDictionaryPenalty(rule string) bool {
+	return strings.HasPrefix(rule, "common_password") || strings.HasPrefix(rule, "dictionary_") || rule == "natural_word"
+}
+
+// finish computes the derived fields once all results are added.
+func (rep *AuditReport) finish() {
+	if rep.Total > 0 {
+		rep.PassRate = float64(rep.Passed) / float64(rep.Total)
+	}
+	rep.TopReasons = topCounts(rep.ReasonCounts, auditTopN)
+	rep.TopDictionaryHits = topCounts(rep.dictionaryHits, auditTopN)
+}
+
+// topCounts returns the n most frequent entries of counts, ties broken by key.
+func topCounts(counts map[string]int, n int) []CountEntry {
+	entries := make([]CountEntry, 0, len(counts))
+	for k, c := range counts {
+		entries = append(entries, CountEntry{Key: k, Count: c})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Key < entries[j].Key
+	})
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
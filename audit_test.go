@@ -0,0 +1,110 @@
+package passval
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestAuditor(t *testing.T) {
+	dump := strings.Join([]string{
+		"password",
+		"password",
+		"p@ssw0rd",
+		"",
+		"dragon2024",
+		"Xk9$mP2!vLq#7",
+	}, "\n")
+
+	v := NewPasswordValidator(8, 64, false, false, false, false, 50)
+	report, err := NewAuditor(v).Audit(strings.NewReader(dump))
+	if err != nil {
+		t.Fatalf("Audit() error: %v", err)
+	}
+
+	if report.Total != 5 {
+		t.Errorf("expected 5 passwords (empty line skipped), got %d", report.Total)
+	}
+	if report.Passed != 1 || report.PassRate != 0.2 {
+		t.Errorf("expected 1 pass (20%%), got %d (%.2f)", report.Passed, report.PassRate)
+	}
+
+	histTotal := 0
+	for _, n := range report.ScoreHistogram {
+		histTotal += n
+	}
+	if histTotal != report.Total {
+		t.Errorf("histogram counts %d passwords, want %d", histTotal, report.Total)
+	}
+
+	if len(report.TopReasons) == 0 || report.ReasonCounts[RuleComplexity] != 4 {
+		t.Errorf("unexpected reasons: %v", report.ReasonCounts)
+	}
+	if len(report.TopDictionaryHits) == 0 || report.TopDictionaryHits[0].Key != "password" {
+		t.Errorf("expected 'password' as top dictionary hit, got %v", report.TopDictionaryHits)
+	}
+}
+
+func TestAuditorKeepsPasswordsOut(t *testing.T) {
+	dump := "john.doe@gmail.com\nhttps://www.mybank.com\n6915553412aB!\nXq7#Xq7#Xq7#Xq7#\ndragon2024\n"
+	report, err := NewAuditor(NewPasswordValidator(8, 64, false, false, false, false, 50)).Audit(strings.NewReader(dump))
+	if err != nil {
+		t.Fatalf("Audit() error: %v", err)
+	}
+	for _, rule := range []string{"email_address", "url", "phone_number", "repeated_pattern"} {
+		if report.ReasonCounts[rule] == 0 {
+			t.Errorf("expected a %s penalty, got %v", rule, report.ReasonCounts)
+		}
+	}
+	for _, e := range report.TopDictionaryHits {
+		if strings.ContainsAny(e.Key, "@.#0123456789") {
+			t.Errorf("dictionary hits reveal a password: %v", report.TopDictionaryHits)
+			break
+		}
+	}
+	if len(report.TopDictionaryHits) == 0 || report.TopDictionaryHits[0].Key != "dragon" {
+		t.Errorf("expected 'dragon' as top dictionary hit, got %v", report.TopDictionaryHits)
+	}
+}
+
+func TestValidateStream(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 50)
+	a := NewAuditor(v)
+	a.Workers = 3
+
+	passwords := []string{"password", "Tr0ub4dor&3xyz", "short", "qwerty123", "Xq7#zR2mK9w!", "letmein"}
+	in := make(chan string)
+	go func() {
+		defer close(in)
+		for range 20 {
+			for _, pw := range passwords {
+				in <- pw
+			}
+		}
+	}()
+
+	i := 0
+	for r := range a.ValidateStream(context.Background(), in) {
+		want := v.ValidateResult(passwords[i%len(passwords)])
+		if r.Pass != want.Pass || r.Score != want.Score {
+			t.Fatalf("result %d = pass %v score %d, want pass %v score %d", i, r.Pass, r.Score, want.Pass, want.Score)
+		}
+		i++
+	}
+	if i != 20*len(passwords) {
+		t.Errorf("got %d results, want %d", i, 20*len(passwords))
+	}
+}
+
+func TestValidateStreamCancel(t *testing.T) {
+	a := NewAuditor(NewPasswordValidator(8, 64, false, false, false, false, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan string) // never closed
+	out := a.ValidateStream(ctx, in)
+
+	in <- "password"
+	<-out
+	cancel()
+	for range out {
+	}
+}
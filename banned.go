@@ -0,0 +1,112 @@
+package passval
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// bannedTerm is a banned substring in its lowercase and leet-normalized forms.
+type bannedTerm struct {
+	term       string
+	normalized string
+}
+
+// WithBannedSubstrings bans passwords containing any of the given terms
+// (e.g. company or product names). Matching is case-insensitive and
+// leet-aware, so "Acm3" matches "acme". A match is a hard rule failure
+// with code RuleBannedSubstring.
+func WithBannedSubstrings(terms ...string) Option {
+	return func(v *PasswordValidator) {
+		v.bannedTerms = append(v.bannedTerms, newBannedTerms(terms)...)
+	}
+}
+
+// WithSiteTerms sets the names of the site or product the validator serves
+// ("acme", "acmebank", "acmeapp"), the user inputs every password is checked
+// against: passwords containing one, in any case or with leet substitutions
+// ("Acm3-2024!"), fail with code RuleSiteTerm. Per-user inputs such as the
+// username still go with each request, e.g. passvalhttp.Request.UserInputs.
+func WithSiteTerms(terms ...string) Option {
+	return func(v *PasswordValidator) {
+		v.siteTerms = append(v.siteTerms, newBannedTerms(terms)...)
+	}
+}
+
+// newBannedTerms lowercases and leet-normalizes terms, skipping blank ones.
+func newBannedTerms(terms []string) []bannedTerm {
+	var out []bannedTerm
+	for _, t := range terms {
+		t = strings.TrimSpace(strings.ToLower(t))
+		if t == "" {
+			continue
+		}
+		out = append(out, bannedTerm{term: t, normalized: leetNormalize(t)})
+	}
+	return out
+}
+
+// findBannedTerm returns the first banned term contained in password, or "".
+func findBannedTerm(password string, terms []bannedTerm, table leetTable) string {
+	if len(terms) == 0 {
+		return ""
+	}
+	return matchBannedTerm(strings.ToLower(password), terms, table)
+}
+
+// matchBannedTerm returns the first term contained in lower, literally or
+// through the substitutions in table, or "".
+func matchBannedTerm(lower string, terms []bannedTerm, table leetTable) string {
+	for _, t := range terms {
+		if strings.Contains(lower, t.term) || leetContains(lower, t.normalized, table) {
+			return t.term
+		}
+	}
+	return ""
+}
+
+// checkBannedTerms records a rule failure if the analyzed password contains a
+// banned term, using the index of a compiled policy if there is one.
+func checkBannedTerms(vErr *ValidationError, a *analysis, terms []bannedTerm, idx *bannedIndex) {
+	var term string
+	if idx != nil {
+		term = idx.match(a.lower, a.leet)
+	} else {
+		term = matchBannedTerm(a.lower, terms, a.leet)
+	}
+	if term != "" {
+		vErr.fail(RuleBannedSubstring, fmt.Sprintf("contains banned term '%s'", term), "term", term)
+	}
+}
+
+// checkSiteTerms records a rule failure if the analyzed password contains a
+// site term.
+func checkSiteTerms(vErr *ValidationError, a *analysis, terms []bannedTerm) {
+	if term := matchBannedTerm(a.lower, terms, a.leet); term != "" {
+		vErr.fail(RuleSiteTerm, fmt.Sprintf("contains the site name '%s'", term), "term", term)
+	}
+}
+
+// WithBannedPatterns bans passwords matching any of the given regular
+// expressions (e.g. employee-ID formats or ticket-number shapes). The
+// expressions are compiled by the caller, once, and matched against the raw
+// password. A match is a hard rule failure with code RuleBannedPattern.
+func WithBannedPatterns(patterns ...*regexp.Regexp) Option {
+	return func(v *PasswordValidator) {
+		for _, re := range patterns {
+			if re != nil {
+				v.bannedPatterns = append(v.bannedPatterns, re)
+			}
+		}
+	}
+}
+
+// checkBannedPatterns records a rule failure for the first banned pattern password matches.
+func checkBannedPatterns(vErr *ValidationError, password string, patterns []*regexp.Regexp) {
+	for _, re := range patterns {
+		if re.MatchString(password) {
+			vErr.fail(RuleBannedPattern, fmt.Sprintf("matches banned pattern %s", re.String()), "pattern", re.String())
+			return
+		}
+	}
+}
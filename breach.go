@@ -0,0 +1,210 @@
+package passval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BreachChecker reports whether a password appears in a known data breach,
+// e.g. by querying the Pwned Passwords range API or a local dataset.
+// Implementations must be safe for concurrent use.
+type BreachChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
+
+// BreachFailureMode decides what ValidateContext does when the breach check
+// fails, times out or is skipped by the circuit breaker.
+type BreachFailureMode int
+
+const (
+	// BreachFailError returns the error and leaves the decision to the
+	// caller. It is the default.
+	BreachFailError BreachFailureMode = iota
+	// BreachFailOpen accepts the password with a WarnBreachSkipped
+	// warning, favouring availability.
+	BreachFailOpen
+	// BreachFailClosed rejects the password with code RuleBreachUnchecked,
+	// favouring security.
+	BreachFailClosed
+)
+
+// String returns the name of the failure mode.
+func (m BreachFailureMode) String() string {
+	switch m {
+	case BreachFailError:
+		return "error"
+	case BreachFailOpen:
+		return "open"
+	case BreachFailClosed:
+		return "closed"
+	}
+	return fmt.Sprintf("BreachFailureMode(%d)", int(m))
+}
+
+// ErrBreachCircuitOpen is reported for breach checks skipped because the
+// circuit breaker is open.
+var ErrBreachCircuitOpen = errors.New("breach check circuit open")
+
+// WithBreachChecker adds a breached-password check to ValidateContext.
+// A breached password fails with code RuleBreached.
+func WithBreachChecker(c BreachChecker) Option {
+	return func(v *PasswordValidator) {
+		v.breachChecker = c
+	}
+}
+
+// WithBreachFailureMode sets how ValidateContext handles a failed breach
+// check. In BreachFailOpen and BreachFailClosed modes it returns no error for
+// the failure; the Result carries the outcome.
+func WithBreachFailureMode(mode BreachFailureMode) Option {
+	return func(v *PasswordValidator) {
+		v.breachMode = mode
+	}
+}
+
+// WithBreachTimeout bounds each breach check to d, so that a slow remote
+// service cannot stall signups. A timeout counts as a failed check.
+func WithBreachTimeout(d time.Duration) Option {
+	return func(v *PasswordValidator) {
+		v.breachTimeout = d
+	}
+}
+
+// WithBreachCircuitBreaker stops calling the breach checker for cooldown
+// after failures consecutive failed checks; skipped checks fail with
+// ErrBreachCircuitOpen and are handled by the failure mode. After the
+// cooldown a single check is let through, and its success closes the
+// circuit. Validators derived with Clone share the circuit.
+func WithBreachCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return func(v *PasswordValidator) {
+		v.breaker = &circuitBreaker{threshold: max(failures, 1), cooldown: cooldown, now: time.Now}
+	}
+}
+
+// ValidateContext validates password like ValidateResult and, if the local
+// rules pass, runs the configured external checks (breach lookup) under ctx.
+// The returned error reports a failed external check, not a policy failure;
+// policy failures are described by the Result. See WithBreachFailureMode to
+// decide failed checks in the Result instead.
+func (v *PasswordValidator) ValidateContext(ctx context.Context, password string) (*Result, error) {
+	r := v.validate(password)
+
+	var err error
+	if r.Pass && v.breachChecker != nil {
+		err = v.checkBreach(ctx, password, r)
+	}
+
+	v.report(r)
+	return r, err
+}
+
+// checkBreach runs the breach checker inside a span, guarded by the circuit
+// breaker and timeout, and records a rule failure if the password is
+// breached. A failed check is handled according to the failure mode, which is
+// recorded on the span.
+func (v *PasswordValidator) checkBreach(ctx context.Context, password string, r *Result) error {
+	ctx, span := v.startSpan(ctx, "passval.breach_check")
+	start := time.Now()
+
+	var breached bool
+	var err error
+	if v.breaker != nil && !v.breaker.allow() {
+		err = ErrBreachCircuitOpen
+	} else {
+		breached, err = v.runBreachCheck(ctx, password)
+		// A caller giving up is not a failure of the checker.
+		switch {
+		case v.breaker == nil:
+		case ctx.Err() != nil:
+			v.breaker.release()
+		default:
+			v.breaker.record(err)
+		}
+	}
+	span.SetAttribute("passval.breached", breached)
+	if err != nil {
+		span.SetAttribute("passval.breach_failure_mode", v.breachMode.String())
+		span.SetAttribute("passval.breach_failed_open", v.breachMode == BreachFailOpen)
+	}
+	endSpan(span, start, err)
+
+	if err != nil {
+		err = fmt.Errorf("breach check: %w", err)
+		switch v.breachMode {
+		case BreachFailOpen:
+			r.Warnings = append(r.Warnings, Warning{Code: WarnBreachSkipped, Message: err.Error()})
+			return nil
+		case BreachFailClosed:
+			v.addRuleFail(r, newRuleFail(RuleBreachUnchecked, "breach check unavailable; try again later"))
+			return nil
+		}
+		return err
+	}
+	if breached {
+		v.addRuleFail(r, newRuleFail(RuleBreached, "password appears in a known data breach"))
+	}
+	return nil
+}
+
+func (v *PasswordValidator) runBreachCheck(ctx context.Context, password string) (bool, error) {
+	if v.breachTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, v.breachTimeout)
+		defer cancel()
+	}
+	return v.breachChecker.IsBreached(ctx, password)
+}
+
+// circuitBreaker counts consecutive failures of a remote check and rejects
+// calls for a cooldown once they reach the threshold.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time // replaced in tests
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool // a trial call is in flight after the cooldown
+}
+
+// allow reports whether a call may proceed.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.probing || b.now().Before(b.openUntil) {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// release ends an allowed call without counting its outcome, e.g. one the
+// caller cancelled, so that a trial call after the cooldown does not leave the
+// circuit open for good.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// record updates the breaker with the outcome of an allowed call.
+func (b *circuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if err == nil {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
@@ -0,0 +1,208 @@
+package passval
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+type fakeBreachChecker struct {
+	breached map[string]bool
+	err      error
+}
+
+func (f *fakeBreachChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.breached[password], nil
+}
+
+type fakeSpan struct {
+	name  string
+	attrs map[string]any
+	err   error
+	ended bool
+}
+
+func (s *fakeSpan) SetAttribute(key string, value any) { s.attrs[key] = value }
+func (s *fakeSpan) RecordError(err error)              { s.err = err }
+func (s *fakeSpan) End()                               { s.ended = true }
+
+type fakeTracer struct {
+	spans []*fakeSpan
+}
+
+func (t *fakeTracer) Tracer(name string) Tracer { return t }
+
+func (t *fakeTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	s := &fakeSpan{name: name, attrs: make(map[string]any)}
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+func TestValidateContext_Breach(t *testing.T) {
+	checker := &fakeBreachChecker{breached: map[string]bool{"Tr0ub4dor&3xyz": true}}
+	tracer := &fakeTracer{}
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0,
+		WithBreachChecker(checker), WithTracerProvider(tracer))
+
+	r, err := v.ValidateContext(context.Background(), "Tr0ub4dor&3xyz")
+	if err != nil {
+		t.Fatalf("ValidateContext() error: %v", err)
+	}
+	if r.Pass {
+		t.Error("breached password should fail")
+	}
+	if codes := r.Codes(); len(codes) == 0 || codes[0] != RuleBreached {
+		t.Errorf("expected %s code, got %v", RuleBreached, codes)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if !span.ended || span.attrs["passval.breached"] != true {
+		t.Errorf("unexpected span state: %+v", span)
+	}
+	if _, ok := span.attrs["passval.latency_ms"]; !ok {
+		t.Error("expected latency attribute on span")
+	}
+
+	r, err = v.ValidateContext(context.Background(), "Xk9$mP2!vLq")
+	if err != nil || !r.Pass {
+		t.Errorf("non-breached password should pass: %v %v", r.Err(), err)
+	}
+}
+
+func TestValidateContext_BreachError(t *testing.T) {
+	checker := &fakeBreachChecker{err: errors.New("timeout")}
+	tracer := &fakeTracer{}
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0,
+		WithBreachChecker(checker), WithTracerProvider(tracer))
+
+	if _, err := v.ValidateContext(context.Background(), "Xk9$mP2!vLq"); err == nil {
+		t.Error("expected breach check error")
+	}
+	if tracer.spans[0].err == nil {
+		t.Error("expected error recorded on span")
+	}
+}
+
+func TestValidateContext_BreachFailureMode(t *testing.T) {
+	checker := &fakeBreachChecker{err: errors.New("timeout")}
+	tracer := &fakeTracer{}
+
+	open := NewPasswordValidator(8, 64, false, false, false, false, 0,
+		WithBreachChecker(checker), WithBreachFailureMode(BreachFailOpen), WithTracerProvider(tracer))
+	r, err := open.ValidateContext(context.Background(), "Xk9$mP2!vLq")
+	if err != nil || !r.Pass {
+		t.Fatalf("fail-open: pass = %v, err = %v", r.Pass, err)
+	}
+	if !slices.ContainsFunc(r.Warnings, func(w Warning) bool { return w.Code == WarnBreachSkipped }) {
+		t.Errorf("fail-open: expected %s warning, got %v", WarnBreachSkipped, r.Warnings)
+	}
+	if span := tracer.spans[0]; span.err == nil || span.attrs["passval.breach_failure_mode"] != "open" || span.attrs["passval.breach_failed_open"] != true {
+		t.Errorf("fail-open: unexpected span state: %+v", span)
+	}
+
+	closed := open.Clone(WithBreachFailureMode(BreachFailClosed))
+	r, err = closed.ValidateContext(context.Background(), "Xk9$mP2!vLq")
+	if err != nil || r.Pass {
+		t.Fatalf("fail-closed: pass = %v, err = %v", r.Pass, err)
+	}
+	if codes := r.Codes(); !slices.Contains(codes, RuleBreachUnchecked) {
+		t.Errorf("fail-closed: expected %s code, got %v", RuleBreachUnchecked, codes)
+	}
+	if span := tracer.spans[1]; span.attrs["passval.breach_failure_mode"] != "closed" || span.attrs["passval.breach_failed_open"] != false {
+		t.Errorf("fail-closed: unexpected span state: %+v", span)
+	}
+}
+
+type slowBreachChecker struct{}
+
+func (slowBreachChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	<-ctx.Done()
+	return false, ctx.Err()
+}
+
+func TestValidateContext_BreachTimeout(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0,
+		WithBreachChecker(slowBreachChecker{}), WithBreachTimeout(10*time.Millisecond))
+
+	_, err := v.ValidateContext(context.Background(), "Xk9$mP2!vLq")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got %v", err)
+	}
+}
+
+type countingBreachChecker struct {
+	calls int
+	err   error
+}
+
+func (c *countingBreachChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	c.calls++
+	return false, c.err
+}
+
+func TestValidateContext_BreachCircuitBreaker(t *testing.T) {
+	checker := &countingBreachChecker{err: errors.New("unavailable")}
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0,
+		WithBreachChecker(checker), WithBreachCircuitBreaker(3, time.Minute))
+	now := time.Unix(0, 0)
+	v.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for range 5 {
+		v.ValidateContext(ctx, "Xk9$mP2!vLq")
+	}
+	if checker.calls != 3 {
+		t.Errorf("checker called %d times, want 3 before the circuit opens", checker.calls)
+	}
+	if _, err := v.ValidateContext(ctx, "Xk9$mP2!vLq"); !errors.Is(err, ErrBreachCircuitOpen) {
+		t.Errorf("expected ErrBreachCircuitOpen, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	checker.err = nil
+	if _, err := v.ValidateContext(ctx, "Xk9$mP2!vLq"); err != nil {
+		t.Errorf("trial check after cooldown: %v", err)
+	}
+	if _, err := v.ValidateContext(ctx, "Xk9$mP2!vLq"); err != nil || checker.calls != 5 {
+		t.Errorf("closed circuit: err = %v, calls = %d, want 5", err, checker.calls)
+	}
+}
+
+type cancelingBreachChecker struct {
+	cancel context.CancelFunc
+}
+
+func (c cancelingBreachChecker) IsBreached(ctx context.Context, password string) (bool, error) {
+	c.cancel()
+	return false, ctx.Err()
+}
+
+func TestValidateContext_BreachCircuitCanceledProbe(t *testing.T) {
+	checker := &countingBreachChecker{err: errors.New("unavailable")}
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0,
+		WithBreachChecker(checker), WithBreachCircuitBreaker(1, time.Minute))
+	now := time.Unix(0, 0)
+	v.breaker.now = func() time.Time { return now }
+	v.ValidateContext(context.Background(), "Xk9$mP2!vLq")
+
+	// The trial call after the cooldown is cancelled by the caller.
+	now = now.Add(time.Minute)
+	ctx, cancel := context.WithCancel(context.Background())
+	canceling := cancelingBreachChecker{cancel: cancel}
+	if _, err := v.Clone(WithBreachChecker(canceling)).ValidateContext(ctx, "Xk9$mP2!vLq"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the cancelled probe, got %v", err)
+	}
+
+	checker.err = nil
+	if _, err := v.ValidateContext(context.Background(), "Xk9$mP2!vLq"); err != nil || checker.calls != 2 {
+		t.Errorf("after a cancelled probe: err = %v, calls = %d, want 2", err, checker.calls)
+	}
+}
@@ -0,0 +1,119 @@
+package passval
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// calibrationData is the embedded calibration corpus, one "label<TAB>password"
+// line per sample.
+//
+//go:embed data/calibration.txt
+var calibrationData string
+
+// Calibration labels, the classes of a calibration corpus.
+const (
+	LabelWeak   = "weak"
+	LabelMedium = "medium"
+	LabelStrong = "strong"
+)
+
+// CalibrationSample is a corpus password the scoring classified differently
+// from its label.
+type CalibrationSample struct {
+	Password string `json:"password"`
+	Label    string `json:"label"`
+	Got      string `json:"got"`
+	Score    int    `json:"score"`
+}
+
+// CalibrationReport describes how the scoring configuration of a validator
+// classifies a labeled corpus. A score is classified by its StrengthLabel:
+// very weak and weak scores are weak, fair ones medium, strong and very
+// strong ones strong.
+type CalibrationReport struct {
+	Total    int     `json:"total"`
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"`
+
+	// Confusion counts samples by label, then by the class they were given.
+	Confusion map[string]map[string]int `json:"confusion"`
+	// MeanScore is the average score of the samples of each label.
+	MeanScore map[string]float64 `json:"mean_score"`
+	// Misclassified lists the samples whose class differs from their label.
+	Misclassified []CalibrationSample `json:"misclassified"`
+}
+
+// Calibrate classifies the embedded calibration corpus of common, patterned,
+// moderate and random passwords with the validator's scoring, so the effect of
+// penalty factors and scoring options can be measured when tuning a policy.
+func (v *PasswordValidator) Calibrate() *CalibrationReport {
+	report, _ := v.Evaluate(strings.NewReader(calibrationData))
+	return report
+}
+
+// Evaluate is like Calibrate for a corpus read from r, with one
+// "label<TAB>password" line per sample, where label is LabelWeak, LabelMedium
+// or LabelStrong. Empty lines and lines starting with '#' are skipped.
+func (v *PasswordValidator) Evaluate(r io.Reader) (*CalibrationReport, error) {
+	report := &CalibrationReport{
+		Confusion: make(map[string]map[string]int),
+		MeanScore: make(map[string]float64),
+	}
+	counts := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxAuditLine)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		label, password, ok := strings.Cut(line, "\t")
+		if !ok || (label != LabelWeak && label != LabelMedium && label != LabelStrong) {
+			return nil, fmt.Errorf("line %d: want \"weak|medium|strong<TAB>password\"", n)
+		}
+
+		score := v.validate(password).Score
+		got := calibrationClass(score)
+		if report.Confusion[label] == nil {
+			report.Confusion[label] = make(map[string]int)
+		}
+		report.Confusion[label][got]++
+		report.MeanScore[label] += float64(score)
+		counts[label]++
+		report.Total++
+		if got == label {
+			report.Correct++
+		} else {
+			report.Misclassified = append(report.Misclassified, CalibrationSample{
+				Password: password, Label: label, Got: got, Score: score,
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for label, n := range counts {
+		report.MeanScore[label] /= float64(n)
+	}
+	if report.Total > 0 {
+		report.Accuracy = float64(report.Correct) / float64(report.Total)
+	}
+	return report, nil
+}
+
+// calibrationClass maps a score to a calibration label.
+func calibrationClass(score int) string {
+	switch StrengthLabel(score) {
+	case StrengthVeryWeak, StrengthWeak:
+		return LabelWeak
+	case StrengthFair:
+		return LabelMedium
+	}
+	return LabelStrong
+}
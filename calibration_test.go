@@ -0,0 +1,35 @@
+package passval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCalibrate(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0)
+	r := v.Calibrate()
+	if r.Total != 60 || r.Correct+len(r.Misclassified) != r.Total {
+		t.Fatalf("unexpected totals %d/%d with %d misclassified", r.Correct, r.Total, len(r.Misclassified))
+	}
+	if r.MeanScore[LabelWeak] >= r.MeanScore[LabelMedium] || r.MeanScore[LabelWeak] >= r.MeanScore[LabelStrong] {
+		t.Errorf("weak passwords should score lowest on average, got %v", r.MeanScore)
+	}
+	if r.Confusion[LabelWeak][LabelWeak] < 20 || r.Confusion[LabelStrong][LabelStrong] < 12 {
+		t.Errorf("most weak and strong samples should be classified as such, got %v", r.Confusion)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0)
+	r, err := v.Evaluate(strings.NewReader("# comment\nweak\tpassword\n\nstrong\tXk9$mP2!vLq#7\nstrong\tletmein\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Total != 3 || r.Correct != 2 || r.Accuracy < 0.66 || len(r.Misclassified) != 1 || r.Misclassified[0].Password != "letmein" {
+		t.Errorf("unexpected report %+v", r)
+	}
+
+	if _, err := v.Evaluate(strings.NewReader("weak\tpassword\nbogus password\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected a line 2 error, got %v", err)
+	}
+}
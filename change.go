@@ -0,0 +1,154 @@
+package passval
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// defaultChangeDistance is the minimum edit distance between an old and a new
+// password required by ValidateChange unless set with WithMinChangeDistance.
+const defaultChangeDistance = 3
+
+// WithMinChangeDistance sets how many characters ValidateChange requires a new
+// password to change, add or remove from the old one (the case-insensitive
+// edit distance). Changing a single character or only the numbers always
+// fails, whatever n.
+func WithMinChangeDistance(n int) Option {
+	return func(v *PasswordValidator) {
+		v.changeDistance = max(n, 0)
+	}
+}
+
+// ValidateChange validates newPassword for a password change: it must pass
+// the policy like ValidateResult and must not be derived from oldPassword by
+// a few edits (see WithMinChangeDistance), by reversing it, or by changing
+// only its numbers. Incrementing a number of the old password ("Winter2024!"
+// after "Winter2023!", "Passw0rd8" after "Passw0rd7") fails with code
+// RuleIncremented, other derived passwords with code RuleTooSimilar; the
+// message does not repeat either password.
+func (v *PasswordValidator) ValidateChange(oldPassword, newPassword string) *Result {
+	a := v.analyze(newPassword)
+	r := v.validateAnalysis(a, false)
+	if f, similar := v.changeProblem(strings.ToLower(oldPassword), a.lower); similar {
+		v.addRuleFail(r, f)
+	}
+	v.report(r)
+	return r
+}
+
+// changeProblem returns the failed rule describing how the new password (to)
+// derives from the old one (from), both lowercased, and false if the change
+// is sufficient.
+func (v *PasswordValidator) changeProblem(from, to string) (RuleFail, bool) {
+	if from == to {
+		return newRuleFail(RuleTooSimilar, "new password is the same as the old password"), true
+	}
+	if to == reverseString(from) {
+		return newRuleFail(RuleTooSimilar, "new password is the old password reversed"), true
+	}
+	if digitSkeleton(to) == digitSkeleton(from) {
+		if isIncrement(from, to) {
+			return newRuleFail(RuleIncremented, "new password increments a number of the old password"), true
+		}
+		return newRuleFail(RuleTooSimilar, "new password only changes the numbers of the old password"), true
+	}
+
+	oldRunes, newRunes := []rune(from), []rune(to)
+	if v.maxAnalyzed > 0 {
+		oldRunes, newRunes = oldRunes[:min(len(oldRunes), v.maxAnalyzed)], newRunes[:min(len(newRunes), v.maxAnalyzed)]
+	}
+	switch d := boundedEditDistance(oldRunes, newRunes, max(v.changeDistance, 2)); {
+	case d == 1:
+		return newRuleFail(RuleTooSimilar, "new password changes a single character of the old password", "min", v.changeDistance, "actual", d), true
+	case d < v.changeDistance:
+		return newRuleFail(RuleTooSimilar, fmt.Sprintf("new password differs from the old password in %d characters, minimum %d", d, v.changeDistance),
+			"min", v.changeDistance, "actual", d), true
+	}
+	return RuleFail{}, false
+}
+
+// maxIncrement is the largest step between the numbers of an old and a new
+// password reported as an increment, e.g. a year skipped or a counter bumped
+// a few times.
+const maxIncrement = 10
+
+// isIncrement reports whether to, which has the same digit skeleton as from,
+// raises exactly one of its numbers by 1 to maxIncrement.
+func isIncrement(from, to string) bool {
+	a, b := digitRuns(from), digitRuns(to)
+	changed := -1
+	for i := range a {
+		if a[i] != b[i] {
+			if changed >= 0 {
+				return false
+			}
+			changed = i
+		}
+	}
+	if changed < 0 {
+		return false
+	}
+	x, err1 := strconv.ParseUint(a[changed], 10, 64)
+	y, err2 := strconv.ParseUint(b[changed], 10, 64)
+	return err1 == nil && err2 == nil && y > x && y-x <= maxIncrement
+}
+
+// digitRuns returns the runs of ASCII digits in s.
+func digitRuns(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool { return !isASCIIDigit(r) })
+}
+
+// digitSkeleton replaces every run of ASCII digits in s with a single '#', so
+// that passwords differing only in their numbers have the same skeleton.
+func digitSkeleton(s string) string {
+	var b strings.Builder
+	inDigits := false
+	for _, r := range s {
+		if isASCIIDigit(r) {
+			if !inDigits {
+				b.WriteByte('#')
+			}
+			inDigits = true
+			continue
+		}
+		inDigits = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// boundedEditDistance returns the Levenshtein distance between a and b, or
+// limit if it is at least limit. Only the band of cells within limit of the
+// diagonal is computed, so long inputs cost O(len(a) * limit).
+func boundedEditDistance(a, b []rune, limit int) int {
+	if limit <= 0 {
+		return 0
+	}
+	if len(a)-len(b) >= limit || len(b)-len(a) >= limit {
+		return limit
+	}
+	prev, cur := make([]int, len(b)+1), make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = min(j, limit)
+	}
+	for i := 1; i <= len(a); i++ {
+		lo, hi := max(1, i-limit), min(len(b), i+limit)
+		cur[lo-1] = limit
+		if lo == 1 {
+			cur[0] = min(i, limit)
+		}
+		for j := lo; j <= hi; j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost, limit)
+		}
+		if hi < len(b) {
+			cur[hi+1] = limit
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,77 @@
+package passval
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateChange(t *testing.T) {
+	v := NewPasswordValidator(10, 64, true, true, true, true, 40)
+	const old = "Tr4vel!Lagoon"
+
+	tests := []struct {
+		name, old, new string
+		code           string
+		msg            string
+	}{
+		{"unchanged", old, old, RuleTooSimilar, "same as the old password"},
+		{"case only", old, "tR4VEL!lAGOON", RuleTooSimilar, "same as the old password"},
+		{"reversed", old, "noogaL!lev4rT", RuleTooSimilar, "reversed"},
+		{"number lowered", old, "Tr3vel!Lagoon", RuleTooSimilar, "only changes the numbers"},
+		{"suffix", old, "Tr4vel!Lagoon#9", RuleTooSimilar, "differs from the old password in 2 characters, minimum 3"},
+		{"year", "Winter2023!Frost", "Winter2024!Frost", RuleIncremented, "increments a number"},
+		{"counter", "Passw0rd7!Grove", "Passw0rd8!Grove", RuleIncremented, "increments a number"},
+		{"new password", old, "Qu1et#Harbor&Fern", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := v.ValidateChange(tt.old, tt.new)
+			for _, code := range []string{RuleTooSimilar, RuleIncremented} {
+				if got := slices.Contains(r.Codes(), code); got != (code == tt.code) {
+					t.Fatalf("Codes() = %v, want %q", r.Codes(), tt.code)
+				}
+			}
+			if tt.code != "" && !strings.Contains(strings.Join(r.FailMessages(), "; "), tt.msg) {
+				t.Errorf("RuleFails = %v, want %q", r.RuleFails, tt.msg)
+			}
+			if tt.code == "" && !r.Pass {
+				t.Errorf("ValidateChange = %+v, want a pass", r)
+			}
+		})
+	}
+
+	if r := v.ValidateChange(old, "short"); !slices.Contains(r.Codes(), RuleTooShort) {
+		t.Errorf("Codes() = %v, want the policy applied to the new password", r.Codes())
+	}
+	lenient := v.Clone(WithMinChangeDistance(1))
+	if r := lenient.ValidateChange(old, "Tr4vel!Lagoon#9"); slices.Contains(r.Codes(), RuleTooSimilar) {
+		t.Errorf("Codes() = %v, want no %s with distance 1", r.Codes(), RuleTooSimilar)
+	}
+	if r := lenient.ValidateChange(old, "Tr4vel!Lagoom"); !slices.Contains(r.Codes(), RuleTooSimilar) {
+		t.Errorf("Codes() = %v, want a single-character change to fail with distance 1", r.Codes())
+	}
+	if p := lenient.Policy(); p.MinChangeDistance != 1 {
+		t.Errorf("Policy().MinChangeDistance = %d, want 1", p.MinChangeDistance)
+	}
+}
+
+func TestBoundedEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"kitten", "sitting", 5, 3},
+		{"kitten", "sitting", 2, 2},
+		{"", "abc", 5, 3},
+		{"abc", "abc", 3, 0},
+		{"abcdef", "a", 3, 3},
+		{strings.Repeat("ab", 500), strings.Repeat("ba", 500), 4, 2},
+	}
+	for _, tt := range tests {
+		if got := boundedEditDistance([]rune(tt.a), []rune(tt.b), tt.limit); got != tt.want {
+			t.Errorf("boundedEditDistance(%.10q, %.10q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	passval "github.com/fernandezvara/passvalidator"
+)
+
+func runAudit(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var pf policyFlags
+	pf.register(fs)
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	records := fs.String("records", "", "print one record per password instead, as csv or jsonl")
+	hashKey := fs.String("hash-key", "", "add an HMAC-SHA256 of each password to records, with this key")
+	plaintext := fs.Bool("plaintext", false, "include passwords in records")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	var rw *passval.ReportWriter
+	switch *records {
+	case "":
+	case "csv":
+		rw = passval.NewCSVReportWriter(stdout)
+	case "jsonl":
+		rw = passval.NewJSONLReportWriter(stdout)
+	default:
+		fmt.Fprintf(stderr, "passval: unknown records format %q\n", *records)
+		return exitUsage
+	}
+	if rw != nil {
+		if *hashKey != "" {
+			rw.HashKey = []byte(*hashKey)
+		}
+		rw.Plaintext = *plaintext
+	}
+
+	v, err := pf.validator()
+	if err != nil {
+		fmt.Fprintf(stderr, "passval: %v\n", err)
+		return exitUsage
+	}
+
+	in := stdin
+	if fs.NArg() > 0 {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(stderr, "passval: %v\n", err)
+			return exitUsage
+		}
+		defer f.Close()
+		in = f
+	}
+
+	report, err := passval.NewAuditor(v).AuditTo(in, rw)
+	if err != nil {
+		fmt.Fprintf(stderr, "passval: %v\n", err)
+		return exitFail
+	}
+	if rw != nil {
+		return exitOK
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+		return exitOK
+	}
+	writeAuditReport(stdout, report)
+	return exitOK
+}
+
+// writeAuditReport prints a human-readable audit summary.
+func writeAuditReport(w io.Writer, r *passval.AuditReport) {
+	fmt.Fprintf(w, "Passwords:  %d\n", r.Total)
+	fmt.Fprintf(w, "Passed:     %d (%.1f%%)\n", r.Passed, r.PassRate*100)
+
+	fmt.Fprintln(w, "\nScore distribution:")
+	for i, n := range r.ScoreHistogram {
+		hi := i*10 + 9
+		if i == 9 {
+			hi = 100
+		}
+		fmt.Fprintf(w, "  %3d-%-3d  %d\n", i*10, hi, n)
+	}
+
+	if len(r.TopReasons) > 0 {
+		fmt.Fprintln(w, "\nTop reasons:")
+		for _, e := range r.TopReasons {
+			fmt.Fprintf(w, "  %-24s %d\n", e.Key, e.Count)
+		}
+	}
+	if len(r.TopDictionaryHits) > 0 {
+		fmt.Fprintln(w, "\nTop dictionary hits:")
+		for _, e := range r.TopDictionaryHits {
+			fmt.Fprintf(w, "  %-24s %d\n", e.Key, e.Count)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	passval "github.com/fernandezvara/passvalidator"
+)
+
+func runGenerate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var pf policyFlags
+	pf.register(fs)
+	count := fs.Int("count", 1, "number of passwords to generate")
+	length := fs.Int("length", 0, "exact length (overrides -min and -max)")
+	passphrase := fs.Bool("passphrase", false, "generate passphrases instead of passwords")
+	words := fs.Int("words", 5, "number of words per passphrase")
+	sep := fs.String("sep", "-", "passphrase word separator")
+	noPenalties := fs.Bool("no-penalties", false, "reject generated passwords with any penalized pattern (sequences, dictionary words, ...)")
+	template := fs.String("template", "", `generate from a template, e.g. "Cvccvc-99-##" (c/C consonant, v/V vowel, a/A letter, 9 digit, # symbol, * any)`)
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if *length > 0 {
+		pf.min, pf.max = *length, *length
+	}
+
+	v, err := pf.validator()
+	if err != nil {
+		fmt.Fprintf(stderr, "passval: %v\n", err)
+		return exitUsage
+	}
+	if *noPenalties {
+		v = v.Clone(passval.WithGenerateNoPenalties())
+	}
+
+	for i := 0; i < *count; i++ {
+		var out string
+		switch {
+		case *passphrase:
+			out, _, err = v.GeneratePassphrase(*words, *sep)
+		case *template != "":
+			out, err = v.GenerateFromTemplate(*template)
+		default:
+			out, err = v.Generate()
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "passval: %v\n", err)
+			return exitFail
+		}
+		fmt.Fprintln(stdout, out)
+	}
+	return exitOK
+}
@@ -0,0 +1,71 @@
+// Command passval validates and generates passwords from the command line,
+// using the same policy engine as the passval library.
+//
+// Usage:
+//
+//	passval validate [policy flags] [-json] [password ...]   (reads stdin lines if no password is given)
+//	passval generate [policy flags] [-count n] [-length n] [-passphrase] [-words n] [-sep s]
+//	passval policy describe|export [policy flags]
+//	passval audit [policy flags] [-json] [-records f] [file] (reads stdin if no file is given)
+//	passval serve [policy flags] [-addr host:port]           (serves a live strength meter demo)
+//
+// validate exits with status 1 if any password fails the policy.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Exit codes.
+const (
+	exitOK    = 0
+	exitFail  = 1
+	exitUsage = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "validate":
+		return runValidate(args[1:], stdin, stdout, stderr)
+	case "generate":
+		return runGenerate(args[1:], stdout, stderr)
+	case "policy":
+		return runPolicy(args[1:], stdout, stderr)
+	case "audit":
+		return runAudit(args[1:], stdin, stdout, stderr)
+	case "serve":
+		return runServe(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return exitOK
+	}
+
+	fmt.Fprintf(stderr, "passval: unknown command %q\n", args[0])
+	usage(stderr)
+	return exitUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage: passval <command> [flags]
+
+Commands:
+  validate   validate passwords given as arguments or one per line on stdin
+  generate   generate passwords or passphrases that satisfy the policy
+  policy     describe or export the configured policy (describe|export)
+  audit      report aggregate statistics for a file of passwords
+  serve      serve the HTTP validation handler with a live strength meter demo page
+
+Run 'passval <command> -h' for the flags of a command.
+`)
+}
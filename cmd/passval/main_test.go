@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	passval "github.com/fernandezvara/passvalidator"
+	"github.com/fernandezvara/passvalidator/passvalhttp"
+)
+
+func runCmd(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestValidateCommand(t *testing.T) {
+	if code, out, _ := runCmd("", "validate", "password"); code != exitFail || !strings.Contains(out, "FAIL") {
+		t.Errorf("weak password: code=%d out=%q", code, out)
+	}
+	if code, out, _ := runCmd("", "validate", "Xk9$mP2!vLq#7"); code != exitOK || !strings.Contains(out, "PASS") {
+		t.Errorf("strong password: code=%d out=%q", code, out)
+	}
+
+	code, out, _ := runCmd("Xk9$mP2!vLq#7\nqwerty\n", "validate", "-json")
+	if code != exitFail {
+		t.Errorf("expected exit %d when one stdin password fails, got %d", exitFail, code)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 JSON lines, got %q", out)
+	}
+	var res validateOutput
+	if err := json.Unmarshal([]byte(lines[1]), &res); err != nil || res.Pass {
+		t.Errorf("unexpected JSON result %q: %v", lines[1], err)
+	}
+}
+
+func TestGenerateCommand(t *testing.T) {
+	code, out, errOut := runCmd("", "generate", "-count", "3", "-length", "16")
+	if code != exitOK {
+		t.Fatalf("generate failed: %s", errOut)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 passwords, got %q", out)
+	}
+	for _, l := range lines {
+		if len(l) != 16 {
+			t.Errorf("expected 16 characters, got %q", l)
+		}
+	}
+
+	code, out, _ = runCmd("", "generate", "-passphrase", "-words", "4", "-sep", " ", "-upper=false", "-numbers=false", "-symbols=false")
+	if code != exitOK || len(strings.Fields(out)) != 4 {
+		t.Errorf("expected a 4-word passphrase, got code=%d out=%q", code, out)
+	}
+
+	// Passphrases follow the policy flags.
+	code, out, _ = runCmd("", "generate", "-passphrase", "-words", "6", "-min", "30", "-count", "5")
+	if code != exitOK {
+		t.Fatalf("generate -passphrase failed with code %d", code)
+	}
+	for _, l := range strings.Split(strings.TrimSpace(out), "\n") {
+		if len(l) < 30 || !strings.ContainsAny(l, "0123456789") || strings.ToLower(l) == l {
+			t.Errorf("passphrase %q does not satisfy the policy flags", l)
+		}
+	}
+}
+
+func TestPolicyCommand(t *testing.T) {
+	code, out, _ := runCmd("", "policy", "export", "-min", "12", "-symbols=false")
+	if code != exitOK {
+		t.Fatalf("policy export failed with code %d", code)
+	}
+	var p passval.Policy
+	if err := json.Unmarshal([]byte(out), &p); err != nil {
+		t.Fatalf("invalid policy JSON: %v", err)
+	}
+	if p.MinLength != 12 || p.RequireSymbols {
+		t.Errorf("unexpected policy: %+v", p)
+	}
+
+	if code, out, _ := runCmd("", "policy", "describe"); code != exitOK || !strings.Contains(out, "Length:") {
+		t.Errorf("policy describe: code=%d out=%q", code, out)
+	}
+	if code, _, errOut := runCmd("", "policy", "describe", "-min", "20", "-max", "10"); code != exitUsage || !strings.Contains(errOut, "below minimum length") {
+		t.Errorf("contradictory lengths: code=%d stderr=%q", code, errOut)
+	}
+	if code, out, _ := runCmd("", "policy", "describe", "-strength-levels", "weak:0,fair:30,strong:60"); code != exitOK || !strings.Contains(out, "weak 0+, fair 30+, strong 60+") {
+		t.Errorf("strength levels: code=%d out=%q", code, out)
+	}
+	if code, _, errOut := runCmd("", "policy", "describe", "-strength-levels", "weak"); code != exitUsage || !strings.Contains(errOut, "label:min-score") {
+		t.Errorf("malformed strength levels: code=%d stderr=%q", code, errOut)
+	}
+	if code, _, _ := runCmd("", "bogus"); code != exitUsage {
+		t.Errorf("expected usage exit for unknown command, got %d", code)
+	}
+}
+
+func TestAuditCommand(t *testing.T) {
+	code, out, _ := runCmd("password\nqwerty\nXk9$mP2!vLq#7\n", "audit", "-json")
+	if code != exitOK {
+		t.Fatalf("audit failed with code %d", code)
+	}
+	var report passval.AuditReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid report JSON: %v", err)
+	}
+	if report.Total != 3 || report.Passed != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	if code, out, _ := runCmd("password\n", "audit"); code != exitOK || !strings.Contains(out, "Top reasons:") {
+		t.Errorf("audit table: code=%d out=%q", code, out)
+	}
+}
+
+func TestAuditRecords(t *testing.T) {
+	code, out, _ := runCmd("password\nXk9$mP2!vLq#7\n", "audit", "-records", "csv")
+	if code != exitOK {
+		t.Fatalf("audit -records failed with code %d", code)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || lines[0] != "index,pass,score,codes" || strings.Contains(out, "Xk9$") {
+		t.Errorf("records = %q, want a header and 2 records without passwords", out)
+	}
+
+	if code, _, _ := runCmd("password\n", "audit", "-records", "xml"); code != exitUsage {
+		t.Errorf("unknown format: code=%d, want %d", code, exitUsage)
+	}
+}
+
+func TestServeMux(t *testing.T) {
+	v := passval.NewPasswordValidator(8, 64, true, true, true, true, 50)
+	srv := httptest.NewServer(newServeMux(v))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !bytes.Contains(page, []byte("<title>passval demo</title>")) {
+		t.Errorf("GET / = %d %.60q", resp.StatusCode, page)
+	}
+
+	resp, err = http.Post(srv.URL+"/validate", "application/json", strings.NewReader(`{"password":"password"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res passvalhttp.Response
+	err = json.NewDecoder(resp.Body).Decode(&res)
+	resp.Body.Close()
+	if err != nil || res.Pass || len(res.ReasonCodes) == 0 {
+		t.Errorf("POST /validate = %+v (%v), want a failure with reason codes", res, err)
+	}
+
+	resp, err = http.Get(srv.URL + "/policy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p passval.ClientPolicy
+	err = json.NewDecoder(resp.Body).Decode(&p)
+	resp.Body.Close()
+	if err != nil || p.MinLength != 8 || len(p.Requirements) == 0 {
+		t.Errorf("GET /policy = %+v (%v)", p, err)
+	}
+
+	resp, err = http.Get(srv.URL + "/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /missing = %d, want 404", resp.StatusCode)
+	}
+}
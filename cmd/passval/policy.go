@@ -0,0 +1,346 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	passval "github.com/fernandezvara/passvalidator"
+)
+
+// policyFlags holds the flags shared by all commands to build a validator.
+type policyFlags struct {
+	min, max       int
+	lower, upper   bool
+	numbers        bool
+	symbols        bool
+	complexity     int
+	strength       string
+	dictPath       string
+	wordlistPath   string
+	allowedSymbols string
+	disallowed     string
+	minClasses     int
+	minUnique      int
+	exemptLength   int
+	tokenBits      float64
+	maxBytes       int
+	asciiOnly      bool
+	printableOnly  bool
+	banned         string
+	siteTerms      string
+	preset         string
+	locale         string
+	topics         string
+	noPenalties    string
+	dictMinMatch   int
+	englishWords   bool
+	languagePath   string
+}
+
+func (p *policyFlags) register(fs *flag.FlagSet) {
+	fs.IntVar(&p.min, "min", 8, "minimum length")
+	fs.IntVar(&p.max, "max", 64, "maximum length (0 = no maximum)")
+	fs.BoolVar(&p.lower, "lower", true, "require a lowercase letter")
+	fs.BoolVar(&p.upper, "upper", true, "require an uppercase letter")
+	fs.BoolVar(&p.numbers, "numbers", true, "require a number")
+	fs.BoolVar(&p.symbols, "symbols", true, "require a symbol")
+	fs.IntVar(&p.complexity, "complexity", 60, "minimum complexity score (0-100)")
+	fs.StringVar(&p.strength, "strength-levels", "", "strength scale as comma-separated label:min-score pairs, e.g. weak:0,fair:30,strong:60,very_strong:80")
+	fs.StringVar(&p.dictPath, "dict", "", "path to a custom dictionary (one password per line)")
+	fs.IntVar(&p.dictMinMatch, "dict-min-match", 0, "shortest dictionary word penalized (0 = 4)")
+	fs.StringVar(&p.wordlistPath, "wordlist", "", "path to a passphrase wordlist (one word per line, diceware format accepted)")
+	fs.StringVar(&p.allowedSymbols, "allowed-symbols", "", "restrict symbols to this set")
+	fs.StringVar(&p.disallowed, "disallowed-chars", "", "reject passwords containing any of these characters")
+	fs.IntVar(&p.minClasses, "min-classes", 0, "require at least n of the 4 character classes")
+	fs.IntVar(&p.minUnique, "min-unique", 0, "require at least n distinct characters")
+	fs.IntVar(&p.exemptLength, "exempt-length", 0, "waive composition rules from this length")
+	fs.Float64Var(&p.tokenBits, "random-token-bits", 0, "waive composition rules for UUIDs and hex tokens of at least this many bits of entropy")
+	fs.IntVar(&p.maxBytes, "max-bytes", 0, "maximum UTF-8 byte length (72 for bcrypt)")
+	fs.BoolVar(&p.asciiOnly, "ascii-only", false, "reject non-ASCII characters")
+	fs.BoolVar(&p.printableOnly, "printable-only", false, "reject control and invisible characters")
+	fs.StringVar(&p.banned, "banned", "", "comma-separated banned substrings")
+	fs.StringVar(&p.preset, "preset", "", "constraints of a target system: "+presetNames())
+	fs.StringVar(&p.siteTerms, "site-terms", "", "comma-separated names of the site or product, rejected in any case or leet form")
+	fs.StringVar(&p.topics, "topics", "", "comma-separated topical wordlists to penalize (football, us_sports, capitals, bands, superheroes) or all")
+	fs.StringVar(&p.noPenalties, "disable-penalties", "", "comma-separated penalties to turn off, e.g. keyboard_pattern,sequential_chars")
+	fs.BoolVar(&p.englishWords, "english-words", false, "penalize common English words, more mildly than common passwords")
+	fs.StringVar(&p.languagePath, "language-words", "", "path to a natural-language wordlist to penalize like -english-words (one word per line)")
+	fs.StringVar(&p.locale, "locale", "", "comma-separated locales of the user base for date detection, e.g. es-AR")
+}
+
+// validator builds the validator described by the flags.
+func (p *policyFlags) validator() (*passval.PasswordValidator, error) {
+	var dict string
+	if p.dictPath != "" {
+		b, err := os.ReadFile(p.dictPath)
+		if err != nil {
+			return nil, err
+		}
+		dict = string(b)
+	}
+
+	var opts []passval.Option
+	wl, err := p.wordlist()
+	if err != nil {
+		return nil, err
+	}
+	if wl != nil {
+		opts = append(opts, passval.WithWordlist(wl))
+	}
+	if p.strength != "" {
+		levels, err := parseStrengthLevels(p.strength)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, passval.WithStrengthLevels(levels...))
+	}
+	if p.allowedSymbols != "" {
+		opts = append(opts, passval.WithAllowedSymbols(p.allowedSymbols))
+	}
+	if p.disallowed != "" {
+		opts = append(opts, passval.WithDisallowedChars(p.disallowed))
+	}
+	if p.minClasses > 0 {
+		opts = append(opts, passval.WithMinCharClasses(p.minClasses))
+	}
+	if p.minUnique > 0 {
+		opts = append(opts, passval.WithMinUniqueChars(p.minUnique))
+	}
+	if p.exemptLength > 0 {
+		opts = append(opts, passval.WithLengthExemption(p.exemptLength))
+	}
+	if p.tokenBits > 0 {
+		opts = append(opts, passval.WithRandomTokenExemption(p.tokenBits))
+	}
+	if p.maxBytes > 0 {
+		opts = append(opts, passval.WithMaxBytes(p.maxBytes))
+	}
+	if p.asciiOnly {
+		opts = append(opts, passval.WithASCIIOnly())
+	}
+	if p.printableOnly {
+		opts = append(opts, passval.WithPrintableOnly())
+	}
+	if p.banned != "" {
+		opts = append(opts, passval.WithBannedSubstrings(strings.Split(p.banned, ",")...))
+	}
+	if p.preset != "" {
+		opts = append(opts, passval.WithPreset(passval.Preset(p.preset)))
+	}
+	if p.siteTerms != "" {
+		opts = append(opts, passval.WithSiteTerms(strings.Split(p.siteTerms, ",")...))
+	}
+	switch p.topics {
+	case "":
+	case "all":
+		opts = append(opts, passval.WithTopicalWordlists())
+	default:
+		var topics []passval.Topic
+		for _, t := range strings.Split(p.topics, ",") {
+			topics = append(topics, passval.Topic(t))
+		}
+		opts = append(opts, passval.WithTopicalWordlists(topics...))
+	}
+	if p.dictMinMatch != 0 {
+		opts = append(opts, passval.WithDictMinMatchLen(p.dictMinMatch))
+	}
+	if p.englishWords {
+		opts = append(opts, passval.WithEnglishWords())
+	}
+	if p.languagePath != "" {
+		b, err := os.ReadFile(p.languagePath)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, passval.WithLanguageWords(strings.Split(string(b), "\n")...))
+	}
+	if p.noPenalties != "" {
+		opts = append(opts, passval.WithDisabledPenalties(strings.Split(p.noPenalties, ",")...))
+	}
+	if p.locale != "" {
+		opts = append(opts, passval.WithLocaleHints(strings.Split(p.locale, ",")...))
+	}
+
+	v, err := passval.NewValidatorStrict(p.min, p.max, p.lower, p.upper, p.numbers, p.symbols, p.complexity, opts...)
+	if err != nil {
+		return nil, err
+	}
+	v.SetDictionary(dict)
+	return v, nil
+}
+
+// presetNames lists the names -preset accepts.
+func presetNames() string {
+	var names []string
+	for _, p := range passval.Presets() {
+		names = append(names, string(p))
+	}
+	return strings.Join(names, ", ")
+}
+
+// parseStrengthLevels parses the -strength-levels flag.
+func parseStrengthLevels(s string) ([]passval.StrengthLevel, error) {
+	var levels []passval.StrengthLevel
+	for _, pair := range strings.Split(s, ",") {
+		label, score, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		n, err := strconv.Atoi(score)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("strength level %q: want label:min-score", pair)
+		}
+		levels = append(levels, passval.StrengthLevel{Label: label, MinScore: n})
+	}
+	return levels, nil
+}
+
+// wordlist loads the -wordlist file, or returns nil without one.
+func (p *policyFlags) wordlist() (passval.Wordlist, error) {
+	if p.wordlistPath == "" {
+		return nil, nil
+	}
+	f, err := os.Open(p.wordlistPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return passval.LoadWordlist(f)
+}
+
+func runPolicy(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || (args[0] != "describe" && args[0] != "export") {
+		fmt.Fprintln(stderr, "usage: passval policy describe|export [policy flags]")
+		return exitUsage
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("policy "+action, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var pf policyFlags
+	pf.register(fs)
+	if err := fs.Parse(args[1:]); err != nil {
+		return exitUsage
+	}
+
+	v, err := pf.validator()
+	if err != nil {
+		fmt.Fprintf(stderr, "passval: %v\n", err)
+		return exitUsage
+	}
+	policy := v.Policy()
+
+	if action == "export" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(policy); err != nil {
+			fmt.Fprintf(stderr, "passval: %v\n", err)
+			return exitFail
+		}
+		return exitOK
+	}
+
+	describePolicy(stdout, policy)
+	return exitOK
+}
+
+// describePolicy writes a human-readable summary of the policy.
+func describePolicy(w io.Writer, p passval.Policy) {
+	if p.MaxLength == passval.NoMax {
+		fmt.Fprintf(w, "Length:      at least %d characters\n", p.MinLength)
+	} else {
+		fmt.Fprintf(w, "Length:      %d-%d characters\n", p.MinLength, p.MaxLength)
+	}
+	var required []string
+	if p.RequireLower {
+		required = append(required, "lowercase")
+	}
+	if p.RequireUpper {
+		required = append(required, "uppercase")
+	}
+	if p.RequireNumbers {
+		required = append(required, "number")
+	}
+	if p.RequireSymbols {
+		required = append(required, "symbol")
+	}
+	if len(required) == 0 {
+		required = append(required, "none")
+	}
+	fmt.Fprintf(w, "Required:    %s\n", strings.Join(required, ", "))
+	fmt.Fprintf(w, "Complexity:  %d/100 minimum\n", p.Complexity)
+	if len(p.StrengthLevels) > 0 {
+		levels := make([]string, len(p.StrengthLevels))
+		for i, l := range p.StrengthLevels {
+			levels[i] = l.String()
+		}
+		fmt.Fprintf(w, "Strength:    %s\n", strings.Join(levels, ", "))
+	}
+	if p.MinCharClasses > 0 {
+		fmt.Fprintf(w, "Classes:     at least %d of 4\n", p.MinCharClasses)
+	}
+	if p.MinUniqueChars > 0 {
+		fmt.Fprintf(w, "Unique:      at least %d distinct characters\n", p.MinUniqueChars)
+	}
+	if p.ExemptLength > 0 {
+		fmt.Fprintf(w, "Exemption:   composition rules waived from %d characters\n", p.ExemptLength)
+	}
+	if p.RandomTokenBits > 0 {
+		fmt.Fprintf(w, "Tokens:      composition rules waived for UUIDs and hex tokens of %.0f+ bits\n", p.RandomTokenBits)
+	}
+	if p.MaxBytes > 0 {
+		fmt.Fprintf(w, "Max bytes:   %d\n", p.MaxBytes)
+	}
+	if p.ASCIIOnly || p.PrintableOnly {
+		var only []string
+		if p.ASCIIOnly {
+			only = append(only, "ASCII")
+		}
+		if p.PrintableOnly {
+			only = append(only, "printable")
+		}
+		fmt.Fprintf(w, "Characters:  %s only\n", strings.Join(only, ", "))
+	}
+	if p.AllowedSymbols != "" {
+		fmt.Fprintf(w, "Symbols:     %s\n", p.AllowedSymbols)
+	}
+	if p.DisallowedChars != "" {
+		fmt.Fprintf(w, "Forbidden:   %q\n", p.DisallowedChars)
+	}
+	if len(p.BannedSubstrings) > 0 {
+		fmt.Fprintf(w, "Banned:      %s\n", strings.Join(p.BannedSubstrings, ", "))
+	}
+	if len(p.Presets) > 0 {
+		presets := make([]string, len(p.Presets))
+		for i, preset := range p.Presets {
+			presets[i] = string(preset)
+		}
+		fmt.Fprintf(w, "Presets:     %s\n", strings.Join(presets, ", "))
+	}
+	if len(p.SiteTerms) > 0 {
+		fmt.Fprintf(w, "Site names:  %s\n", strings.Join(p.SiteTerms, ", "))
+	}
+	if len(p.Topics) > 0 {
+		fmt.Fprintf(w, "Topics:      %s\n", strings.Join(p.Topics, ", "))
+	}
+	if p.DictMinMatchLen > 0 {
+		fmt.Fprintf(w, "Dict words:  %d+ characters\n", p.DictMinMatchLen)
+	}
+	switch {
+	case p.EnglishWords && p.LanguageWords > 0:
+		fmt.Fprintf(w, "Words:       English and %d more\n", p.LanguageWords)
+	case p.EnglishWords:
+		fmt.Fprintf(w, "Words:       English\n")
+	case p.LanguageWords > 0:
+		fmt.Fprintf(w, "Words:       %d\n", p.LanguageWords)
+	}
+	if len(p.DisabledPenalties) > 0 {
+		fmt.Fprintf(w, "No penalty:  %s\n", strings.Join(p.DisabledPenalties, ", "))
+	}
+	if len(p.LocaleHints) > 0 {
+		fmt.Fprintf(w, "Locales:     %s\n", strings.Join(p.LocaleHints, ", "))
+	}
+	fmt.Fprintf(w, "Entropy:     %s\n", p.EntropyMode)
+}
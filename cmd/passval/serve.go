@@ -0,0 +1,69 @@
+package main
+
+import (
+	_ "embed"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	passval "github.com/fernandezvara/passvalidator"
+	"github.com/fernandezvara/passvalidator/passvalhttp"
+)
+
+// demoPage is the live strength meter served at /.
+//
+//go:embed demo.html
+var demoPage []byte
+
+func runServe(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var pf policyFlags
+	pf.register(fs)
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	v, err := pf.validator()
+	if err != nil {
+		fmt.Fprintf(stderr, "passval: %v\n", err)
+		return exitUsage
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           newServeMux(v),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	fmt.Fprintf(stdout, "passval: serving the demo on http://%s/\n", *addr)
+	if err := srv.ListenAndServe(); err != nil {
+		fmt.Fprintf(stderr, "passval: %v\n", err)
+		return exitFail
+	}
+	return exitOK
+}
+
+// newServeMux routes the demo: the page at /, the passvalhttp handler at
+// /validate and the client policy, which the page lists as requirements, at
+// /policy.
+func newServeMux(v *passval.PasswordValidator) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(demoPage)
+	})
+	mux.Handle("/validate", passvalhttp.NewHandler(v))
+	mux.HandleFunc("GET /policy", func(w http.ResponseWriter, r *http.Request) {
+		b, err := v.ExportClientPolicy()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(b)
+	})
+	return mux
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	passval "github.com/fernandezvara/passvalidator"
+)
+
+// validateOutput is the JSON form of one validation.
+type validateOutput struct {
+	Password    string            `json:"password"`
+	Pass        bool              `json:"pass"`
+	Score       int               `json:"score"`
+	Strength    string            `json:"strength"`
+	ReasonCodes []string          `json:"reason_codes"`
+	RuleFails   []string          `json:"rule_fails,omitempty"`
+	Penalties   []string          `json:"penalties,omitempty"`
+	Warnings    []passval.Warning `json:"warnings,omitempty"`
+}
+
+func runValidate(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var pf policyFlags
+	pf.register(fs)
+	asJSON := fs.Bool("json", false, "print results as JSON lines")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	v, err := pf.validator()
+	if err != nil {
+		fmt.Fprintf(stderr, "passval: %v\n", err)
+		return exitUsage
+	}
+
+	passwords := fs.Args()
+	if len(passwords) == 0 {
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			passwords = append(passwords, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			fmt.Fprintf(stderr, "passval: reading stdin: %v\n", err)
+			return exitUsage
+		}
+	}
+
+	var outputs []validateOutput
+	allPass := true
+	for _, pwd := range passwords {
+		r := v.ValidateResult(pwd)
+		out := validateOutput{
+			Password:    pwd,
+			Pass:        r.Pass,
+			Score:       r.Score,
+			Strength:    r.Strength,
+			ReasonCodes: r.Codes(),
+			RuleFails:   r.FailMessages(),
+			Warnings:    r.Warnings,
+		}
+		for _, p := range r.Penalties {
+			out.Penalties = append(out.Penalties, fmt.Sprintf("%s x%.2f: %s", p.Rule, p.Factor, p.Desc))
+		}
+		outputs = append(outputs, out)
+		allPass = allPass && r.Pass
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		for _, out := range outputs {
+			enc.Encode(out)
+		}
+	} else {
+		writeTable(stdout, outputs)
+	}
+
+	if !allPass {
+		return exitFail
+	}
+	return exitOK
+}
+
+// writeTable prints one row per password with its outcome and reasons.
+func writeTable(w io.Writer, outputs []validateOutput) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PASSWORD\tRESULT\tSCORE\tSTRENGTH\tREASONS")
+	for _, out := range outputs {
+		result := "FAIL"
+		if out.Pass {
+			result = "PASS"
+		}
+		reasons := strings.Join(out.ReasonCodes, ",")
+		if reasons == "" {
+			reasons = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", out.Password, result, out.Score, out.Strength, reasons)
+	}
+	tw.Flush()
+}
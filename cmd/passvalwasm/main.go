@@ -0,0 +1,202 @@
+//go:build js && wasm
+
+// Command passvalwasm exposes the passval scoring engine to JavaScript, so a
+// browser strength meter runs exactly the same logic as the backend.
+//
+// Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o passval.wasm ./cmd/passvalwasm
+//
+// Add -tags passval_nodict to embed only the 100 most common passwords.
+// Once loaded with wasm_exec.js it defines a global passval object:
+//
+//	passval.configure(policy)  // policy uses the JSON fields of passval.Policy
+//	passval.validate(password) // {pass, score, strength, reason_codes, rule_fails, warnings}
+//	                           // rule_fails: [{code, params, message}]
+//	passval.score(password)    // complexity score 0-100
+package main
+
+import (
+	"encoding/json"
+	"regexp"
+	"syscall/js"
+
+	passval "github.com/fernandezvara/passvalidator"
+)
+
+// v is the validator used by the bindings; configure replaces it. scorer
+// scores the password field as it is typed, reusing the results of its
+// prefixes.
+var (
+	v      = passval.NewPasswordValidator(8, 64, true, true, true, true, 60)
+	scorer = v.NewScorer()
+)
+
+func main() {
+	js.Global().Set("passval", js.ValueOf(map[string]any{
+		"configure": js.FuncOf(configure),
+		"validate":  js.FuncOf(validate),
+		"score":     js.FuncOf(score),
+	}))
+	select {}
+}
+
+// configure builds a new validator from a policy object and returns null, or
+// an error message if the policy is invalid.
+func configure(_ js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return "configure: missing policy"
+	}
+	raw := js.Global().Get("JSON").Call("stringify", args[0]).String()
+	var p passval.Policy
+	if err := json.Unmarshal([]byte(raw), &p); err != nil {
+		return "configure: " + err.Error()
+	}
+	pv, err := fromPolicy(p)
+	if err != nil {
+		return "configure: " + err.Error()
+	}
+	v, scorer = pv, pv.NewScorer()
+	return nil
+}
+
+func validate(_ js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return nil
+	}
+	res := scorer.Update(args[0].String())
+	ruleFails := make([]any, len(res.RuleFails))
+	for i, f := range res.RuleFails {
+		params := make(map[string]any, len(f.Params))
+		for k, p := range f.Params {
+			params[k] = p
+		}
+		ruleFails[i] = map[string]any{"code": f.Code, "params": params, "message": f.Message}
+	}
+	warnings := make([]any, len(res.Warnings))
+	for i, w := range res.Warnings {
+		warnings[i] = map[string]any{"code": w.Code, "message": w.Message}
+	}
+	return map[string]any{
+		"pass":         res.Pass,
+		"score":        res.Score,
+		"strength":     res.Strength,
+		"reason_codes": jsStrings(res.Codes()),
+		"rule_fails":   ruleFails,
+		"warnings":     warnings,
+	}
+}
+
+func score(_ js.Value, args []js.Value) any {
+	if len(args) == 0 {
+		return 0
+	}
+	return scorer.Score(args[0].String())
+}
+
+// fromPolicy builds a validator from the fields of p that apply to local
+// validation.
+func fromPolicy(p passval.Policy) (*passval.PasswordValidator, error) {
+	var opts []passval.Option
+	if p.WarnThreshold > 0 {
+		opts = append(opts, passval.WithWarnThreshold(p.WarnThreshold))
+	}
+	if len(p.StrengthLevels) > 0 {
+		opts = append(opts, passval.WithStrengthLevels(p.StrengthLevels...))
+	}
+	if p.MinLower > 0 || p.MinUpper > 0 || p.MinDigits > 0 || p.MinSymbols > 0 {
+		opts = append(opts, passval.WithMinClassCounts(p.MinLower, p.MinUpper, p.MinDigits, p.MinSymbols))
+	}
+	if p.MinCharClasses > 0 {
+		opts = append(opts, passval.WithMinCharClasses(p.MinCharClasses))
+	}
+	if p.MinUniqueChars > 0 {
+		opts = append(opts, passval.WithMinUniqueChars(p.MinUniqueChars))
+	}
+	if p.ExemptLength > 0 {
+		opts = append(opts, passval.WithLengthExemption(p.ExemptLength))
+	}
+	if p.MaxBytes > 0 {
+		opts = append(opts, passval.WithMaxBytes(p.MaxBytes))
+	}
+	if p.ASCIIOnly {
+		opts = append(opts, passval.WithASCIIOnly())
+	}
+	if p.PrintableOnly {
+		opts = append(opts, passval.WithPrintableOnly())
+	}
+	if p.AllowedSymbols != "" {
+		opts = append(opts, passval.WithAllowedSymbols(p.AllowedSymbols))
+	}
+	if p.DisallowedChars != "" {
+		opts = append(opts, passval.WithDisallowedChars(p.DisallowedChars))
+	}
+	if len(p.BannedSubstrings) > 0 {
+		opts = append(opts, passval.WithBannedSubstrings(p.BannedSubstrings...))
+	}
+	if len(p.DisabledPenalties) > 0 {
+		opts = append(opts, passval.WithDisabledPenalties(p.DisabledPenalties...))
+	}
+	for _, preset := range p.Presets {
+		opts = append(opts, passval.WithPreset(preset))
+	}
+	if len(p.SiteTerms) > 0 {
+		opts = append(opts, passval.WithSiteTerms(p.SiteTerms...))
+	}
+	if len(p.BannedPatterns) > 0 {
+		patterns := make([]*regexp.Regexp, len(p.BannedPatterns))
+		for i, expr := range p.BannedPatterns {
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return nil, err
+			}
+			patterns[i] = re
+		}
+		opts = append(opts, passval.WithBannedPatterns(patterns...))
+	}
+	if len(p.Topics) > 0 {
+		topics := make([]passval.Topic, len(p.Topics))
+		for i, t := range p.Topics {
+			topics[i] = passval.Topic(t)
+		}
+		opts = append(opts, passval.WithTopicalWordlists(topics...))
+	}
+	if len(p.LocaleHints) > 0 {
+		opts = append(opts, passval.WithLocaleHints(p.LocaleHints...))
+	}
+	if p.PassphraseMinWords > 0 {
+		opts = append(opts, passval.WithPassphrasePolicy(p.PassphraseMinWords, p.PassphraseMinWordLen))
+	}
+	if p.RandomTokenBits > 0 {
+		opts = append(opts, passval.WithRandomTokenExemption(p.RandomTokenBits))
+	}
+	if p.MinGuesses > 0 {
+		opts = append(opts, passval.WithMinGuesses(p.MinGuesses))
+	}
+	if p.EnglishWords {
+		opts = append(opts, passval.WithEnglishWords())
+	}
+	if p.DictMinMatchLen > 0 {
+		opts = append(opts, passval.WithDictMinMatchLen(p.DictMinMatchLen))
+	}
+	if p.MaxAnalyzedRunes > 0 || p.MaxDictScanWords > 0 {
+		opts = append(opts, passval.WithAnalysisLimits(p.MaxAnalyzedRunes, p.MaxDictScanWords))
+	}
+	if p.MinChangeDistance > 0 {
+		opts = append(opts, passval.WithMinChangeDistance(p.MinChangeDistance))
+	}
+	if p.EntropyMode == passval.EntropyShannon.String() {
+		opts = append(opts, passval.WithEntropyMode(passval.EntropyShannon))
+	}
+	return passval.NewValidatorStrict(p.MinLength, p.MaxLength, p.RequireLower, p.RequireUpper,
+		p.RequireNumbers, p.RequireSymbols, p.Complexity, opts...)
+}
+
+// jsStrings converts ss to a slice js.ValueOf accepts.
+func jsStrings(ss []string) []any {
+	out := make([]any, len(ss))
+	for i, s := range ss {
+		out[i] = s
+	}
+	return out
+}
@@ -0,0 +1,88 @@
+package passval
+
+// CompiledPolicy is a frozen snapshot of a validator with its derived
+// structures built up front, for request paths that should pay no setup
+// cost. It is safe for concurrent use, and later changes to the validator it
+// was compiled from, such as SetDictionary, do not affect it.
+type CompiledPolicy struct {
+	v *PasswordValidator
+}
+
+var _ Validator = (*CompiledPolicy)(nil)
+
+// Compile builds, once, the structures validation derives from the policy:
+// the banned substrings are merged into a single trie matched in one pass
+// over the password, instead of one scan per term, and the current
+// dictionary is pinned. Keyboard adjacency rows and banned patterns are
+// prepared when the package loads and when options are applied.
+func (v *PasswordValidator) Compile() *CompiledPolicy {
+	c := v.Clone()
+	if len(c.bannedTerms) > 0 {
+		c.bannedIndex = newBannedIndex(c.bannedTerms)
+	}
+	return &CompiledPolicy{v: c}
+}
+
+// Validate is PasswordValidator.Validate for the compiled policy.
+func (p *CompiledPolicy) Validate(password string) (bool, int) {
+	return p.v.Validate(password)
+}
+
+// ValidateVerbose is PasswordValidator.ValidateVerbose for the compiled policy.
+func (p *CompiledPolicy) ValidateVerbose(password string) (bool, int, error) {
+	return p.v.ValidateVerbose(password)
+}
+
+// ValidateResult is PasswordValidator.ValidateResult for the compiled policy.
+func (p *CompiledPolicy) ValidateResult(password string) *Result {
+	return p.v.ValidateResult(password)
+}
+
+// ValidateBytes is PasswordValidator.ValidateBytes for the compiled policy.
+func (p *CompiledPolicy) ValidateBytes(password []byte) *Result {
+	return p.v.ValidateBytes(password)
+}
+
+// Policy returns a description of the compiled policy.
+func (p *CompiledPolicy) Policy() Policy {
+	return p.v.Policy()
+}
+
+// bannedIndex matches all banned terms, literally and leet-normalized, with
+// one trie.
+type bannedIndex struct {
+	dict  *dictionary
+	terms []bannedTerm
+	first map[string]int // index in terms of the first term with a trie word
+}
+
+func newBannedIndex(terms []bannedTerm) *bannedIndex {
+	idx := &bannedIndex{terms: terms, first: make(map[string]int)}
+	var words []string
+	for i, t := range terms {
+		for _, w := range []string{t.term, t.normalized} {
+			if _, ok := idx.first[w]; !ok && w != "" {
+				idx.first[w] = i
+				words = append(words, w)
+			}
+		}
+	}
+	idx.dict = newDictionary(words)
+	return idx
+}
+
+// match returns the first term, in the order the terms were given, contained
+// in lower, literally or through the substitutions in table, or "". It
+// returns what matchBannedTerm returns for the same terms.
+func (idx *bannedIndex) match(lower string, table leetTable) string {
+	best := -1
+	for _, m := range idx.dict.leetMatches(lower, 1, table) {
+		if i := idx.first[m.word]; best < 0 || i < best {
+			best = i
+		}
+	}
+	if best < 0 {
+		return ""
+	}
+	return idx.terms[best].term
+}
@@ -0,0 +1,56 @@
+package passval
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 40,
+		WithBannedSubstrings("Acme", "rocket", "acm3corp"))
+	c := v.Compile()
+
+	for _, pw := range []string{
+		"Tr0ub4dor&3xyz", "AcmeRocks!2024", "I<3R0ck3t$!!", "@CM3corp!Zx9", "qwerty123", "Xq7#zR2mK9w",
+	} {
+		want, got := v.ValidateResult(pw), c.ValidateResult(pw)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Compile().ValidateResult(%q) = %+v, want %+v", pw, got, want)
+		}
+	}
+
+	// The first term in the given order wins, as without compiling.
+	if _, _, err := c.ValidateVerbose("x@cm3corp!Z9"); err == nil || !strings.Contains(err.Error(), "'acme'") {
+		t.Errorf("ValidateVerbose err = %v, want banned term 'acme'", err)
+	}
+
+	// The compiled policy keeps the dictionary it was compiled with.
+	v.SetDictionary("xq7#zr2mk9w")
+	if r := c.ValidateResult("Xq7#zR2mK9w"); hasPenalty(r.Penalties, "common_password") {
+		t.Errorf("compiled policy picked up SetDictionary: %+v", r.Penalties)
+	}
+	if !reflect.DeepEqual(c.Policy(), v.Policy()) {
+		t.Errorf("Policy() = %+v, want %+v", c.Policy(), v.Policy())
+	}
+}
+
+func BenchmarkBannedSubstrings(b *testing.B) {
+	terms := make([]string, 500)
+	for i := range terms {
+		terms[i] = fmt.Sprintf("brand%03dname", i)
+	}
+	v := NewPasswordValidator(8, 64, true, true, true, true, 50, WithBannedSubstrings(terms...))
+	b.Run("validator", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			v.Validate("MyP@ssw0rd!23")
+		}
+	})
+	c := v.Compile()
+	b.Run("compiled", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			c.Validate("MyP@ssw0rd!23")
+		}
+	})
+}
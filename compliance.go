@@ -0,0 +1,130 @@
+package passval
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Standard identifies a password guideline that CheckCompliance audits a
+// policy against.
+type Standard int
+
+const (
+	// StandardNIST80063B is NIST SP 800-63B, section 5.1.1 (memorized secrets).
+	StandardNIST80063B Standard = iota
+	// StandardOWASPASVSL2 is OWASP ASVS 4.0, chapter V2.1, at level 2.
+	StandardOWASPASVSL2
+	// StandardPCIDSS is PCI DSS 4.0, requirement 8.3.6.
+	StandardPCIDSS
+)
+
+// String returns the name of the standard.
+func (s Standard) String() string {
+	switch s {
+	case StandardNIST80063B:
+		return "NIST SP 800-63B"
+	case StandardOWASPASVSL2:
+		return "OWASP ASVS L2"
+	case StandardPCIDSS:
+		return "PCI DSS 4.0"
+	}
+	return fmt.Sprintf("Standard(%d)", int(s))
+}
+
+// Finding is a requirement of a standard that a policy falls short of.
+type Finding struct {
+	Requirement string `json:"requirement"` // section of the standard, e.g. "5.1.1.2"
+	Message     string `json:"message"`     // e.g. "maximum length 32 is below 64"
+}
+
+// CheckCompliance inspects the configuration of v and reports where it falls
+// short of standard, for audit evidence. An empty result means no shortfall
+// was found among the requirements a policy can express; organisational
+// controls (rate limiting, password history, storage) are not covered.
+func CheckCompliance(v *PasswordValidator, standard Standard) ([]Finding, error) {
+	if v == nil {
+		return nil, errors.New("nil validator")
+	}
+
+	var findings []Finding
+	add := func(req, format string, args ...any) {
+		findings = append(findings, Finding{Requirement: req, Message: fmt.Sprintf(format, args...)})
+	}
+	minLength := v.MinLength
+	if v.warnOnly[RuleTooShort] {
+		minLength = 0
+	}
+
+	switch standard {
+	case StandardNIST80063B:
+		if minLength < 8 {
+			add("5.1.1.2", "minimum length %d is below 8", minLength)
+		}
+		if v.MaxLength != NoMax && v.MaxLength < 64 {
+			add("5.1.1.2", "maximum length %d is below 64", v.MaxLength)
+		}
+		if v.hasCompositionRules() {
+			add("5.1.1.2", "composition rules (required character classes) are imposed")
+		}
+		if v.allowedSymbols != "" {
+			add("5.1.1.2", "symbols are restricted to %q; all printable characters should be accepted", v.allowedSymbols)
+		}
+		if v.forbiddenChars != "" {
+			add("5.1.1.2", "characters %q are rejected; all printable characters should be accepted", v.forbiddenChars)
+		}
+		if v.asciiOnly {
+			add("5.1.1.2", "non-ASCII characters are rejected; Unicode characters should be accepted")
+		}
+		if v.breachChecker == nil {
+			add("5.1.1.2", "no breached-password check configured")
+		}
+		if v.skipPenalties["common_password"] {
+			add("5.1.1.2", "the common password check is disabled")
+		}
+		if len(v.bannedTerms) == 0 && len(v.siteTerms) == 0 {
+			add("5.1.1.2", "no context-specific words (e.g. the service name) are banned")
+		}
+
+	case StandardOWASPASVSL2:
+		if minLength < 12 {
+			add("2.1.1", "minimum length %d is below 12", minLength)
+		}
+		switch {
+		case v.MaxLength == NoMax:
+			add("2.1.2", "no maximum length; passwords above 128 characters are accepted")
+		case v.MaxLength < 64:
+			add("2.1.2", "maximum length %d is below 64", v.MaxLength)
+		case v.MaxLength > 128:
+			add("2.1.2", "maximum length %d is above 128", v.MaxLength)
+		}
+		if v.breachChecker == nil {
+			add("2.1.7", "no breached-password check configured")
+		}
+		if v.hasCompositionRules() {
+			add("2.1.9", "composition rules (required character classes) are imposed")
+		}
+
+	case StandardPCIDSS:
+		if minLength < 12 {
+			add("8.3.6", "minimum length %d is below 12", minLength)
+		}
+		letters := minClassCount(v.MinLower, v.RequireLower) > 0 || minClassCount(v.MinUpper, v.RequireUpper) > 0
+		if !letters || minClassCount(v.MinDigits, v.RequireNumbers) == 0 {
+			add("8.3.6", "letters and numbers are not both required")
+		} else if v.exemptLength > 0 || v.passphraseMinWords > 0 || v.tokenBits > 0 {
+			add("8.3.6", "long passwords, passphrases or random tokens are exempt from the number requirement")
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown standard %v", standard)
+	}
+	return findings, nil
+}
+
+// hasCompositionRules reports whether the policy requires characters of
+// particular classes.
+func (v *PasswordValidator) hasCompositionRules() bool {
+	return v.RequireLower || v.RequireUpper || v.RequireNumbers || v.RequireSymbols ||
+		v.MinLower > 0 || v.MinUpper > 0 || v.MinDigits > 0 || v.MinSymbols > 0 ||
+		v.minCharClasses > 0
+}
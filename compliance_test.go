@@ -0,0 +1,75 @@
+package passval
+
+import (
+	"strings"
+	"testing"
+)
+
+func findingMessages(t *testing.T, v *PasswordValidator, s Standard) string {
+	t.Helper()
+	findings, err := CheckCompliance(v, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs []string
+	for _, f := range findings {
+		msgs = append(msgs, f.Requirement+": "+f.Message)
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func TestCheckCompliance(t *testing.T) {
+	legacy := NewPasswordValidator(8, 32, true, true, true, true, 50)
+	modern := NewPasswordValidator(15, 128, false, false, false, false, 50,
+		WithBreachChecker(&fakeBreachChecker{}), WithBannedSubstrings("acme"))
+
+	nist := findingMessages(t, legacy, StandardNIST80063B)
+	for _, want := range []string{"maximum length 32 is below 64", "composition rules", "no breached-password check", "context-specific words"} {
+		if !strings.Contains(nist, want) {
+			t.Errorf("NIST findings missing %q:\n%s", want, nist)
+		}
+	}
+	if got := findingMessages(t, modern, StandardNIST80063B); got != "" {
+		t.Errorf("expected no NIST findings, got:\n%s", got)
+	}
+
+	if got := findingMessages(t, modern.Clone(WithASCIIOnly()), StandardNIST80063B); !strings.Contains(got, "non-ASCII") {
+		t.Errorf("NIST findings should flag ASCII-only policies, got:\n%s", got)
+	}
+
+	asvs := findingMessages(t, legacy, StandardOWASPASVSL2)
+	for _, want := range []string{"2.1.1: minimum length 8 is below 12", "2.1.2", "2.1.7", "2.1.9"} {
+		if !strings.Contains(asvs, want) {
+			t.Errorf("ASVS findings missing %q:\n%s", want, asvs)
+		}
+	}
+	if got := findingMessages(t, modern, StandardOWASPASVSL2); got != "" {
+		t.Errorf("expected no ASVS findings, got:\n%s", got)
+	}
+
+	if got := findingMessages(t, modern, StandardPCIDSS); !strings.Contains(got, "letters and numbers") {
+		t.Errorf("PCI DSS should require letters and numbers, got:\n%s", got)
+	}
+	pci := NewPasswordValidator(12, 64, true, false, true, false, 50)
+	if got := findingMessages(t, pci, StandardPCIDSS); got != "" {
+		t.Errorf("expected no PCI DSS findings, got:\n%s", got)
+	}
+
+	demoted := NewPasswordValidator(12, 64, true, false, true, false, 50, WithWarnOnly(RuleTooShort))
+	if got := findingMessages(t, demoted, StandardPCIDSS); !strings.Contains(got, "minimum length 0") {
+		t.Errorf("a warn-only minimum length should not count, got:\n%s", got)
+	}
+
+	unlimited := NewPasswordValidator(15, NoMax, false, false, false, false, 50,
+		WithBreachChecker(&fakeBreachChecker{}), WithBannedSubstrings("acme"))
+	if got := findingMessages(t, unlimited, StandardNIST80063B); got != "" {
+		t.Errorf("expected no NIST findings without a maximum, got:\n%s", got)
+	}
+	if got := findingMessages(t, unlimited, StandardOWASPASVSL2); !strings.Contains(got, "2.1.2: no maximum length") {
+		t.Errorf("ASVS should flag a missing maximum, got:\n%s", got)
+	}
+
+	if _, err := CheckCompliance(modern, Standard(99)); err == nil {
+		t.Error("expected an error for an unknown standard")
+	}
+}
@@ -0,0 +1,99 @@
+package passval
+
+// All returns a Validator that passes only if every one of validators passes,
+// e.g. a corporate baseline and a tenant's stricter overlay. Its score and
+// entropy are the lowest reported, and failed rules, penalties and warnings
+// are merged without duplicates. With no validators it fails every password,
+// like Any, rather than accepting them unchecked.
+func All(validators ...Validator) Validator {
+	return &composite{validators: validators}
+}
+
+// Any returns a Validator that passes if at least one of validators passes,
+// e.g. a character policy or a passphrase policy. On success it returns the
+// highest-scoring passing result; on failure it merges the results of all
+// validators, with the highest score and entropy reported. With no
+// validators it fails every password.
+func Any(validators ...Validator) Validator {
+	return &composite{validators: validators, any: true}
+}
+
+type composite struct {
+	validators []Validator
+	any        bool
+}
+
+func (c *composite) Validate(password string) (bool, int) {
+	r := c.ValidateResult(password)
+	return r.Pass, r.Score
+}
+
+func (c *composite) ValidateVerbose(password string) (bool, int, error) {
+	r := c.ValidateResult(password)
+	return r.Pass, r.Score, r.Err()
+}
+
+func (c *composite) ValidateResult(password string) *Result {
+	results := make([]*Result, len(c.validators))
+	for i, v := range c.validators {
+		results[i] = v.ValidateResult(password)
+	}
+
+	if c.any {
+		var best *Result
+		for _, r := range results {
+			if r.Pass && (best == nil || r.Score > best.Score) {
+				best = r
+			}
+		}
+		if best != nil {
+			return best
+		}
+	}
+	r := mergeResults(results, c.any)
+	if c.any || len(results) == 0 {
+		r.Pass = false // no validator passed, or there were none
+	}
+	return r
+}
+
+// mergeResults combines results into one, keeping the lowest score and entropy,
+// or the highest when best is set. It passes if every result passes.
+func mergeResults(results []*Result, best bool) *Result {
+	merged := &Result{Pass: true, err: &ValidationError{}}
+	seenFails := make(map[string]bool)
+	seenPenalties := make(map[string]bool)
+	seenWarnings := make(map[Warning]bool)
+
+	for i, r := range results {
+		if i == 0 || (best && r.Score > merged.Score) || (!best && r.Score < merged.Score) {
+			merged.Score, merged.Strength = r.Score, r.Strength
+		}
+		if i == 0 || (best && r.Entropy > merged.Entropy) || (!best && r.Entropy < merged.Entropy) {
+			merged.Entropy = r.Entropy
+		}
+		merged.Pass = merged.Pass && r.Pass
+
+		for _, f := range r.RuleFails {
+			if key := f.Code + "\x00" + f.Message; !seenFails[key] {
+				seenFails[key] = true
+				merged.err.RuleFails = append(merged.err.RuleFails, f)
+			}
+		}
+		for _, p := range r.Penalties {
+			if !seenPenalties[p.Rule] {
+				seenPenalties[p.Rule] = true
+				merged.err.Penalties = append(merged.err.Penalties, p)
+			}
+		}
+		for _, w := range r.Warnings {
+			if !seenWarnings[w] {
+				seenWarnings[w] = true
+				merged.Warnings = append(merged.Warnings, w)
+			}
+		}
+	}
+	merged.RuleFails = merged.err.RuleFails
+	merged.Penalties = merged.err.Penalties
+	return merged
+}
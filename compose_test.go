@@ -0,0 +1,83 @@
+package passval
+
+import "testing"
+
+func TestAll(t *testing.T) {
+	baseline := NewPasswordValidator(8, 64, true, true, true, false, 30)
+	overlay := NewPasswordValidator(12, 64, true, true, true, true, 30)
+	v := All(baseline, overlay)
+
+	r := v.ValidateResult("Abcdefgh12")
+	if r.Pass {
+		t.Fatal("expected failure: overlay requires 12 characters and a symbol")
+	}
+	codes := r.Codes()
+	for _, want := range []string{RuleTooShort, RuleMissingSymbol} {
+		if !containsString(codes, want) {
+			t.Errorf("Codes() = %v, missing %q", codes, want)
+		}
+	}
+
+	pass, score, err := v.ValidateVerbose("Xk9$mP2!vLq#7wZ")
+	if !pass || err != nil {
+		t.Fatalf("expected pass, got err %v", err)
+	}
+	_, s1 := baseline.Validate("Xk9$mP2!vLq#7wZ")
+	_, s2 := overlay.Validate("Xk9$mP2!vLq#7wZ")
+	if want := min(s1, s2); score != want {
+		t.Errorf("score = %d, want lowest %d", score, want)
+	}
+}
+
+func TestAny(t *testing.T) {
+	chars := NewPasswordValidator(10, 64, true, true, true, true, 40)
+	phrase := NewPasswordValidator(20, 128, true, false, false, false, 40, WithPassphrasePolicy(4, 4))
+	v := Any(chars, phrase)
+
+	if pass, _ := v.Validate("Xk9$mP2!vLq#7"); !pass {
+		t.Error("character password should satisfy the character policy")
+	}
+	if pass, _ := v.Validate("orbit velvet tundra meadow"); !pass {
+		t.Error("passphrase should satisfy the passphrase policy")
+	}
+
+	r := v.ValidateResult("short")
+	if r.Pass || r.Err() == nil {
+		t.Fatal("expected failure from both policies")
+	}
+	if n := countString(r.Codes(), RuleTooShort); n != 2 {
+		t.Errorf("RuleTooShort reported %d times, want once per distinct minimum", n)
+	}
+	if n := countString(Any(chars, chars).ValidateResult("short").Codes(), RuleTooShort); n != 1 {
+		t.Errorf("identical failures reported %d times, want 1 after merging", n)
+	}
+
+	if pass, _ := Any().Validate("anything"); pass {
+		t.Error("Any() with no validators should not pass")
+	}
+}
+
+func containsString(ss []string, s string) bool {
+	return countString(ss, s) > 0
+}
+
+func countString(ss []string, s string) int {
+	n := 0
+	for _, x := range ss {
+		if x == s {
+			n++
+		}
+	}
+	return n
+}
+
+func TestEmptyComposite(t *testing.T) {
+	for name, v := range map[string]Validator{"All": All(), "Any": Any()} {
+		if pass, _ := v.Validate("Xk9$mP2!vLq#7wZ"); pass {
+			t.Errorf("%s() with no validators passed a password", name)
+		}
+		if r := v.ValidateResult("x"); r.Pass || r.Err() == nil {
+			t.Errorf("%s() with no validators: Pass = %v, Err = %v", name, r.Pass, r.Err())
+		}
+	}
+}
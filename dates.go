@@ -0,0 +1,213 @@
+package passval
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// dateOrder is a set of orderings of the day, month and year in a date.
+type dateOrder uint8
+
+const (
+	orderDMY dateOrder = 1 << iota // 25/12/1990, most of the world
+	orderMDY                       // 12/25/1990, the United States
+	orderYMD                       // 1990-12-25, East Asia, Hungary, ISO 8601
+)
+
+// defaultDateOrders are the orderings weighted as local without locale hints:
+// those of a US user base.
+const defaultDateOrders = orderMDY | orderYMD
+
+// String names the first ordering in o, e.g. "day/month/year".
+func (o dateOrder) String() string {
+	switch {
+	case o&orderDMY != 0:
+		return "day/month/year"
+	case o&orderMDY != 0:
+		return "month/day/year"
+	case o&orderYMD != 0:
+		return "year-month-day"
+	}
+	return "date"
+}
+
+// Date orderings by region and, for tags without a region, by language.
+var (
+	mdyRegions   = []string{"US", "PH", "FM", "MH", "PW", "AS", "GU", "PR", "UM", "VI"}
+	ymdRegions   = []string{"CN", "TW", "HK", "JP", "KR", "KP", "MN", "HU", "LT", "IR", "SE"}
+	mdyLanguages = []string{"en", "fil"}
+	ymdLanguages = []string{"zh", "ja", "ko", "mn", "hu", "lt", "fa", "sv"}
+)
+
+// WithLocaleHints weights date detection for the user base of the given
+// BCP 47 locales, e.g. "es-AR" or "de": dates written in the local order of
+// day, month and year (25/12/1990 in Argentina, 12/25/1990 in the US,
+// 1990-12-25 in Japan) are penalized as birthdates, while orderings that are
+// valid but not local get a lighter penalty. Without hints, US orderings are
+// assumed local. Unknown locales count as day/month/year, the most common
+// ordering.
+func WithLocaleHints(locales ...string) Option {
+	return func(v *PasswordValidator) {
+		v.localeHints = append(v.localeHints, locales...)
+		v.dateOrders = 0
+		for _, l := range v.localeHints {
+			v.dateOrders |= localeDateOrder(l)
+		}
+		if v.dateOrders == 0 {
+			v.dateOrders = defaultDateOrders
+		}
+	}
+}
+
+// localeDateOrder returns the date ordering of a BCP 47 locale.
+func localeDateOrder(locale string) dateOrder {
+	parts := strings.FieldsFunc(locale, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 {
+		return 0
+	}
+	lang := strings.ToLower(parts[0])
+	for _, p := range parts[1:] {
+		if len(p) != 2 {
+			continue // script or variant subtag
+		}
+		switch region := strings.ToUpper(p); {
+		case slices.Contains(mdyRegions, region):
+			return orderMDY
+		case slices.Contains(ymdRegions, region):
+			return orderYMD
+		}
+		return orderDMY
+	}
+	switch {
+	case slices.Contains(mdyLanguages, lang):
+		return orderMDY
+	case slices.Contains(ymdLanguages, lang):
+		return orderYMD
+	}
+	return orderDMY
+}
+
+// dateSeparators may appear between the parts of a written date.
+const dateSeparators = "/-."
+
+// penaltyDate detects a calendar date, the birthdates and anniversaries
+// behind many passwords: day, month and year with separators ("25/12/1990",
+// "1990-12-25", "25.12.90") or without ("25121990", "901225"). Dates in an
+// ordering local to the user base (see WithLocaleHints) weigh most; 2-digit
+// years weigh a little less than 4-digit ones.
+func penaltyDate(a *analysis, cfg penaltyConfig) *PenaltyDetail {
+	local := cfg.dateOrders
+	if local == 0 {
+		local = defaultDateOrders
+	}
+
+	var best *PenaltyDetail
+	for i := 0; i < len(a.runes); i++ {
+		if !isASCIIDigit(a.runes[i]) || i > 0 && isASCIIDigit(a.runes[i-1]) {
+			continue
+		}
+		end, orders, fullYear := dateAt(a.runes, i)
+		if orders == 0 {
+			continue
+		}
+
+		factor, order := 0.8, orders
+		if orders&local != 0 {
+			factor, order = 0.5, orders&local
+			if !fullYear {
+				factor = 0.6
+			}
+		}
+		if best == nil || factor < best.Factor {
+			best = a.spanned(&PenaltyDetail{
+				Rule:   "date",
+				Factor: factor,
+				Desc:   fmt.Sprintf("password contains a date (%s)", order),
+				Match:  a.password[a.offsets[i]:a.offsets[end]],
+			}, i, end)
+		}
+	}
+	return best
+}
+
+// dateAt reads a date starting at rune i, the start of a digit run, and
+// returns the rune index where it ends, the orderings it is valid in, and
+// whether its year has four digits. orders is 0 if there is no date there.
+func dateAt(runes []rune, i int) (end int, orders dateOrder, fullYear bool) {
+	groups, end := digitGroups(runes, i)
+	switch len(groups) {
+	case 1:
+		// Compact dates: DDMMYYYY, MMDDYYYY, YYYYMMDD, DDMMYY, MMDDYY, YYMMDD
+		g := groups[0]
+		switch len(g) {
+		case 8:
+			orders = dateOrders(g[:2], g[2:4], g[4:], g[:4], g[4:6], g[6:])
+			return end, orders, true
+		case 6:
+			orders = dateOrders(g[:2], g[2:4], g[4:], g[:2], g[2:4], g[4:])
+			return end, orders, false
+		}
+	case 3:
+		g := groups
+		if len(g[0]) == 4 {
+			if isPlausibleYear(g[0]) && len(g[1]) <= 2 && len(g[2]) <= 2 && isDayMonth(pad2(g[2]), pad2(g[1])) {
+				return end, orderYMD, true
+			}
+			return 0, 0, false
+		}
+		if len(g[0]) <= 2 && len(g[1]) <= 2 && (len(g[2]) == 2 || len(g[2]) == 4) {
+			orders = dateOrders(pad2(g[0]), pad2(g[1]), g[2], "", "", "")
+			return end, orders, len(g[2]) == 4
+		}
+	}
+	return 0, 0, false
+}
+
+// digitGroups returns the digit groups starting at rune i, separated by one
+// repeated date separator ("25/12/1990", not "25/12-1990"), and the rune
+// index after the last group.
+func digitGroups(runes []rune, i int) (groups []string, end int) {
+	var sep rune
+	for {
+		j := i
+		for j < len(runes) && isASCIIDigit(runes[j]) {
+			j++
+		}
+		groups = append(groups, string(runes[i:j]))
+		end = j
+		if len(groups) == 3 || j+1 >= len(runes) || !strings.ContainsRune(dateSeparators, runes[j]) ||
+			sep != 0 && runes[j] != sep || !isASCIIDigit(runes[j+1]) {
+			return groups, end
+		}
+		sep, i = runes[j], j+1
+	}
+}
+
+// dateOrders returns the orderings in which a, b and year read as a valid
+// date with the year last, and in which ya, yb, yc read as a valid date with
+// the year first. Years of four digits must be plausible birth or event years.
+func dateOrders(a, b, year, ya, yb, yc string) dateOrder {
+	validYear := func(y string) bool { return len(y) == 2 || isPlausibleYear(y) }
+	var o dateOrder
+	if validYear(year) {
+		if isDayMonth(a, b) {
+			o |= orderDMY
+		}
+		if isDayMonth(b, a) {
+			o |= orderMDY
+		}
+	}
+	if ya != "" && validYear(ya) && isDayMonth(yc, yb) {
+		o |= orderYMD
+	}
+	return o
+}
+
+// pad2 left-pads a 1-digit day or month with a zero.
+func pad2(s string) string {
+	if len(s) == 1 {
+		return "0" + s
+	}
+	return s
+}
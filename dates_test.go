@@ -0,0 +1,66 @@
+package passval
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLocaleDateOrder(t *testing.T) {
+	for locale, want := range map[string]dateOrder{
+		"es-AR":      orderDMY,
+		"pt_BR":      orderDMY,
+		"de":         orderDMY,
+		"en-GB":      orderDMY,
+		"en-US":      orderMDY,
+		"en":         orderMDY,
+		"ja":         orderYMD,
+		"zh-Hant-TW": orderYMD,
+		"hu-HU":      orderYMD,
+		"":           0,
+	} {
+		if got := localeDateOrder(locale); got != want {
+			t.Errorf("localeDateOrder(%q) = %v, want %v", locale, got, want)
+		}
+	}
+}
+
+func TestDatePenalty(t *testing.T) {
+	tests := []struct {
+		password string
+		locales  []string
+		factor   float64 // 0 for no date penalty
+	}{
+		{"Lucia12/25/1990", nil, 0.5},
+		{"Lucia25/12/1990", nil, 0.8},
+		{"Lucia25/12/1990", []string{"es-AR"}, 0.5},
+		{"Lucia12/25/1990", []string{"es-AR"}, 0.8},
+		{"Lucia25121990", []string{"es-AR"}, 0.5},
+		{"Lucia25.12.90", []string{"de-DE"}, 0.6},
+		{"Lucia1990-12-25", []string{"ja"}, 0.5},
+		{"Lucia1990-12-25", []string{"es-AR"}, 0.8},
+		{"Lucia1990-12-25", []string{"es-AR", "ja"}, 0.5},
+		{"Lucia25/12-1990", []string{"es-AR"}, 0},
+		{"Lucia12/34/5678", nil, 0},
+		{"Xq7#zR2mK9w!", nil, 0},
+	}
+	for _, tt := range tests {
+		var opts []Option
+		if tt.locales != nil {
+			opts = append(opts, WithLocaleHints(tt.locales...))
+		}
+		var got float64
+		for _, p := range DetectPenalties(tt.password, opts...) {
+			if p.Rule == "date" {
+				got = p.Factor
+			}
+		}
+		if got != tt.factor {
+			t.Errorf("%q with %v: date factor = %v, want %v", tt.password, tt.locales, got, tt.factor)
+		}
+	}
+
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0, WithLocaleHints("es-AR"))
+	if p := v.Policy(); !slices.Equal(p.LocaleHints, []string{"es-AR"}) {
+		t.Errorf("Policy().LocaleHints = %v, want [es-AR]", p.LocaleHints)
+	}
+}
@@ -0,0 +1,84 @@
+package passval
+
+import "slices"
+
+// DebugTrace records how a password was scored, with WithDebugTrace: every
+// word the wordlists matched and every penalty the detectors proposed,
+// whether or not it was applied. It is meant for tuning penalties; the
+// applied penalties alone are in Result.Penalties.
+type DebugTrace struct {
+	BaseScore  int // score from the entropy, before penalties
+	Hits       []TraceHit
+	Candidates []TraceCandidate
+}
+
+// TraceHit is an occurrence of a wordlist word in the password, literally or
+// through leet-speak substitutions.
+type TraceHit struct {
+	List     string // "dictionary", "topical" or "language"
+	Word     string // the listed word; empty for ValidateBytes
+	Start    int    // byte offsets of the occurrence, password[Start:End]
+	End      int
+	Reversed bool // found in the reversed password
+}
+
+// TraceCandidate is a penalty a detector proposed. Applied is false for
+// penalties that were superseded or turned off, with the reason in Skipped.
+type TraceCandidate struct {
+	PenaltyDetail
+	Applied bool
+	Skipped string
+}
+
+// WithDebugTrace fills Result.Debug with a DebugTrace of each validation, so
+// that the candidates behind a score can be inspected without instrumenting
+// the detectors. Tracing costs extra allocations and runs the dictionary
+// detectors that would otherwise be skipped, so it is not meant for
+// production validators.
+func WithDebugTrace() Option {
+	return func(v *PasswordValidator) {
+		v.debugTrace = true
+	}
+}
+
+// hit records the dictionary match m of form f, found in the list named list.
+// Matches already recorded by another detector are not repeated.
+func (t *DebugTrace) hit(a *analysis, list string, m dictMatch, f form) {
+	if t == nil {
+		return
+	}
+	start, end := a.spanOf(m, f)
+	h := TraceHit{List: list, Word: m.word, Start: a.offsets[start], End: a.offsets[end], Reversed: f.reversed}
+	if !slices.Contains(t.Hits, h) {
+		t.Hits = append(t.Hits, h)
+	}
+}
+
+// candidate records the penalty p, applied or skipped for the given reason.
+func (t *DebugTrace) candidate(p *PenaltyDetail, skipped string) {
+	if t == nil || p == nil {
+		return
+	}
+	t.Candidates = append(t.Candidates, TraceCandidate{PenaltyDetail: *p, Applied: skipped == "", Skipped: skipped})
+}
+
+// redact clears the words and matches of the trace, for passwords that must
+// stay secret.
+func (t *DebugTrace) redact() {
+	for i := range t.Hits {
+		t.Hits[i].Word = ""
+	}
+	for i := range t.Candidates {
+		t.Candidates[i].Match = ""
+	}
+}
+
+// scanWords returns the matches in form f of the words of d of at least minLen
+// characters, recording them in the trace under list.
+func (c penaltyConfig) scanWords(a *analysis, list string, d *dictionary, f form, minLen int) []dictMatch {
+	matches := d.leetMatchesN(f.s, minLen, a.leet, c.dictLimit)
+	for _, m := range matches {
+		c.trace.hit(a, list, m, f)
+	}
+	return matches
+}
@@ -0,0 +1,64 @@
+package passval
+
+import "testing"
+
+func TestDebugTrace(t *testing.T) {
+	if r := NewPasswordValidator(8, 64, false, false, false, false, 0).ValidateResult("dragonmonkey"); r.Debug != nil {
+		t.Errorf("trace without WithDebugTrace: %+v", r.Debug)
+	}
+
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0, WithDebugTrace(), WithDisabledPenalties("repeated_chars"))
+	r := v.ValidateResult("dragonmonkey")
+	d := r.Debug
+	if d == nil {
+		t.Fatal("expected a trace")
+	}
+	if d.BaseScore < r.Score {
+		t.Errorf("base score %d below the final score %d", d.BaseScore, r.Score)
+	}
+
+	found := func(list, word string) bool {
+		for _, h := range d.Hits {
+			if h.List == list && h.Word == word && !h.Reversed {
+				return true
+			}
+		}
+		return false
+	}
+	for _, w := range []string{"dragon", "monkey"} {
+		if !found("dictionary", w) {
+			t.Errorf("no dictionary hit for %q in %+v", w, d.Hits)
+		}
+	}
+
+	var applied, superseded int
+	for _, c := range d.Candidates {
+		switch {
+		case c.Applied:
+			applied++
+		case c.Rule == "dictionary_substring" && c.Skipped == "superseded by dictionary_concatenation":
+			superseded++
+		}
+	}
+	if applied != len(r.Penalties) || superseded != 1 {
+		t.Errorf("expected %d applied candidates and a superseded dictionary_substring, got %+v", len(r.Penalties), d.Candidates)
+	}
+
+	r = v.ValidateResult("aaaaaaaa")
+	c := r.Debug.Candidates
+	if len(c) == 0 || c[0].Rule != "repeated_chars" || c[0].Applied || c[0].Skipped != "disabled" {
+		t.Errorf("expected the disabled repeated_chars candidate, got %+v", c)
+	}
+
+	r = v.ValidateBytes([]byte("dragonmonkey"))
+	for _, h := range r.Debug.Hits {
+		if h.Word != "" {
+			t.Errorf("ValidateBytes trace reveals %q", h.Word)
+		}
+	}
+	for _, c := range r.Debug.Candidates {
+		if c.Match != "" {
+			t.Errorf("ValidateBytes trace reveals %q", c.Match)
+		}
+	}
+}
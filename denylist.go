@@ -0,0 +1,68 @@
+package passval
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidateOption configures a single ValidateWith call.
+type ValidateOption func(*validateConfig)
+
+type validateConfig struct {
+	denylist *dictionary
+}
+
+// minDenylistMatch is the shortest denylist entry matched inside a longer
+// password. Entries are specific to one user, so shorter ones than the
+// dictionary's four characters (a pet called "Rex") are worth catching.
+const minDenylistMatch = 3
+
+// WithDenylist rejects, for one call, passwords built from any of words,
+// e.g. the user's previous passwords or names from their profile. Words are
+// matched like the common passwords dictionary: exactly, through leet-speak,
+// reversed, or inside a longer password. A match is a hard rule failure with
+// code RuleDenylisted; the message does not repeat the word.
+func WithDenylist(words ...string) ValidateOption {
+	return func(c *validateConfig) {
+		if len(words) > 0 {
+			c.denylist = loadDictionary(strings.Join(words, "\n"))
+		}
+	}
+}
+
+// ValidateWith validates password like ValidateResult, applying per-call
+// options such as WithDenylist without building a validator per request.
+func (v *PasswordValidator) ValidateWith(password string, opts ...ValidateOption) *Result {
+	var cfg validateConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	a := v.analyze(password)
+	r := v.validateAnalysis(a, false)
+	if msg := denylistMatch(a, cfg.denylist); msg != "" {
+		v.addRuleFail(r, newRuleFail(RuleDenylisted, msg))
+	}
+	v.report(r)
+	return r
+}
+
+// denylistMatch describes how the analyzed password matches an entry of d,
+// or returns "" if it matches none.
+func denylistMatch(a *analysis, d *dictionary) string {
+	if d == nil || len(d.words) == 0 {
+		return ""
+	}
+	for _, f := range a.forms() {
+		if d.contains(f.s) || d.leetMatch(f.s, a.leet) != "" {
+			return "password is on the denylist" + f.note
+		}
+	}
+	for _, f := range a.forms() {
+		if m, ok := d.longestLeetMatch(f.s, minDenylistMatch, a.leet); ok {
+			return fmt.Sprintf("contains a denylisted word (%d chars)", utf8.RuneCountInString(m.word)) + f.note
+		}
+	}
+	return ""
+}
@@ -0,0 +1,41 @@
+package passval
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateWithDenylist(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, false, 0)
+	deny := WithDenylist("Summer2023x", "Rex")
+
+	for _, tc := range []struct {
+		pwd  string
+		deny bool
+	}{
+		{"Summer2023x", true},
+		{"5ummer2023x", true},  // leet
+		{"x3202remmuS", true},  // reversed
+		{"Rex4ever9Qz", true},  // short entry inside a longer password
+		{"Kq7vLp2wTz9", false}, // unrelated
+	} {
+		r := v.ValidateWith(tc.pwd, deny)
+		got := slices.Contains(r.Codes(), RuleDenylisted)
+		if got != tc.deny {
+			t.Errorf("%q: denylisted = %v, want %v (%v)", tc.pwd, got, tc.deny, r.RuleFails)
+		}
+		if got && r.Pass {
+			t.Errorf("%q: denylisted password should fail", tc.pwd)
+		}
+		for _, msg := range r.FailMessages() {
+			if strings.Contains(strings.ToLower(msg), "summer") || strings.Contains(strings.ToLower(msg), "rex") {
+				t.Errorf("%q: message repeats the denylisted word: %s", tc.pwd, msg)
+			}
+		}
+	}
+
+	if r := v.ValidateWith("Summer2023x"); !r.Pass {
+		t.Errorf("without a denylist the password should pass, got %v", r.RuleFails)
+	}
+}
@@ -1,19 +1,41 @@
 package passval
 
 import (
-	_ "embed"
+	"cmp"
+	"slices"
 	"strings"
+	"unicode/utf8"
 )
 
-//go:embed data/common_passwords.txt
-var commonPasswordsData string
-
-// dictionary holds the common passwords set for fast lookup.
+// dictionary holds a word list for exact lookup, through binary search of
+// the sorted words, and for leet-aware substring matching, through a trie
+// stored in two flat slices. Compared to a map and a trie of pointer nodes
+// with per-node child maps, it needs about a tenth of the memory for large
+// lists (see BenchmarkLoadDictionary).
 type dictionary struct {
-	set   map[string]bool
-	words []string // for substring iteration
+	words []string   // sorted, without duplicates
+	nodes []trieNode // nodes[0] is the root
+	edges []trieEdge // the edges of each node, contiguous and sorted by label
+}
+
+// trieNode is a node of the dictionary trie. Its edges are
+// edges[first:first+count]; word is the index in words of the word the node
+// ends, or -1.
+type trieNode struct {
+	first uint32
+	count uint32
+	word  int32
 }
 
+// trieEdge leads from a node to the child for label.
+type trieEdge struct {
+	label rune
+	child uint32
+}
+
+// root is the index of the root node of the trie.
+const root = 0
+
 // globalDict is initialized at package load time.
 var globalDict *dictionary
 
@@ -22,22 +44,181 @@ func init() {
 }
 
 func loadDictionary(data string) *dictionary {
-	lines := strings.Split(data, "\n")
-	d := &dictionary{
-		set: make(map[string]bool, len(lines)),
-	}
-	for _, line := range lines {
-		word := strings.TrimSpace(strings.ToLower(line))
-		if word == "" {
-			continue
+	var words []string
+	for line := range strings.SplitSeq(data, "\n") {
+		if word := strings.TrimSpace(strings.ToLower(line)); word != "" {
+			words = append(words, word)
 		}
-		d.set[word] = true
-		d.words = append(d.words, word)
 	}
+	return newDictionary(words)
+}
+
+// newDictionary builds a dictionary of words, which it sorts in place.
+func newDictionary(words []string) *dictionary {
+	slices.Sort(words)
+	d := &dictionary{words: slices.Clip(slices.Compact(words))}
+	d.build(0, len(d.words), 0)
+	d.nodes, d.edges = slices.Clip(d.nodes), slices.Clip(d.edges)
 	return d
 }
 
+// build adds the node for the prefix of length off shared by words[lo:hi],
+// and its descendants, and returns its index.
+func (d *dictionary) build(lo, hi, off int) uint32 {
+	n := uint32(len(d.nodes))
+	d.nodes = append(d.nodes, trieNode{word: -1})
+	if lo < hi && len(d.words[lo]) == off {
+		d.nodes[n].word = int32(lo)
+		lo++
+	}
+
+	// Words are sorted, so those continuing with the same rune are adjacent,
+	// and byte order is rune order in UTF-8.
+	type group struct {
+		label  rune
+		lo, hi int
+		size   int
+	}
+	var groups []group
+	for i := lo; i < hi; {
+		r, size := utf8.DecodeRuneInString(d.words[i][off:])
+		j := i + 1
+		for j < hi && strings.HasPrefix(d.words[j][off:], d.words[i][off:off+size]) {
+			j++
+		}
+		groups = append(groups, group{r, i, j, size})
+		i = j
+	}
+
+	first := uint32(len(d.edges))
+	d.nodes[n].first, d.nodes[n].count = first, uint32(len(groups))
+	for _, g := range groups {
+		d.edges = append(d.edges, trieEdge{label: g.label})
+	}
+	for k, g := range groups {
+		d.edges[first+uint32(k)].child = d.build(g.lo, g.hi, off+g.size)
+	}
+	return n
+}
+
+// child returns the child of node n for r, or false if there is none.
+func (d *dictionary) child(n uint32, r rune) (uint32, bool) {
+	node := d.nodes[n]
+	edges := d.edges[node.first : node.first+node.count]
+	k, ok := slices.BinarySearchFunc(edges, r, func(e trieEdge, r rune) int { return cmp.Compare(e.label, r) })
+	if !ok {
+		return 0, false
+	}
+	return edges[k].child, true
+}
+
+// wordAt returns the word node n ends, or "".
+func (d *dictionary) wordAt(n uint32) string {
+	if w := d.nodes[n].word; w >= 0 {
+		return d.words[w]
+	}
+	return ""
+}
+
+// leetStep advances a set of trie nodes by one password character, following
+// the character itself and each of its mappings in table. The walk over the
+// trie is an automaton over the ambiguity graph: the state set is bounded by
+// the trie, however many ambiguous characters the password has.
+func (d *dictionary) leetStep(states []uint32, r rune, table leetTable) []uint32 {
+	var next []uint32
+	add := func(n uint32, ok bool) {
+		if ok && !slices.Contains(next, n) {
+			next = append(next, n)
+		}
+	}
+	for _, s := range states {
+		add(d.child(s, r))
+		for _, m := range table[r] {
+			add(d.walk(s, m))
+		}
+	}
+	return next
+}
+
+// walk follows the characters of s from node n, returning false if the trie
+// has no such path.
+func (d *dictionary) walk(n uint32, s string) (uint32, bool) {
+	for _, r := range s {
+		var ok bool
+		if n, ok = d.child(n, r); !ok {
+			return 0, false
+		}
+	}
+	return n, true
+}
+
+// leetMatch returns the dictionary word that s spells through the
+// substitutions in table, or "" if there is none.
+func (d *dictionary) leetMatch(s string, table leetTable) string {
+	states := []uint32{root}
+	for _, r := range s {
+		if states = d.leetStep(states, r, table); len(states) == 0 {
+			return ""
+		}
+	}
+	for _, n := range states {
+		if w := d.wordAt(n); w != "" {
+			return w
+		}
+	}
+	return ""
+}
+
+// dictMatch is an occurrence of a dictionary word in a password, spanning
+// runes [start, end).
+type dictMatch struct {
+	word       string
+	start, end int
+}
+
+// leetMatches returns every occurrence in s of a dictionary word of at least
+// minLen bytes, literally or through the substitutions in table.
+func (d *dictionary) leetMatches(s string, minLen int, table leetTable) []dictMatch {
+	return d.leetMatchesN(s, minLen, table, 0)
+}
+
+// leetMatchesN is like leetMatches but stops after n matches, if n > 0.
+func (d *dictionary) leetMatchesN(s string, minLen int, table leetTable, n int) []dictMatch {
+	runes := []rune(s)
+	var matches []dictMatch
+	for i := range runes {
+		states := []uint32{root}
+		for j, r := range runes[i:] {
+			if states = d.leetStep(states, r, table); len(states) == 0 {
+				break
+			}
+			for _, node := range states {
+				if w := d.wordAt(node); len(w) >= minLen {
+					matches = append(matches, dictMatch{word: w, start: i, end: i + j + 1})
+					if len(matches) == n {
+						return matches
+					}
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// longestLeetMatch returns the longest occurrence in s of a dictionary word of
+// at least minLen bytes, literally or through the substitutions in table.
+// ok is false if there is none.
+func (d *dictionary) longestLeetMatch(s string, minLen int, table leetTable) (longest dictMatch, ok bool) {
+	for _, m := range d.leetMatches(s, minLen, table) {
+		if len(m.word) > len(longest.word) {
+			longest, ok = m, true
+		}
+	}
+	return longest, ok
+}
+
 // contains checks if the exact word is in the dictionary.
 func (d *dictionary) contains(word string) bool {
-	return d.set[word]
+	_, ok := slices.BinarySearch(d.words, word)
+	return ok
 }
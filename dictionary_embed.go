@@ -0,0 +1,12 @@
+//go:build !passval_nodict
+
+package passval
+
+import _ "embed"
+
+// commonPasswordsData is the embedded common passwords list. Build with the
+// passval_nodict tag to embed only its 100 most common passwords, e.g. for
+// size-sensitive js/wasm builds.
+//
+//go:embed data/common_passwords.txt
+var commonPasswordsData string
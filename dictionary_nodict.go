@@ -0,0 +1,13 @@
+//go:build passval_nodict
+
+package passval
+
+import _ "embed"
+
+// commonPasswordsData is the small dictionary tier of passval_nodict builds:
+// the 100 most common passwords of the full list, so that js/wasm builds
+// still catch the worst choices. NewPasswordValidatorWithDict and
+// SetDictionary load a complete list.
+//
+//go:embed data/common_passwords_top100.txt
+var commonPasswordsData string
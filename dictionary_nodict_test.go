@@ -0,0 +1,7 @@
+//go:build passval_nodict
+
+package passval
+
+// fullDictionary reports whether the complete common passwords list is
+// embedded, for tests that rely on its less common entries.
+const fullDictionary = false
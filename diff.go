@@ -0,0 +1,90 @@
+package passval
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// PolicyChange is one difference between two policies.
+type PolicyChange struct {
+	Field   string `json:"field"` // the Policy JSON field, e.g. "min_length"
+	From    any    `json:"from"`
+	To      any    `json:"to"`
+	Message string `json:"message"` // e.g. "min length raised from 8 to 12"
+}
+
+// DiffPolicies compares the policies of a and b, e.g. the current and the
+// proposed validator of a rollout, and returns the changes from a to b in the
+// order of the Policy fields. It returns nil if the policies are equal.
+func DiffPolicies(a, b *PasswordValidator) []PolicyChange {
+	return diffPolicies(a.Policy(), b.Policy())
+}
+
+func diffPolicies(a, b Policy) []PolicyChange {
+	var changes []PolicyChange
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	for i := range va.NumField() {
+		from, to := va.Field(i).Interface(), vb.Field(i).Interface()
+		if reflect.DeepEqual(from, to) {
+			continue
+		}
+		field, _, _ := strings.Cut(va.Type().Field(i).Tag.Get("json"), ",")
+		changes = append(changes, PolicyChange{
+			Field:   field,
+			From:    from,
+			To:      to,
+			Message: describeChange(strings.ReplaceAll(field, "_", " "), from, to),
+		})
+	}
+	return changes
+}
+
+// describeChange describes the change of the named field from one value to
+// another of the same type.
+func describeChange(name string, from, to any) string {
+	switch from := from.(type) {
+	case bool:
+		if to.(bool) {
+			return name + " enabled"
+		}
+		return name + " disabled"
+	case int:
+		if to.(int) > from {
+			return fmt.Sprintf("%s raised from %d to %d", name, from, to)
+		}
+		return fmt.Sprintf("%s lowered from %d to %d", name, from, to)
+	case float64:
+		if to.(float64) > from {
+			return fmt.Sprintf("%s raised from %g to %g", name, from, to)
+		}
+		return fmt.Sprintf("%s lowered from %g to %g", name, from, to)
+	case []string:
+		var parts []string
+		if added := missingFrom(to.([]string), from); len(added) > 0 {
+			parts = append(parts, "added "+strings.Join(added, ", "))
+		}
+		if removed := missingFrom(from, to.([]string)); len(removed) > 0 {
+			parts = append(parts, "removed "+strings.Join(removed, ", "))
+		}
+		if len(parts) == 0 {
+			return name + " reordered"
+		}
+		return name + ": " + strings.Join(parts, "; ")
+	case []StrengthLevel:
+		return fmt.Sprintf("%s changed from %s to %s", name, formatStrengthLevels(from), formatStrengthLevels(to.([]StrengthLevel)))
+	}
+	return fmt.Sprintf("%s changed from %q to %q", name, from, to)
+}
+
+// missingFrom returns the elements of s that are not in other.
+func missingFrom(s, other []string) []string {
+	var out []string
+	for _, e := range s {
+		if !slices.Contains(other, e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,39 @@
+package passval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffPolicies(t *testing.T) {
+	a := NewPasswordValidator(8, 64, true, true, true, false, 50, WithBannedSubstrings("acme", "widget"))
+	b := NewPasswordValidator(12, 64, true, true, true, true, 60,
+		WithBannedSubstrings("acme", "gadget"), WithMinGuesses(1e10))
+
+	if changes := DiffPolicies(a, a.Clone()); changes != nil {
+		t.Errorf("identical policies: got %v", changes)
+	}
+
+	var msgs []string
+	for _, c := range DiffPolicies(a, b) {
+		msgs = append(msgs, c.Message)
+	}
+	want := []string{
+		"min length raised from 8 to 12",
+		"require symbols enabled",
+		"complexity raised from 50 to 60",
+		"banned substrings: added gadget; removed widget",
+		"min guesses raised from 0 to 1e+10",
+	}
+	if got := strings.Join(msgs, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("DiffPolicies messages:\n%s\nwant:\n%s", got, strings.Join(want, "\n"))
+	}
+
+	changes := DiffPolicies(b, a)
+	if c := changes[0]; c.Field != "min_length" || c.From != 12 || c.To != 8 || c.Message != "min length lowered from 12 to 8" {
+		t.Errorf("first reverse change = %+v", c)
+	}
+	if c := DiffPolicies(a, a.Clone(WithEntropyMode(EntropyShannon))); len(c) != 1 || c[0].Message != `entropy mode changed from "pool" to "shannon"` {
+		t.Errorf("entropy mode change = %+v", c)
+	}
+}
@@ -0,0 +1,64 @@
+package passval
+
+import (
+	"fmt"
+	"strings"
+)
+
+// confusableNames names the characters of ambiguousChars for warnings.
+var confusableNames = map[rune]string{
+	'O': "capital O", '0': "zero", 'o': "lowercase o",
+	'I': "capital i", 'l': "lowercase L", '1': "one", '|': "vertical bar",
+	'`': "backtick", '\'': "apostrophe", '"': "double quote",
+}
+
+// Confusables returns the characters of password that are easily confused
+// with others when read or transcribed (O and 0, l and 1, quotes), in order of
+// first appearance. WithExcludeAmbiguous keeps them out of generated
+// passwords.
+func Confusables(password string) []rune {
+	var out []rune
+	for _, r := range password {
+		if strings.ContainsRune(ambiguousChars, r) && !strings.ContainsRune(string(out), r) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// confusableWarnings returns a WarnConfusable warning listing the confusable
+// characters of password, or nil if it has none.
+func confusableWarnings(password string) []Warning {
+	chars := Confusables(password)
+	if len(chars) == 0 {
+		return nil
+	}
+	names := make([]string, len(chars))
+	for i, r := range chars {
+		names[i] = fmt.Sprintf("%c (%s)", r, confusableNames[r])
+	}
+	return []Warning{{
+		Code:    WarnConfusable,
+		Message: "contains characters that are easy to misread: " + strings.Join(names, ", "),
+	}}
+}
+
+// FormatForDisplay groups password into chunks of size characters joined by
+// sep, e.g. "xK9m-Qp2v-Lt7w" for size 4 and sep "-", to make it easier to
+// read out or copy by hand. Choose a sep that the password cannot contain,
+// or show it in a different style; the grouping is for display only and must
+// be removed before use. A size below 1 returns password unchanged.
+func FormatForDisplay(password string, size int, sep string) string {
+	runes := []rune(password)
+	if size < 1 || len(runes) <= size {
+		return password
+	}
+	var b strings.Builder
+	for i := 0; i < len(runes); i += size {
+		if i > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(string(runes[i:min(i+size, len(runes))]))
+	}
+	return b.String()
+}
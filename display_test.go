@@ -0,0 +1,51 @@
+package passval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfusables(t *testing.T) {
+	if got := string(Confusables("Ol0x1lO")); got != "Ol01" {
+		t.Errorf("Confusables = %q, want %q", got, "Ol01")
+	}
+	if got := Confusables("xK9#mPq2"); got != nil {
+		t.Errorf("Confusables = %q, want none", string(got))
+	}
+
+	w := confusableWarnings("Tr0ub4dor")
+	if len(w) != 1 || w[0].Code != WarnConfusable || !strings.Contains(w[0].Message, "0 (zero)") {
+		t.Errorf("confusableWarnings = %+v", w)
+	}
+
+	v := NewPasswordValidator(16, 16, true, true, true, true, 50, WithExcludeAmbiguous())
+	for range 20 {
+		g, err := v.GenerateWithInfo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(g.Warnings) != 0 {
+			t.Fatalf("GenerateWithInfo with WithExcludeAmbiguous: %+v", g)
+		}
+	}
+}
+
+func TestFormatForDisplay(t *testing.T) {
+	tests := []struct {
+		pwd  string
+		size int
+		sep  string
+		want string
+	}{
+		{"xK9mQp2vLt7w", 4, "-", "xK9m-Qp2v-Lt7w"},
+		{"xK9mQp2vLt", 4, " ", "xK9m Qp2v Lt"},
+		{"ñandú€", 2, "·", "ña·nd·ú€"},
+		{"short", 8, "-", "short"},
+		{"xK9mQp2v", 0, "-", "xK9mQp2v"},
+	}
+	for _, tt := range tests {
+		if got := FormatForDisplay(tt.pwd, tt.size, tt.sep); got != tt.want {
+			t.Errorf("FormatForDisplay(%q, %d, %q) = %q, want %q", tt.pwd, tt.size, tt.sep, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package passval
 
 import (
 	"math"
+	"math/bits"
 	"unicode"
 )
 
@@ -21,6 +22,31 @@ func calculateEntropy(password string) float64 {
 	return float64(len(password)) * math.Log2(float64(poolSize))
 }
 
+// calculateShannonEntropy computes the entropy bits of a password from the
+// observed frequency of each character: length * -Σ p(c) * log2(p(c)).
+// Unlike calculateEntropy it rewards an even spread of characters, not just
+// the presence of character classes.
+func calculateShannonEntropy(password string) float64 {
+	if len(password) == 0 {
+		return 0
+	}
+
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range password {
+		counts[r]++
+		total++
+	}
+
+	perChar := 0.0
+	for _, c := range counts {
+		p := float64(c) / float64(total)
+		perChar -= p * math.Log2(p)
+	}
+
+	return float64(total) * perChar
+}
+
 // effectivePoolSize determines the character pool based on what types
 // of characters are actually present in the password.
 func effectivePoolSize(password string) int {
@@ -58,6 +84,9 @@ func effectivePoolSize(password string) int {
 	return pool
 }
 
+// scoreCurveK controls the score curve shape — lower = faster saturation.
+const scoreCurveK = 40.0
+
 // entropyToScore maps entropy bits to a 0-100 score using a logarithmic curve
 // with diminishing returns after ~60 bits.
 //
@@ -74,9 +103,7 @@ func entropyToScore(entropy float64) int {
 		return 0
 	}
 
-	const k = 40.0 // controls curve shape — lower = faster saturation
-
-	score := 100.0 * (1.0 - math.Exp(-entropy/k))
+	score := 100.0 * (1.0 - math.Exp(-entropy/scoreCurveK))
 
 	s := int(math.Round(score))
 	if s > 100 {
@@ -87,3 +114,31 @@ func entropyToScore(entropy float64) int {
 	}
 	return s
 }
+
+// maxLengthScore caps lengthScore, keeping it below any meaningful strength.
+const maxLengthScore = 5
+
+// lengthScore is the score of a degenerate password of n characters, one
+// no entropy estimate credits ("aaaaaaaaaaaa" under EntropyShannon): 1 for
+// one character and a point more each time the length doubles, up to
+// maxLengthScore at 16. It keeps such passwords apart from the empty password
+// and growing as they are typed, so the score works as a progressive strength
+// meter. Passwords whose penalties wipe out their score are not floored.
+func lengthScore(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return min(bits.Len(uint(n)), maxLengthScore)
+}
+
+// scoreToEntropy is the inverse of entropyToScore: it returns the entropy bits
+// that correspond to a 0-100 score.
+func scoreToEntropy(score int) float64 {
+	if score <= 0 {
+		return 0
+	}
+	if score >= 100 {
+		score = 99
+	}
+	return -scoreCurveK * math.Log(1.0-float64(score)/100.0)
+}
@@ -0,0 +1,79 @@
+package passval
+
+import "time"
+
+// Event types reported to an EventSink.
+const (
+	EventValidation = "validation"
+	EventGeneration = "generation"
+)
+
+// Event is a structured record of a policy decision, for audit trails and
+// SIEM pipelines. It never contains the password.
+type Event struct {
+	Type          string    `json:"type"` // EventValidation or EventGeneration
+	Time          time.Time `json:"time"`
+	PolicyVersion string    `json:"policy_version,omitempty"`
+
+	Pass  bool     `json:"pass"`
+	Score int      `json:"score,omitempty"` // validations only
+	Codes []string `json:"codes,omitempty"` // reason codes of a validation
+
+	Attempts int    `json:"attempts,omitempty"` // candidates tried by a generation
+	Error    string `json:"error,omitempty"`    // why a generation failed
+}
+
+// EventSink receives an Event for every validation and generation, e.g. to
+// stream policy decisions to a SIEM without wrapping every call site.
+// Implementations must be safe for concurrent use and should not block.
+type EventSink interface {
+	Emit(e Event)
+}
+
+// WithEventSink reports policy decisions to s.
+func WithEventSink(s EventSink) Option {
+	return func(v *PasswordValidator) {
+		v.events = s
+	}
+}
+
+// WithPolicyVersion labels the events of the validator with version, so that
+// decisions can be traced to the policy revision that made them.
+func WithPolicyVersion(version string) Option {
+	return func(v *PasswordValidator) {
+		v.policyVersion = version
+	}
+}
+
+// emitValidation reports a validation outcome to the configured event sink.
+func (v *PasswordValidator) emitValidation(r *Result) {
+	if v.events == nil {
+		return
+	}
+	v.events.Emit(Event{
+		Type:          EventValidation,
+		Time:          time.Now(),
+		PolicyVersion: v.policyVersion,
+		Pass:          r.Pass,
+		Score:         r.Score,
+		Codes:         r.Codes(),
+	})
+}
+
+// emitGeneration reports a generation outcome to the configured event sink.
+func (v *PasswordValidator) emitGeneration(attempts int, err error) {
+	if v.events == nil {
+		return
+	}
+	e := Event{
+		Type:          EventGeneration,
+		Time:          time.Now(),
+		PolicyVersion: v.policyVersion,
+		Pass:          err == nil,
+		Attempts:      attempts,
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	v.events.Emit(e)
+}
@@ -0,0 +1,53 @@
+package passval
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type recordingSink struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (s *recordingSink) Emit(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, e)
+}
+
+func TestWithEventSink(t *testing.T) {
+	sink := &recordingSink{}
+	v := NewPasswordValidator(8, 64, true, true, true, true, 40, WithEventSink(sink), WithPolicyVersion("2026-10"))
+
+	v.Validate("letmein")
+	v.ValidateWith("Xk9$mP2!vLq", WithDenylist("dragon"))
+	if _, err := v.Generate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sink.events) != 3 {
+		t.Fatalf("expected 3 events, got %+v", sink.events)
+	}
+	first := sink.events[0]
+	if first.Type != EventValidation || first.Pass || first.PolicyVersion != "2026-10" || first.Time.IsZero() ||
+		!slices.Contains(first.Codes, "common_password") {
+		t.Errorf("unexpected validation event %+v", first)
+	}
+	if e := sink.events[1]; e.Type != EventValidation || !e.Pass {
+		t.Errorf("unexpected validation event %+v", e)
+	}
+	if e := sink.events[2]; e.Type != EventGeneration || !e.Pass || e.Attempts < 1 {
+		t.Errorf("unexpected generation event %+v", e)
+	}
+
+	for _, e := range sink.events {
+		data, _ := json.Marshal(e)
+		if strings.Contains(string(data), "letmein") || strings.Contains(string(data), "Xk9") {
+			t.Errorf("event leaks the password: %s", data)
+		}
+	}
+}
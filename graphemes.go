@@ -0,0 +1,197 @@
+package passval
+
+import (
+	"math"
+	"unicode"
+)
+
+// emojiPoolSize is the pool of an emoji character for pool entropy: roughly
+// the number of emoji a keyboard picker offers, not counting skin tones.
+const emojiPoolSize = 1400
+
+// WithGraphemeAnalysis measures passwords in user-perceived characters
+// (extended grapheme clusters) instead of bytes: an emoji with a skin tone, a
+// flag or a letter with combining accents counts as one character for the
+// length rules, repetition and diversity checks, and entropy, where emoji
+// draw from a pool of their own. Segmentation follows the rules of Unicode
+// UAX #29 that matter for passwords (combining marks, emoji modifiers and
+// ZWJ sequences, flags, CRLF) without the full Unicode tables.
+func WithGraphemeAnalysis() Option {
+	return func(v *PasswordValidator) {
+		v.graphemes = true
+	}
+}
+
+// analyze computes the shared analysis of password with the validator's
+// leet table, segmented into grapheme clusters if configured.
+func (v *PasswordValidator) analyze(password string) *analysis {
+	a := analyze(password, v.leet)
+	if v.graphemes {
+		a.useGraphemes()
+	}
+	return a
+}
+
+// useGraphemes segments the password into grapheme clusters and recomputes
+// the statistics that count characters over clusters.
+func (a *analysis) useGraphemes() {
+	a.clusters = graphemeStarts(a.runes)
+
+	unique := make(map[string]bool)
+	a.maxRepeat, a.repeatAt, a.repeatEnd = 0, 0, 0
+	repeat := 0
+	for k := 0; k+1 < len(a.clusters); k++ {
+		c := a.cluster(k)
+		unique[c] = true
+		if k > 0 && c == a.cluster(k-1) {
+			repeat++
+		} else {
+			repeat = 1
+		}
+		if repeat > a.maxRepeat {
+			a.maxRepeat = repeat
+			a.repeatAt, a.repeatEnd = a.clusters[k-repeat+1], a.clusters[k+1]
+		}
+	}
+	a.uniqueRunes = len(unique)
+}
+
+// cluster returns grapheme cluster k of the lowercase password.
+func (a *analysis) cluster(k int) string {
+	return string(a.runes[a.clusters[k]:a.clusters[k+1]])
+}
+
+// clusterEntropy is the entropy of the password counted over grapheme
+// clusters, from the pool of the classes present or, in Shannon mode, from
+// the frequency of each cluster.
+func clusterEntropy(a *analysis, mode EntropyMode) float64 {
+	n := len(a.clusters) - 1
+	if n <= 0 {
+		return 0
+	}
+
+	if mode == EntropyShannon {
+		counts := make(map[string]int)
+		for k := range n {
+			counts[a.cluster(k)]++
+		}
+		perChar := 0.0
+		for _, c := range counts {
+			p := float64(c) / float64(n)
+			perChar -= p * math.Log2(p)
+		}
+		return float64(n) * perChar
+	}
+
+	var lower, upper, digit, symbol, emoji bool
+	for k := range n {
+		// The original case lives in password; runes holds the lowercase form.
+		r := a.rune(a.clusters[k])
+		switch {
+		case isEmojiRune(r):
+			emoji = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	pool := 0
+	for _, c := range []struct {
+		present bool
+		size    int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {emoji, emojiPoolSize}} {
+		if c.present {
+			pool += c.size
+		}
+	}
+	if pool <= 1 {
+		return 0
+	}
+	return float64(n) * math.Log2(float64(pool))
+}
+
+// graphemeStarts returns the index in runes where each grapheme cluster
+// starts, followed by len(runes).
+func graphemeStarts(runes []rune) []int {
+	if len(runes) == 0 {
+		return []int{0}
+	}
+	starts := []int{0}
+	pictographic := isExtendedPictographic(runes[0]) // the cluster so far is an emoji (ZWJ) sequence
+	regional := 0                                    // regional indicators in the current cluster
+	if isRegionalIndicator(runes[0]) {
+		regional = 1
+	}
+	for i := 1; i < len(runes); i++ {
+		prev, r := runes[i-1], runes[i]
+		join := false
+		switch {
+		case prev == '\r' && r == '\n':
+			join = true
+		case prev == '\r' || prev == '\n' || r == '\r' || r == '\n':
+			join = false
+		case isGraphemeExtend(r) || r == zwj:
+			join = true
+		case prev == zwj && pictographic && isExtendedPictographic(r):
+			join = true
+		case isRegionalIndicator(r) && regional == 1:
+			join = true
+		}
+		if join {
+			if isRegionalIndicator(r) {
+				regional++
+			}
+			continue
+		}
+		starts = append(starts, i)
+		pictographic = isExtendedPictographic(r)
+		regional = 0
+		if isRegionalIndicator(r) {
+			regional = 1
+		}
+	}
+	return append(starts, len(runes))
+}
+
+// zwj is the zero width joiner that glues emoji into one sequence.
+const zwj = '\u200d'
+
+// isGraphemeExtend reports whether r attaches to the preceding character:
+// combining and enclosing marks, spacing marks, variation selectors, emoji
+// skin tone modifiers and tag characters.
+func isGraphemeExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r >= 0xfe00 && r <= 0xfe0f ||
+		r >= 0x1f3fb && r <= 0x1f3ff ||
+		r >= 0xe0020 && r <= 0xe007f
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// isExtendedPictographic approximates the Extended_Pictographic property
+// with the blocks that hold emoji.
+func isExtendedPictographic(r rune) bool {
+	switch {
+	case r >= 0x1f000 && r <= 0x1faff && !isRegionalIndicator(r) && !(r >= 0x1f3fb && r <= 0x1f3ff):
+		return true
+	case r >= 0x2600 && r <= 0x27bf, r >= 0x2300 && r <= 0x23ff, r >= 0x2b00 && r <= 0x2bff:
+		return true
+	}
+	switch r {
+	case 0xa9, 0xae, 0x203c, 0x2049, 0x2122, 0x2139, 0x3030, 0x303d, 0x3297, 0x3299:
+		return true
+	}
+	return false
+}
+
+// isEmojiRune reports whether a cluster starting with r is an emoji or flag.
+func isEmojiRune(r rune) bool {
+	return isExtendedPictographic(r) || isRegionalIndicator(r)
+}
@@ -0,0 +1,55 @@
+package passval
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGraphemeStarts(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int // clusters
+	}{
+		{"abc", 3},
+		{"e\u0301te\u0301", 3}, // combining acute accents
+		{"\U0001F44D\U0001F3FD\U0001F44D\U0001F3FD", 2},           // skin tone modifiers
+		{"\U0001F469\u200d\U0001F469\u200d\U0001F467x", 2},        // ZWJ family
+		{"\U0001F1EA\U0001F1F8\U0001F1EB\U0001F1F7\U0001F1E9", 3}, // two flags and a lone indicator
+		{"\u2764\ufe0fa", 2}, // variation selector
+		{"a\r\nb", 3},
+	}
+	for _, tt := range tests {
+		if got := len(graphemeStarts([]rune(tt.s))) - 1; got != tt.want {
+			t.Errorf("graphemeStarts(%q) = %d clusters, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestGraphemeAnalysis(t *testing.T) {
+	plain := NewPasswordValidator(4, 64, false, false, false, false, 0)
+	v := plain.Clone(WithGraphemeAnalysis())
+
+	// Three thumbs up with a skin tone are 24 bytes but 3 characters.
+	thumbs := "👍🏽👍🏽👍🏽"
+	if codes := plain.ValidateResult(thumbs).Codes(); slices.Contains(codes, RuleTooShort) {
+		t.Errorf("byte length: unexpected %v", codes)
+	}
+	r := v.ValidateResult(thumbs)
+	if !slices.Contains(r.Codes(), RuleTooShort) {
+		t.Errorf("grapheme length: expected %s, got %v", RuleTooShort, r.Codes())
+	}
+	if !hasPenalty(r.Penalties, "repeated_chars") {
+		t.Errorf("repeated emoji should be penalized, got %v", r.Codes())
+	}
+
+	// Entropy counts each emoji once, from the emoji pool.
+	emoji := "🐶🍕🚀🎸🌵"
+	if got, bytes := v.ValidateResult(emoji).Entropy, plain.ValidateResult(emoji).Entropy; got >= bytes || got < 50 {
+		t.Errorf("emoji entropy = %.1f (bytes: %.1f), want about 5*log2(%d)", got, bytes, emojiPoolSize)
+	}
+
+	// ASCII passwords are unaffected apart from the length unit.
+	if a, b := plain.ValidateResult("Tr0ub4dor&3"), v.ValidateResult("Tr0ub4dor&3"); a.Score != b.Score {
+		t.Errorf("ASCII score changed: %d vs %d", a.Score, b.Score)
+	}
+}
@@ -0,0 +1,77 @@
+package passval
+
+import (
+	"math"
+	"time"
+)
+
+// AttackModel describes the guessing rate of an attacker, used to express
+// policies as crack times rather than abstract scores.
+type AttackModel int
+
+const (
+	// AttackOnlineThrottled is an online attack against a rate-limited service (100 guesses/hour).
+	AttackOnlineThrottled AttackModel = iota
+	// AttackOnlineUnthrottled is an online attack without rate limiting (10 guesses/second).
+	AttackOnlineUnthrottled
+	// AttackOfflineSlowHash is an offline attack against a slow hash such as bcrypt (10^4 guesses/second).
+	AttackOfflineSlowHash
+	// AttackOfflineFastHash is an offline attack against a fast hash such as SHA-1 (10^10 guesses/second).
+	AttackOfflineFastHash
+)
+
+// GuessesPerSecond returns the guessing rate assumed by the attack model.
+func (m AttackModel) GuessesPerSecond() float64 {
+	switch m {
+	case AttackOnlineThrottled:
+		return 100.0 / 3600.0
+	case AttackOnlineUnthrottled:
+		return 10
+	case AttackOfflineSlowHash:
+		return 1e4
+	case AttackOfflineFastHash:
+		return 1e10
+	}
+	return 1e10
+}
+
+// WithMinGuesses adds a rule that fails validation when the estimated number of
+// guesses needed to crack the password is below n (e.g. 1e10).
+func WithMinGuesses(n float64) Option {
+	return func(v *PasswordValidator) {
+		v.minGuesses = n
+	}
+}
+
+// WithMinCrackTime adds a rule that fails validation when the password is expected
+// to be cracked in less than d under the given attack model.
+func WithMinCrackTime(d time.Duration, model AttackModel) Option {
+	return WithMinGuesses(d.Seconds() * model.GuessesPerSecond())
+}
+
+// EstimateGuesses returns the estimated number of guesses an attacker needs to
+// find password, taking penalties into account.
+func (v *PasswordValidator) EstimateGuesses(password string) float64 {
+	r := v.validate(password)
+	return estimateGuesses(r.Entropy, r.Score)
+}
+
+// CrackTime returns the estimated time to crack password under the given attack model.
+func (v *PasswordValidator) CrackTime(password string, model AttackModel) time.Duration {
+	seconds := v.EstimateGuesses(password) / model.GuessesPerSecond()
+	if seconds >= math.MaxInt64/float64(time.Second) {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// estimateGuesses converts the penalized score back into effective entropy bits
+// (the inverse of entropyToScore) and returns 2^bits. A score that was not
+// reduced by penalties keeps the full raw entropy.
+func estimateGuesses(entropy float64, score int) float64 {
+	bits := entropy
+	if score < entropyToScore(entropy) {
+		bits = scoreToEntropy(score)
+	}
+	return math.Pow(2, bits)
+}
@@ -0,0 +1,106 @@
+package passval
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+//go:embed data/english_words.txt
+var englishWordsData string
+
+// WithEnglishWords penalizes passwords built on common English words
+// ("consider", "mountain") with the "natural_word" penalty, milder than
+// those of the common passwords dictionary: an everyday word narrows the
+// search to a vocabulary, while a breached password is among the first
+// guesses. The embedded list holds about 1,300 frequent words of at least 4
+// letters; words the dictionary lists are left to it. Passphrases under a
+// passphrase policy are not penalized, since they are made of words by design.
+func WithEnglishWords() Option {
+	return func(v *PasswordValidator) {
+		v.englishWords = true
+		v.language = loadLanguageWords(v.englishWords, v.languageWords)
+	}
+}
+
+// WithLanguageWords adds words to the natural-language wordlist of the
+// "natural_word" penalty, e.g. the most frequent words of the language of the
+// user base, or a larger English vocabulary. It can be combined with
+// WithEnglishWords.
+func WithLanguageWords(words ...string) Option {
+	return func(v *PasswordValidator) {
+		v.languageWords = append(v.languageWords, cleanWords(words)...)
+		v.language = loadLanguageWords(v.englishWords, v.languageWords)
+	}
+}
+
+// loadLanguageWords builds the matcher of the natural-language words: the
+// embedded English list if english is set, and words.
+func loadLanguageWords(english bool, words []string) *dictionary {
+	all := slices.Clone(words)
+	if english {
+		all = append(all, cleanWords(strings.Split(englishWordsData, "\n"))...)
+	}
+	return newDictionary(all)
+}
+
+// cleanWords returns the non-empty words of words, trimmed and lowercased.
+func cleanWords(words []string) []string {
+	var clean []string
+	for _, w := range words {
+		if w = strings.TrimSpace(strings.ToLower(w)); w != "" {
+			clean = append(clean, w)
+		}
+	}
+	return clean
+}
+
+// penaltyNaturalWord penalizes passwords built on natural-language words, by
+// the share of the password the non-overlapping words cover. Words the
+// common passwords dictionary lists are left to the dictionary penalties.
+func penaltyNaturalWord(a *analysis, language, dict *dictionary, cfg penaltyConfig) *PenaltyDetail {
+	if language == nil || cfg.passphrase {
+		return nil
+	}
+	var matches []dictMatch
+	for _, m := range cfg.scanWords(a, "language", language, a.forms()[0], cfg.minDictMatch()) {
+		if dict == nil || !dict.contains(m.word) {
+			matches = append(matches, m)
+		}
+	}
+	tiling, covered := dictTiling(matches)
+	if len(tiling) == 0 {
+		return nil
+	}
+
+	var factor float64
+	switch coverage := float64(covered) / float64(len(a.runes)); {
+	case coverage >= 0.8:
+		factor = 0.6
+	case coverage >= 0.5:
+		factor = 0.75
+	case coverage >= 0.3:
+		factor = 0.85
+	default:
+		return nil
+	}
+
+	words := dictWords(tiling)
+	var desc string
+	switch {
+	case len(words) == 1:
+		desc = cfg.describe(words[0], "password contains the common word '%s'",
+			"password contains a common word (%d chars)")
+	case cfg.redact:
+		desc = fmt.Sprintf("password contains %d common words", len(words))
+	default:
+		desc = fmt.Sprintf("password contains the common words '%s'", strings.Join(words, "', '"))
+	}
+	return a.spanned(&PenaltyDetail{
+		Rule:   "natural_word",
+		Factor: factor,
+		Desc:   desc,
+		Match:  strings.Join(words, "+"),
+	}, tiling[0].start, tiling[len(tiling)-1].end)
+}
@@ -0,0 +1,71 @@
+package passval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEnglishWords(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0, WithEnglishWords())
+
+	for _, tc := range []struct {
+		pwd    string
+		factor float64 // 0 for no natural_word penalty
+		match  string
+	}{
+		{"considerable", 0.6, "consider+able"},
+		{"Mountain7!", 0.6, "mountain"},
+		{"Mountain7!Qz", 0.75, "mountain"},
+		{"Xk9$mP2!vLq#island", 0.85, "island"},
+		{"mountainriver", 0.6, "mountain+river"},
+		{"dragon2024", 0, ""}, // a common password, left to the dictionary
+		{"Xk9$mP2!vLq#7", 0, ""},
+	} {
+		p := findPenalty(v.ValidateResult(tc.pwd).Penalties, "natural_word")
+		switch {
+		case tc.factor == 0 && p != nil:
+			t.Errorf("%q: unexpected penalty %+v", tc.pwd, p)
+		case tc.factor != 0 && (p == nil || p.Factor != tc.factor || p.Match != tc.match):
+			t.Errorf("%q: expected factor %.2f on %q, got %+v", tc.pwd, tc.factor, tc.match, p)
+		}
+	}
+
+	// Milder than the dictionary for the same coverage.
+	dict := findPenalty(v.ValidateResult("dragon2024").Penalties, "dictionary_substring")
+	word := findPenalty(v.ValidateResult("island2024").Penalties, "natural_word")
+	if dict == nil || word == nil || word.Factor <= dict.Factor {
+		t.Errorf("expected natural_word %+v to be milder than dictionary_substring %+v", word, dict)
+	}
+
+	if p := NewPasswordValidator(8, 64, false, false, false, false, 0).ValidateResult("Mountain7!").Penalties; hasPenalty(p, "natural_word") {
+		t.Errorf("natural_word applied without WithEnglishWords: %+v", p)
+	}
+	pp := NewPasswordValidator(8, 64, false, false, false, false, 0, WithEnglishWords(), WithPassphrasePolicy(3, 4))
+	if p := pp.ValidateResult("mountain river castle").Penalties; hasPenalty(p, "natural_word") {
+		t.Errorf("passphrases should not get natural_word: %+v", p)
+	}
+}
+
+func TestLanguageWords(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0, WithLanguageWords("Montaña", " ", "castillo\n"))
+	p := findPenalty(v.ValidateResult("castillo99").Penalties, "natural_word")
+	if p == nil || p.Match != "castillo" {
+		t.Errorf("expected a natural_word penalty for castillo, got %+v", p)
+	}
+	if p := v.ValidateResult("Mountain7!").Penalties; hasPenalty(p, "natural_word") {
+		t.Errorf("English words should not count without WithEnglishWords: %+v", p)
+	}
+
+	both := v.Clone(WithEnglishWords())
+	if got := both.Policy(); !got.EnglishWords || got.LanguageWords != 2 {
+		t.Errorf("Policy() = EnglishWords %v, LanguageWords %d; want true, 2", got.EnglishWords, got.LanguageWords)
+	}
+	if !hasPenalty(both.ValidateResult("Mountain7!").Penalties, "natural_word") {
+		t.Error("expected English words after WithEnglishWords")
+	}
+
+	r := NewPasswordValidator(8, 64, false, false, false, false, 0, WithEnglishWords(), WithRedactedMessages()).ValidateResult("mountainriver")
+	if p := findPenalty(r.Penalties, "natural_word"); p == nil || strings.Contains(p.Desc, "mountain") {
+		t.Errorf("expected a redacted description, got %+v", p)
+	}
+}
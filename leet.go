@@ -1,39 +1,92 @@
 package passval
 
-import "strings"
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
 
-// leetMap maps leet-speak characters to their possible letter equivalents.
-// Some characters map to multiple letters (ambiguous).
-var leetMap = map[rune][]rune{
-	'@': {'a'},
-	'4': {'a'},
-	'8': {'b'},
-	'(': {'c'},
-	'{': {'c'},
-	'3': {'e'},
-	'6': {'g'},
-	'#': {'h'},
-	'!': {'i'},
-	'1': {'i', 'l'},
-	'|': {'i', 'l'},
-	'0': {'o'},
-	'9': {'g', 'q'},
-	'5': {'s'},
-	'$': {'s'},
-	'7': {'t'},
-	'+': {'t'},
-	'2': {'z'},
-	'%': {'x'},
+// leetTable maps leet-speak and look-alike characters to the letters they can
+// stand for. A character may have several mappings (ambiguous), and a mapping
+// may be longer than one letter ('ß' → "ss").
+type leetTable map[rune][]string
+
+// leetMap is the default leet table.
+var leetMap = leetTable{
+	'@': {"a"},
+	'4': {"a"},
+	'8': {"b"},
+	'(': {"c"},
+	'{': {"c"},
+	'3': {"e"},
+	'6': {"g"},
+	'#': {"h"},
+	'!': {"i"},
+	'1': {"i", "l"},
+	'|': {"i", "l"},
+	'0': {"o"},
+	'9': {"g", "q"},
+	'5': {"s"},
+	'$': {"s"},
+	'7': {"t"},
+	'+': {"t"},
+	'2': {"z"},
+	'%': {"x"},
+
+	// Unicode substitutions and Cyrillic look-alikes
+	'€': {"e"},
+	'ß': {"ss"},
+	'£': {"l"},
+	'¡': {"i"},
+	'а': {"a"},
+	'е': {"e"},
+	'о': {"o"},
+	'р': {"p"},
+	'с': {"c"},
+	'х': {"x"},
+}
+
+// WithLeetMap extends the leet table used for dictionary, banned term and
+// profanity matching. Each entry maps a character to the letters it can stand
+// for, replacing any default mapping for that character, e.g.
+// WithLeetMap(map[rune][]string{'¥': {"y"}, 'æ': {"ae"}}). Matching runs on
+// the lowercased password, so characters and letters are lowercased; an entry
+// with no letters removes the mapping of its character.
+func WithLeetMap(m map[rune][]string) Option {
+	return func(v *PasswordValidator) {
+		table := make(leetTable, len(v.leet)+len(m))
+		for r, opts := range v.leet {
+			table[r] = opts
+		}
+		for r, opts := range m {
+			r = unicode.ToLower(r)
+			if len(opts) == 0 {
+				delete(table, r)
+				continue
+			}
+			lower := make([]string, len(opts))
+			for i, o := range opts {
+				lower[i] = strings.ToLower(o)
+			}
+			table[r] = lower
+		}
+		v.leet = table
+	}
 }
 
 // leetNormalize performs a single-pass normalization of leet-speak,
 // picking the first mapping for each character. This covers the most common cases.
 func leetNormalize(s string) string {
+	return leetMap.normalize(s)
+}
+
+// normalize replaces each character of s with its first mapping in t.
+func (t leetTable) normalize(s string) string {
 	var b strings.Builder
 	b.Grow(len(s))
 	for _, r := range s {
-		if replacements, ok := leetMap[r]; ok {
-			b.WriteRune(replacements[0]) // take first/most common mapping
+		if replacements, ok := t[r]; ok {
+			b.WriteString(replacements[0]) // take first/most common mapping
 		} else {
 			b.WriteRune(r)
 		}
@@ -41,71 +94,93 @@ func leetNormalize(s string) string {
 	return b.String()
 }
 
-// leetVariants generates multiple normalized versions of the input
-// by considering ambiguous mappings (e.g. '1' → 'i' or 'l').
-// Returns up to ~4 variants to keep it manageable.
-func leetVariants(s string) []string {
-	// Start with the basic normalization
-	primary := leetNormalize(s)
-	variants := map[string]bool{primary: true}
-
-	// Find ambiguous positions (chars with multiple mappings)
-	type ambiguity struct {
-		pos     int
-		options []rune
+// variants returns up to max readings of s, where each character is replaced
+// by one of its mappings in t or kept. Readings are ordered by the mappings'
+// order, so the first is t.normalize(s) and the last is s itself.
+func (t leetTable) variants(s string, max int) []string {
+	if max < 1 {
+		return nil
 	}
-	var ambiguities []ambiguity
-
-	runes := []rune(s)
-	for i, r := range runes {
-		if replacements, ok := leetMap[r]; ok && len(replacements) > 1 {
-			ambiguities = append(ambiguities, ambiguity{pos: i, options: replacements})
+	out := []string{""}
+	for _, r := range s {
+		opts := append(slices.Clone(t[r]), string(r))
+		next := make([]string, 0, min(len(out)*len(opts), max))
+		for _, prefix := range out {
+			for _, o := range opts {
+				if len(next) < max {
+					next = append(next, prefix+o)
+				}
+			}
 		}
+		out = next
 	}
+	return out
+}
 
-	// Generate variants for first 2 ambiguities (avoids explosion)
-	limit := len(ambiguities)
-	if limit > 2 {
-		limit = 2
-	}
+// LeetNormalize lowercases s and replaces each leet-speak or look-alike
+// character with the letter it most commonly stands for, as the validator
+// does before matching banned terms: LeetNormalize("P@$$w0rd") is "password".
+// Use the method of the same name to apply a table extended by WithLeetMap.
+func LeetNormalize(s string) string {
+	return leetMap.normalize(strings.ToLower(s))
+}
+
+// LeetVariants lowercases s and returns up to max of its readings, replacing
+// each leet-speak character with any letter it can stand for or keeping it:
+// LeetVariants("h1", 10) is ["hi", "hl", "h1"]. The first reading is
+// LeetNormalize(s). The count grows exponentially with the number of
+// ambiguous characters, so max should stay small.
+func LeetVariants(s string, max int) []string {
+	return leetMap.variants(strings.ToLower(s), max)
+}
+
+// LeetNormalize is like the package-level LeetNormalize using the validator's
+// leet table, including mappings added by WithLeetMap.
+func (v *PasswordValidator) LeetNormalize(s string) string {
+	return v.leet.normalize(strings.ToLower(s))
+}
+
+// LeetVariants is like the package-level LeetVariants using the validator's
+// leet table, including mappings added by WithLeetMap.
+func (v *PasswordValidator) LeetVariants(s string, max int) []string {
+	return v.leet.variants(strings.ToLower(s), max)
+}
 
-	if limit > 0 {
-		// Generate combinations
-		combos := [][]rune{{}}
-		for i := 0; i < limit; i++ {
-			var newCombos [][]rune
-			for _, combo := range combos {
-				for _, opt := range ambiguities[i].options {
-					newCombo := make([]rune, len(combo)+1)
-					copy(newCombo, combo)
-					newCombo[len(combo)] = opt
-					newCombos = append(newCombos, newCombo)
+// leetContains reports whether s contains term, where every character of s
+// may stand for any of its mappings in table. Ambiguous characters ('1' as
+// 'i' or 'l') are resolved per position by tracking the set of term offsets
+// reachable so far, so any number of them is handled without enumerating variants.
+func leetContains(s, term string, table leetTable) bool {
+	sr, tr := []rune(s), []rune(term)
+	if len(tr) == 0 {
+		return true
+	}
+	for i := range sr {
+		reach := []int{0} // offsets into tr matched so far
+		for _, c := range sr[i:] {
+			var next []int
+			add := func(j int) {
+				if !slices.Contains(next, j) {
+					next = append(next, j)
 				}
 			}
-			combos = newCombos
-		}
-
-		for _, combo := range combos {
-			result := make([]rune, len(runes))
-			// Start with primary normalization
-			for i, r := range runes {
-				if replacements, ok := leetMap[r]; ok {
-					result[i] = replacements[0]
-				} else {
-					result[i] = r
+			for _, j := range reach {
+				if tr[j] == c {
+					add(j + 1)
+				}
+				for _, opt := range table[c] {
+					if o := []rune(opt); j+len(o) <= len(tr) && string(tr[j:j+len(o)]) == opt {
+						add(j + len(o))
+					}
 				}
 			}
-			// Apply ambiguous choices
-			for i := 0; i < limit; i++ {
-				result[ambiguities[i].pos] = combo[i]
+			if slices.Contains(next, len(tr)) {
+				return true
+			}
+			if reach = next; len(reach) == 0 {
+				break
 			}
-			variants[string(result)] = true
 		}
 	}
-
-	out := make([]string, 0, len(variants))
-	for v := range variants {
-		out = append(out, v)
-	}
-	return out
+	return false
 }
@@ -0,0 +1,43 @@
+package passval
+
+// Metrics receives validation and generation outcomes so that policy friction
+// can be monitored in production, e.g. by exporting them as Prometheus
+// counters and histograms. Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveValidation is called once per Validate, ValidateVerbose or
+	// ValidateResult call with the outcome, the final score and the reason
+	// codes of failed rules and applied penalties.
+	ObserveValidation(pass bool, score int, codes []string)
+	// ObserveGeneration is called once per Generate call with the number of
+	// candidates tried and the returned error, if any.
+	ObserveGeneration(attempts int, err error)
+}
+
+// WithMetrics reports validation and generation outcomes to m.
+func WithMetrics(m Metrics) Option {
+	return func(v *PasswordValidator) {
+		v.metrics = m
+	}
+}
+
+// observe validates password and reports the outcome to the configured metrics.
+func (v *PasswordValidator) observe(password string) *Result {
+	r := v.validate(password)
+	v.report(r)
+	return r
+}
+
+// report sends a validation outcome to the configured metrics and event sink.
+func (v *PasswordValidator) report(r *Result) {
+	if v.metrics != nil {
+		v.metrics.ObserveValidation(r.Pass, r.Score, r.Codes())
+	}
+	v.emitValidation(r)
+}
+
+func (v *PasswordValidator) observeGeneration(attempts int, err error) {
+	if v.metrics != nil {
+		v.metrics.ObserveGeneration(attempts, err)
+	}
+	v.emitGeneration(attempts, err)
+}
@@ -0,0 +1,63 @@
+package passval
+
+import (
+	"sync"
+	"testing"
+)
+
+type recordingMetrics struct {
+	mu          sync.Mutex
+	validations int
+	failures    map[string]int
+	scores      []int
+	generations int
+	attempts    int
+}
+
+func (m *recordingMetrics) ObserveValidation(pass bool, score int, codes []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.validations++
+	m.scores = append(m.scores, score)
+	if !pass {
+		for _, c := range codes {
+			m.failures[c]++
+		}
+	}
+}
+
+func (m *recordingMetrics) ObserveGeneration(attempts int, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.generations++
+	m.attempts += attempts
+}
+
+func TestWithMetrics(t *testing.T) {
+	m := &recordingMetrics{failures: make(map[string]int)}
+	v := NewPasswordValidator(8, 64, true, true, true, true, 40, WithMetrics(m))
+
+	v.Validate("password")
+	v.ValidateVerbose("Xk9$mP2!vLq")
+	v.ValidateResult("short")
+
+	if m.validations != 3 {
+		t.Errorf("expected 3 validations, got %d", m.validations)
+	}
+	if m.failures[RuleTooShort] != 1 {
+		t.Errorf("expected 1 %s failure, got %d", RuleTooShort, m.failures[RuleTooShort])
+	}
+	if m.failures["common_password"] != 1 {
+		t.Errorf("expected 1 common_password failure, got %d", m.failures["common_password"])
+	}
+
+	if _, err := v.Generate(); err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if m.generations != 1 || m.attempts < 1 {
+		t.Errorf("expected 1 generation with attempts, got %d/%d", m.generations, m.attempts)
+	}
+	if m.validations != 3 {
+		t.Errorf("generation candidates should not count as validations, got %d", m.validations)
+	}
+}
@@ -0,0 +1,236 @@
+package passval
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// Option configures optional behaviour of a PasswordValidator.
+// Options are applied in order by the constructors, after the positional rules.
+type Option func(*PasswordValidator)
+
+// EntropyMode selects the estimator used to compute the raw entropy bits of a password.
+type EntropyMode int
+
+const (
+	// EntropyPool estimates entropy as length × log2(pool_size), where the pool is
+	// derived from the character classes present. This is the default.
+	EntropyPool EntropyMode = iota
+	// EntropyShannon estimates entropy from the actual character frequency
+	// distribution of the password (length × Shannon entropy per character),
+	// so skewed inputs like "aaaaaaA1!" score far below a random string of the same length.
+	EntropyShannon
+)
+
+// String returns the name of the entropy mode.
+func (m EntropyMode) String() string {
+	switch m {
+	case EntropyPool:
+		return "pool"
+	case EntropyShannon:
+		return "shannon"
+	}
+	return fmt.Sprintf("EntropyMode(%d)", int(m))
+}
+
+// WithEntropyMode selects the entropy estimator used for scoring.
+func WithEntropyMode(mode EntropyMode) Option {
+	return func(v *PasswordValidator) {
+		v.entropyMode = mode
+	}
+}
+
+// ambiguousChars are characters that are easily confused when a password is
+// read aloud or transcribed by hand: O/0/o, l/1/I/|, and quote characters.
+const ambiguousChars = "O0oIl1|`'\""
+
+// WithExcludeAmbiguous makes the generator avoid visually ambiguous characters
+// (O/0, l/1/I, backtick and quotes).
+func WithExcludeAmbiguous() Option {
+	return WithExcludeChars(ambiguousChars)
+}
+
+// WithExcludeChars makes the generator avoid every character in chars.
+// It can be combined with WithExcludeAmbiguous.
+func WithExcludeChars(chars string) Option {
+	return func(v *PasswordValidator) {
+		v.excludeChars += chars
+	}
+}
+
+// WithGenerateNoPenalties makes Generate, GenerateN, GenerateWithInfo,
+// GenerateWithEntropy and GenerateInto reject candidates that any penalty
+// applies to, such as a "1234" run or a dictionary word, even if they pass the
+// policy, so that generated passwords hold up to an audit of their patterns.
+// Passphrases and template passwords are not affected.
+func WithGenerateNoPenalties() Option {
+	return func(v *PasswordValidator) {
+		v.genNoPenalties = true
+	}
+}
+
+// WithAllowedSymbols restricts symbols to the given set: the generator only uses
+// these symbols and validation fails for any other symbol in the password,
+// listed in the message except for ValidateBytes, which gives their count and
+// position.
+func WithAllowedSymbols(symbols string) Option {
+	return func(v *PasswordValidator) {
+		v.allowedSymbols = symbols
+	}
+}
+
+// WithDisallowedChars fails validation, with code RuleDisallowedChar, for
+// passwords containing any character of chars, for legacy systems that break
+// on some characters, e.g. WithDisallowedChars(`"'\` + " ") for quotes,
+// backslashes and spaces. The generator avoids them too. The message lists the
+// offending characters, except for ValidateBytes, where it gives their count
+// and position as WithASCIIOnly does.
+func WithDisallowedChars(chars string) Option {
+	return func(v *PasswordValidator) {
+		v.forbiddenChars += chars
+		v.excludeChars += chars
+	}
+}
+
+// WithASCIIOnly fails validation, with code RuleNonASCII, for passwords
+// containing characters outside ASCII, for backends (RADIUS, legacy LDAP) that
+// mangle them. The message gives the count and position of the characters,
+// not the characters themselves.
+func WithASCIIOnly() Option {
+	return func(v *PasswordValidator) {
+		v.asciiOnly = true
+	}
+}
+
+// WithPrintableOnly fails validation, with code RuleNonPrintable, for passwords
+// containing control or invisible characters, including tabs and spaces other
+// than the ordinary U+0020. Combine it with WithASCIIOnly to accept printable
+// ASCII only.
+func WithPrintableOnly() Option {
+	return func(v *PasswordValidator) {
+		v.printableOnly = true
+	}
+}
+
+// WithMinClassCounts sets the minimum number of lowercase letters, uppercase
+// letters, digits and symbols a password must contain (e.g. at least two of each).
+// Generate satisfies the same minimums.
+func WithMinClassCounts(lower, upper, digits, symbols int) Option {
+	return func(v *PasswordValidator) {
+		v.MinLower = lower
+		v.MinUpper = upper
+		v.MinDigits = digits
+		v.MinSymbols = symbols
+	}
+}
+
+// WithMinCharClasses requires at least n of the four character classes
+// (lowercase, uppercase, digits, symbols), e.g. "3 of 4" corporate policies.
+// Generate includes enough classes to satisfy the rule.
+func WithMinCharClasses(n int) Option {
+	return func(v *PasswordValidator) {
+		if n > 4 {
+			n = 4
+		}
+		v.minCharClasses = n
+	}
+}
+
+// WithMinUniqueChars fails validation when the password has fewer than n
+// distinct characters, e.g. to reject "aaaaaaaa1!" outright rather than only
+// lowering its score. Letters differing only in case count once. Unlike the
+// class requirements, it also applies to passphrases and exempt lengths.
+func WithMinUniqueChars(n int) Option {
+	return func(v *PasswordValidator) {
+		v.minUnique = n
+	}
+}
+
+// WithLengthExemption waives the number, symbol and character class requirements
+// for passwords of at least n characters, following NIST guidance that favours
+// length over composition.
+func WithLengthExemption(n int) Option {
+	return func(v *PasswordValidator) {
+		v.exemptLength = n
+	}
+}
+
+// BcryptMaxBytes is the number of bytes bcrypt hashes; anything longer is silently truncated.
+const BcryptMaxBytes = 72
+
+// WithMaxBytes fails validation when the UTF-8 encoding of the password is
+// longer than n bytes, independently of MaxLength. Use WithMaxBytes(BcryptMaxBytes)
+// to catch passphrases that would be truncated at hash time.
+func WithMaxBytes(n int) Option {
+	return func(v *PasswordValidator) {
+		v.maxBytes = n
+	}
+}
+
+// WithPenaltyInputLimit runs the penalty detectors on at most the first n
+// bytes of the password, bounding the cost of the dictionary and pattern
+// scans, which grow with the square of the input, for pathological inputs such
+// as 10 KB "passwords" sent to a login endpoint. Longer passwords are still
+// checked by every rule and scored by entropy in full, and get a
+// WarnPartialScan warning. Use it together with WithMaxBytes to reject them.
+func WithPenaltyInputLimit(n int) Option {
+	return func(v *PasswordValidator) {
+		v.penaltyLimit = n
+	}
+}
+
+// WithAnalysisLimits bounds the worst-case CPU cost of validating untrusted
+// input. Only the first maxAnalyzedRunes characters are analyzed for character
+// classes, rules, entropy and penalties, while the length rules (MinLength,
+// MaxLength, WithMaxBytes) still see the full input; longer passwords get a
+// WarnTruncated warning. maxDictScanWords caps the dictionary words collected
+// while scanning the password, bounding the work on inputs that contain
+// thousands of overlapping words. 0 leaves a limit off.
+func WithAnalysisLimits(maxAnalyzedRunes, maxDictScanWords int) Option {
+	return func(v *PasswordValidator) {
+		v.maxAnalyzed = maxAnalyzedRunes
+		v.maxDictWords = maxDictScanWords
+	}
+}
+
+// WithPenaltyTimeBudget stops running penalty detectors once d has passed
+// since validation started; the remaining detectors are skipped and the
+// result gets a WarnPartialScan warning. A detector that has started runs to
+// completion, so pair it with WithPenaltyInputLimit to bound the overrun.
+func WithPenaltyTimeBudget(d time.Duration) Option {
+	return func(v *PasswordValidator) {
+		v.penaltyBudget = d
+	}
+}
+
+// WithRandSource makes Generate read randomness from r instead of crypto/rand,
+// so tests and simulations can reproduce generated passwords from a seeded
+// source. Production code should leave the default in place. r must be safe
+// for concurrent use if Generate is called from several goroutines.
+func WithRandSource(r io.Reader) Option {
+	return func(v *PasswordValidator) {
+		v.randSource = r
+	}
+}
+
+// WithSingleClassPenalty applies a penalty with the given factor (0-1, e.g.
+// 0.5) to passwords made entirely of one character class, such as all
+// lowercase letters or all digits, regardless of length. Such passwords are
+// always reported with a WarnSingleClass warning; the penalty also lowers
+// their score, for NIST-style policies without composition rules.
+func WithSingleClassPenalty(factor float64) Option {
+	return func(v *PasswordValidator) {
+		v.singleClass = min(max(factor, 0), 1)
+	}
+}
+
+// WithRedactedMessages keeps matched dictionary words out of penalty
+// descriptions, which then give only the word length ("contains a common
+// dictionary word (6 chars)"), so that validation errors can be logged.
+// PenaltyDetail.Match and the Start/End span remain available to the caller.
+func WithRedactedMessages() Option {
+	return func(v *PasswordValidator) {
+		v.redact = true
+	}
+}
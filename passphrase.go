@@ -0,0 +1,407 @@
+package passval
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WithPassphrasePolicy enables passphrase-aware validation. A password made of at
+// least minWords words of at least minWordLen characters is scored by word count
+// and wordlist size instead of character pool, and is exempt from the number and
+// symbol requirements. Words are the runs of letters: spaces, hyphens, digits,
+// symbols and any other non-letters separate them ("maple7orbit!canyon"). Digits
+// in leet-speak split words as well, so "P4ssw0rd" is the fragments "P", "ssw"
+// and "rd", which only count if they reach minWordLen.
+func WithPassphrasePolicy(minWords, minWordLen int) Option {
+	return func(v *PasswordValidator) {
+		if minWords < 1 {
+			minWords = 1
+		}
+		if minWordLen < 1 {
+			minWordLen = 1
+		}
+		v.passphraseMinWords = minWords
+		v.passphraseMinWordLen = minWordLen
+	}
+}
+
+// passphraseWords returns the words of password and whether it qualifies as a
+// passphrase under the configured passphrase policy. With WithWordlist, a
+// password without separators qualifies if it splits into list words.
+func (v *PasswordValidator) passphraseWords(password string) ([]string, bool) {
+	if v.passphraseMinWords == 0 {
+		return nil, false
+	}
+
+	var words []string
+	parts := splitPassphrase(password)
+	for _, w := range parts {
+		if utf8.RuneCountInString(w) >= v.passphraseMinWordLen {
+			words = append(words, w)
+		}
+	}
+	if len(words) < v.passphraseMinWords && len(parts) == 1 && v.wordlist != nil {
+		if segmented, ok := segmentWords(parts[0], v.wordlist, v.passphraseMinWordLen); ok {
+			words = segmented
+		}
+	}
+	return words, len(words) >= v.passphraseMinWords
+}
+
+// passphraseListSize returns the number of words passphrases are assumed to
+// be drawn from: the configured wordlist, or the embedded one.
+func (v *PasswordValidator) passphraseListSize() int {
+	if v.wordlist != nil {
+		return v.wordlist.Len()
+	}
+	return defaultWordlist.Len()
+}
+
+// splitPassphrase splits a passphrase into words on the common separators
+// and on digits and symbols standing in for them ("maple7orbit!canyon").
+func splitPassphrase(s string) []string {
+	return strings.FieldsFunc(s, isPassphraseSeparator)
+}
+
+func isPassphraseSeparator(r rune) bool {
+	return !unicode.IsLetter(r)
+}
+
+// passphraseEntropy computes the entropy bits of a passphrase as
+// unique_words × log2(listSize). Repeated words add no entropy.
+func passphraseEntropy(words []string, listSize int) float64 {
+	unique := make(map[string]bool, len(words))
+	for _, w := range words {
+		unique[strings.ToLower(w)] = true
+	}
+	return float64(len(unique)) * math.Log2(float64(listSize))
+}
+
+//go:embed data/passphrase_words.txt
+var passphraseWordsData string
+
+// defaultWordlist is the embedded wordlist used for passphrase generation: a
+// sample of 1,292 words, about 10.3 bits per word, not the 7,776-word EFF long
+// list (12.9 bits), which must be loaded with LoadWordlist.
+var defaultWordlist = newWordlist(strings.Split(passphraseWordsData, "\n"))
+
+// PassphraseOption configures GeneratePassphrase.
+type PassphraseOption func(*passphraseConfig)
+
+type passphraseConfig struct {
+	wordlist   Wordlist
+	capitalize bool
+	digits     int
+	symbols    int
+	injection  PassphraseInjection
+	digitSet   string
+	symbolSet  string
+	random     io.Reader
+	profanity  []bannedTerm
+}
+
+// PassphraseInjection selects where GeneratePassphrase places the digits and
+// symbols of WithPassphraseDigits and WithPassphraseSymbols.
+type PassphraseInjection int
+
+const (
+	// InjectWordEnd appends each character to the end of a random word,
+	// e.g. "maple7-orbit-canyon!". It is the default of the package-level
+	// GeneratePassphrase.
+	InjectWordEnd PassphraseInjection = iota
+	// InjectSeparators puts the characters between words in place of the
+	// separator, e.g. "maple7orbit!canyon", keeping words intact. It is the
+	// default of PasswordValidator.GeneratePassphrase. A single-word
+	// passphrase gets them as a suffix.
+	InjectSeparators
+	// InjectSuffix appends the characters as one block after the last word,
+	// e.g. "maple-orbit-canyon-7!".
+	InjectSuffix
+)
+
+// WithPassphraseInjection sets where injected digits and symbols go.
+func WithPassphraseInjection(mode PassphraseInjection) PassphraseOption {
+	return func(c *passphraseConfig) {
+		c.injection = mode
+	}
+}
+
+// WithPassphraseWordlist draws the words from wl instead of the embedded
+// list, e.g. a corporate-approved list or an EFF list read with LoadWordlist.
+func WithPassphraseWordlist(wl Wordlist) PassphraseOption {
+	return func(c *passphraseConfig) {
+		c.wordlist = wl
+	}
+}
+
+// WithPassphraseCapitalize capitalizes the first letter of every word.
+func WithPassphraseCapitalize() PassphraseOption {
+	return func(c *passphraseConfig) {
+		c.capitalize = true
+	}
+}
+
+// WithPassphraseDigits appends n random digits, each to a randomly chosen word.
+func WithPassphraseDigits(n int) PassphraseOption {
+	return func(c *passphraseConfig) {
+		c.digits = n
+	}
+}
+
+// WithPassphraseSymbols appends n random symbols, each to a randomly chosen word.
+func WithPassphraseSymbols(n int) PassphraseOption {
+	return func(c *passphraseConfig) {
+		c.symbols = n
+	}
+}
+
+// maxPassphraseAttempts bounds how often GeneratePassphrase retries after
+// its output is rejected by the profanity screen.
+const maxPassphraseAttempts = 100
+
+// GeneratePassphrase creates a passphrase of the given number of words picked
+// uniformly with crypto/rand from the embedded wordlist, joined by sep. Words
+// from the embedded list carry about 10.3 bits each; pass an EFF list loaded
+// with LoadWordlist to WithPassphraseWordlist for 12.9.
+// It returns the passphrase and its entropy in bits, computed from the wordlist
+// size and the randomness of any injected digits or symbols, as a lower
+// bound when their placement is random. Passphrases that
+// spell an offensive term, as a word or across words, are regenerated.
+func GeneratePassphrase(words int, sep string, opts ...PassphraseOption) (string, float64, error) {
+	return generatePassphrase(words, sep, newPassphraseConfig(opts))
+}
+
+func generatePassphrase(words int, sep string, cfg passphraseConfig) (string, float64, error) {
+	if words < 1 {
+		return "", 0, fmt.Errorf("passphrase needs at least 1 word, got %d", words)
+	}
+	for i := 0; i < maxPassphraseAttempts; i++ {
+		p, err := passphraseParts(words, sep, cfg)
+		if err != nil {
+			return "", 0, err
+		}
+		if offensivePassphrase(p.words, cfg.profanity) {
+			continue
+		}
+		return p.String(), p.entropy, nil
+	}
+	return "", 0, fmt.Errorf("failed to generate a passphrase after %d attempts", maxPassphraseAttempts)
+}
+
+// GeneratePassphrase creates a passphrase like the package-level function
+// that also satisfies the validator's policy: the digits and symbols the
+// policy requires are placed between words (see WithPassphraseInjection),
+// drawn from the generation charsets, words are capitalized if uppercase
+// letters are required, and the validator's wordlist, profanity list and
+// random source are used. opts override these defaults. Candidates that fail
+// validation are regenerated.
+func (v *PasswordValidator) GeneratePassphrase(words int, sep string, opts ...PassphraseOption) (string, float64, error) {
+	_, _, number, symbol := v.generationCharsets()
+	cfg := passphraseConfig{
+		wordlist:   v.wordlist,
+		capitalize: minClassCount(v.MinUpper, v.RequireUpper) > 0,
+		digits:     minClassCount(v.MinDigits, v.RequireNumbers),
+		symbols:    minClassCount(v.MinSymbols, v.RequireSymbols),
+		injection:  InjectSeparators,
+		digitSet:   number,
+		symbolSet:  symbol,
+		random:     v.random(),
+		profanity:  v.profanity,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.setDefaults()
+	switch {
+	case cfg.digits > 0 && cfg.digitSet == "":
+		return "", 0, fmt.Errorf("no digits left to generate after exclusions")
+	case cfg.symbols > 0 && cfg.symbolSet == "":
+		return "", 0, fmt.Errorf("no symbols left to generate after exclusions")
+	}
+
+	for i := 0; i < maxGenerateAttempts; i++ {
+		phrase, entropy, err := generatePassphrase(words, sep, cfg)
+		if err != nil {
+			return "", 0, err
+		}
+		if v.validate(phrase).Pass {
+			return phrase, entropy, nil
+		}
+	}
+	return "", 0, fmt.Errorf("failed to generate a valid passphrase after %d attempts", maxGenerateAttempts)
+}
+
+// GeneratedPassphrase is a generated passphrase with both measures of its
+// strength, for security reviews.
+type GeneratedPassphrase struct {
+	Passphrase   string
+	Words        int // number of words
+	WordlistSize int // number of words each word was drawn from
+	// Entropy is the passphrase's real entropy in bits: Words × log2(WordlistSize)
+	// plus the randomness of any injected digits or symbols. Report this figure.
+	Entropy float64
+	// CharacterEntropy is what the character-pool estimate used for typed
+	// passwords gives for the same string, length × log2(pool). It overstates
+	// the strength of a passphrase, whose characters are not chosen one by one,
+	// and is shown only for comparison.
+	CharacterEntropy float64
+}
+
+// GeneratePassphraseWithInfo is like GeneratePassphrase but returns the
+// wordlist entropy alongside the character-pool estimate of the passphrase.
+func GeneratePassphraseWithInfo(words int, sep string, opts ...PassphraseOption) (*GeneratedPassphrase, error) {
+	phrase, entropy, err := GeneratePassphrase(words, sep, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &GeneratedPassphrase{
+		Passphrase:       phrase,
+		Words:            words,
+		WordlistSize:     newPassphraseConfig(opts).wordlist.Len(),
+		Entropy:          entropy,
+		CharacterEntropy: calculateEntropy(phrase),
+	}, nil
+}
+
+func newPassphraseConfig(opts []PassphraseOption) passphraseConfig {
+	cfg := passphraseConfig{digitSet: numberChars, symbolSet: symbolChars, profanity: defaultProfanity}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.setDefaults()
+	return cfg
+}
+
+// setDefaults fills in the wordlist and random source if unset.
+func (c *passphraseConfig) setDefaults() {
+	if c.wordlist == nil {
+		c.wordlist = defaultWordlist
+	}
+	if c.random == nil {
+		c.random = rand.Reader
+	}
+}
+
+// passphrase is a generated passphrase candidate: its words, each with any
+// digits and symbols appended to it, the separator after each word but the
+// last, and a block of digits and symbols after the last word.
+type passphrase struct {
+	words   []string
+	seps    []string
+	sep     string
+	suffix  string
+	entropy float64
+}
+
+func (p passphrase) String() string {
+	var b strings.Builder
+	for i, w := range p.words {
+		b.WriteString(w)
+		if i < len(p.seps) {
+			b.WriteString(p.seps[i])
+		}
+	}
+	if p.suffix != "" {
+		b.WriteString(p.sep + p.suffix)
+	}
+	return b.String()
+}
+
+// passphraseParts picks the words of one passphrase candidate and places any
+// injected digits and symbols, and returns the candidate with its entropy.
+func passphraseParts(words int, sep string, cfg passphraseConfig) (passphrase, error) {
+	p := passphrase{words: make([]string, words), seps: make([]string, words-1), sep: sep}
+	for i := range p.words {
+		n, err := randIndexFrom(cfg.random, cfg.wordlist.Len())
+		if err != nil {
+			return p, err
+		}
+		p.words[i] = cfg.wordlist.WordAt(n)
+		if cfg.capitalize {
+			r, size := utf8.DecodeRuneInString(p.words[i])
+			p.words[i] = string(unicode.ToUpper(r)) + p.words[i][size:]
+		}
+	}
+	for i := range p.seps {
+		p.seps[i] = sep
+	}
+	p.entropy = float64(words) * math.Log2(float64(cfg.wordlist.Len()))
+
+	// Each injected character picks a gap or word, but characters of one set
+	// placed apart cannot be told in which order they were placed, so up to
+	// log2(count!) of those bits are not in the output. Crediting the
+	// placements less that keeps the entropy a lower bound.
+	var placement float64
+	replaced := make([]bool, len(p.seps))
+	inject := func(count int, set string) error {
+		chars := []rune(set)
+		for i := 0; i < count; i++ {
+			c, err := randIndexFrom(cfg.random, len(chars))
+			if err != nil {
+				return err
+			}
+			p.entropy += math.Log2(float64(len(chars)))
+
+			switch {
+			case cfg.injection == InjectSeparators && len(p.seps) > 0:
+				g, err := randIndexFrom(cfg.random, len(p.seps))
+				if err != nil {
+					return err
+				}
+				if !replaced[g] {
+					p.seps[g], replaced[g] = "", true
+				}
+				p.seps[g] += string(chars[c])
+				placement += math.Log2(float64(len(p.seps)))
+			case cfg.injection == InjectSuffix || cfg.injection == InjectSeparators:
+				p.suffix += string(chars[c])
+			default:
+				w, err := randIndexFrom(cfg.random, words)
+				if err != nil {
+					return err
+				}
+				p.words[w] += string(chars[c])
+				placement += math.Log2(float64(words))
+			}
+		}
+		if cfg.injection != InjectSuffix && (cfg.injection != InjectSeparators || len(p.seps) > 0) {
+			placement -= log2Factorial(count)
+		}
+		return nil
+	}
+	if err := inject(cfg.digits, cfg.digitSet); err != nil {
+		return p, err
+	}
+	if err := inject(cfg.symbols, cfg.symbolSet); err != nil {
+		return p, err
+	}
+	p.entropy += max(placement, 0)
+	return p, nil
+}
+
+// log2Factorial returns log2(n!).
+func log2Factorial(n int) float64 {
+	lg, _ := math.Lgamma(float64(n + 1))
+	return lg / math.Ln2
+}
+
+// randIndex returns a uniform random integer in [0, n) read from crypto/rand.
+func randIndex(n int) (int, error) {
+	return randIndexFrom(rand.Reader, n)
+}
+
+// randIndexFrom returns a uniform random integer in [0, n) read from r.
+func randIndexFrom(r io.Reader, n int) (int, error) {
+	i, err := rand.Int(r, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, err
+	}
+	return int(i.Int64()), nil
+}
@@ -0,0 +1,61 @@
+package passvalfuzz
+
+import (
+	"crypto/sha256"
+	"math/rand/v2"
+	"testing"
+
+	passval "github.com/fernandezvara/passvalidator"
+)
+
+// seedPasswords are the seed corpus of FuzzValidate: weak and strong
+// passwords, patterns the penalties look for, passphrases, non-ASCII and
+// invalid UTF-8 input.
+var seedPasswords = []string{
+	"", "a", "password", "P@ssw0rd", "Summer2024!", "qwerty123", "aaaaaaaa",
+	"abcabcabc", "12/05/1987", "+1 555 123 4567", "john@example.com",
+	"correct horse battery staple", "Xk9$mP2!vLq#7", "ñandú-Ωmega-密码",
+	"éé", "\x00\t\n", "\xff\xfe", "👍🏽👍🏽👍🏽",
+}
+
+// FuzzValidate fuzzes v with arbitrary passwords, seeded with a corpus of
+// typical and edge-case input plus seeds, and fails on any input whose
+// result breaks an invariant of CheckValidate.
+func FuzzValidate(f *testing.F, v passval.Validator, seeds ...string) {
+	for _, s := range append(seedPasswords, seeds...) {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, password string) {
+		if err := CheckValidate(v, password); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+// FuzzGeneratePolicy fuzzes the length, class and complexity settings of a
+// policy, with opts applied on top, and fails if a policy NewValidatorStrict
+// accepts generates a password that breaks an invariant of CheckGenerate.
+// The random source is derived from the fuzzed input, so failures reproduce.
+func FuzzGeneratePolicy(f *testing.F, opts ...passval.Option) {
+	f.Add(uint8(8), uint8(64), uint8(0b1111), uint8(50), []byte("seed"))
+	f.Add(uint8(1), uint8(0), uint8(0), uint8(0), []byte{})
+	f.Add(uint8(12), uint8(12), uint8(0b0101), uint8(80), []byte{1, 2, 3})
+	f.Add(uint8(4), uint8(4), uint8(0b1111), uint8(100), []byte("x"))
+	f.Fuzz(func(t *testing.T, minLen, maxLen, classes, complexity uint8, seed []byte) {
+		min := int(minLen)%64 + 1
+		max := passval.NoMax
+		if maxLen != 0 {
+			max = min + int(maxLen)%64
+		}
+		opts := append(opts[:len(opts):len(opts)], passval.WithRandSource(rand.NewChaCha8(sha256.Sum256(seed))))
+		v, err := passval.NewValidatorStrict(min, max,
+			classes&1 != 0, classes&2 != 0, classes&4 != 0, classes&8 != 0,
+			int(complexity)%101, opts...)
+		if err != nil {
+			return
+		}
+		if err := CheckGenerate(v); err != nil {
+			t.Error(err)
+		}
+	})
+}
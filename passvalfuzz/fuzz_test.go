@@ -0,0 +1,50 @@
+package passvalfuzz_test
+
+import (
+	"strings"
+	"testing"
+
+	passval "github.com/fernandezvara/passvalidator"
+	"github.com/fernandezvara/passvalidator/passvalfuzz"
+)
+
+func FuzzValidate(f *testing.F) {
+	passvalfuzz.FuzzValidate(f, passval.NewPasswordValidator(8, 64, true, true, true, true, 50))
+}
+
+func FuzzValidatePassphrase(f *testing.F) {
+	v := passval.NewPasswordValidator(12, passval.NoMax, false, false, false, false, 40,
+		passval.WithPassphrasePolicy(4, 3), passval.WithPenaltyInputLimit(32), passval.WithTopicalWordlists())
+	passvalfuzz.FuzzValidate(f, v, "liverpool liverpool liverpool")
+}
+
+func FuzzGeneratePolicy(f *testing.F) {
+	passvalfuzz.FuzzGeneratePolicy(f)
+}
+
+func FuzzGeneratePreset(f *testing.F) {
+	passvalfuzz.FuzzGeneratePolicy(f, passval.WithPreset(passval.PresetOracleDB), passval.WithDisallowedChars("#"))
+}
+
+func TestCheckResult(t *testing.T) {
+	ok := &passval.Result{Pass: true, Score: 70, Strength: passval.StrengthStrong}
+	if err := passvalfuzz.CheckResult("Xk9$mP2!", ok); err != nil {
+		t.Errorf("consistent result: %v", err)
+	}
+
+	bad := &passval.Result{
+		Pass:      true,
+		Score:     101,
+		RuleFails: []passval.RuleFail{{Code: passval.RuleTooShort, Message: "too short"}},
+		Penalties: []passval.PenaltyDetail{{Rule: "repeated_chars", Factor: -0.5, Start: 2, End: 20}},
+	}
+	err := passvalfuzz.CheckResult("aaaa", bad)
+	if err == nil {
+		t.Fatal("expected violations")
+	}
+	for _, want := range []string{"outside 0-100", "no strength label", "passes with failed rules", "factor -0.5", "spans 2-20"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Package passvalfuzz checks the invariants every passval validator must keep,
+// whatever its configuration, and provides native Go fuzz targets built on
+// them, so that projects can fuzz their own policies in CI:
+//
+//	func FuzzPasswordPolicy(f *testing.F) {
+//		passvalfuzz.FuzzValidate(f, newSignupValidator())
+//	}
+//
+//	func FuzzGeneratedPasswords(f *testing.F) {
+//		passvalfuzz.FuzzGeneratePolicy(f, passval.WithPreset(passval.PresetLDAP))
+//	}
+//
+// Run them with go test -fuzz=FuzzPasswordPolicy; without -fuzz they run the
+// seed corpus as regular tests.
+package passvalfuzz
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	passval "github.com/fernandezvara/passvalidator"
+)
+
+// CheckResult reports the invariants that r, the result of validating
+// password, breaks: a score outside 0-100, a negative or NaN entropy, a
+// penalty factor outside 0-1 or a span outside the password, a missing
+// strength label, or a Pass that disagrees with the failed rules. It returns
+// nil if r is consistent.
+func CheckResult(password string, r *passval.Result) error {
+	if r == nil {
+		return errors.New("nil result")
+	}
+
+	var errs []error
+	if r.Score < 0 || r.Score > 100 {
+		errs = append(errs, fmt.Errorf("score %d is outside 0-100", r.Score))
+	}
+	if r.Entropy < 0 || math.IsNaN(r.Entropy) {
+		errs = append(errs, fmt.Errorf("entropy %v is negative or NaN", r.Entropy))
+	}
+	if r.Strength == "" {
+		errs = append(errs, errors.New("no strength label"))
+	}
+	if r.Pass && len(r.RuleFails) > 0 {
+		errs = append(errs, fmt.Errorf("passes with failed rules %v", r.RuleFails))
+	}
+	if !r.Pass && len(r.RuleFails) == 0 {
+		errs = append(errs, errors.New("fails without a failed rule"))
+	}
+	for _, f := range r.RuleFails {
+		if f.Code == "" || f.Message == "" {
+			errs = append(errs, fmt.Errorf("failed rule %+v has no code or message", f))
+		}
+	}
+	for _, p := range r.Penalties {
+		if !(p.Factor >= 0 && p.Factor <= 1) {
+			errs = append(errs, fmt.Errorf("penalty %s has factor %v outside 0-1", p.Rule, p.Factor))
+		}
+		if p.Start < 0 || p.Start > p.End || p.End > len(password) {
+			errs = append(errs, fmt.Errorf("penalty %s spans %d-%d of a %d-byte password", p.Rule, p.Start, p.End, len(password)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckValidate validates password with v and reports the invariants the
+// result breaks, as CheckResult does, and whether Validate disagrees with
+// ValidateResult. Validators with external checks, such as a breach checker,
+// must answer consistently for the comparison to hold.
+func CheckValidate(v passval.Validator, password string) error {
+	r := v.ValidateResult(password)
+	if err := CheckResult(password, r); err != nil {
+		return fmt.Errorf("%q: %w", password, err)
+	}
+	if pass, score := v.Validate(password); pass != r.Pass || score != r.Score {
+		return fmt.Errorf("%q: Validate = %v, %d but ValidateResult = %v, %d", password, pass, score, r.Pass, r.Score)
+	}
+	return nil
+}
+
+// CheckGenerate generates a password with v and reports whether it falls
+// outside the length limits or fails v's own policy. An error from Generate
+// is not a violation, since some policies cannot be satisfied; use
+// NewValidatorStrict to reject those.
+func CheckGenerate(v *passval.PasswordValidator) error {
+	pwd, err := v.Generate()
+	if err != nil {
+		return nil
+	}
+	n := len([]rune(pwd))
+	if n < v.MinLength || (v.MaxLength != passval.NoMax && n > v.MaxLength) {
+		return fmt.Errorf("generated %q has %d characters, outside %d-%d", pwd, n, v.MinLength, v.MaxLength)
+	}
+	r := v.ValidateResult(pwd)
+	if !r.Pass {
+		return fmt.Errorf("generated %q fails its own policy: %v", pwd, r.Err())
+	}
+	return CheckResult(pwd, r)
+}
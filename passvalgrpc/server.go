@@ -0,0 +1,121 @@
+// Package passvalgrpc serves a passval validator as a gRPC PasswordPolicy
+// service (see passval.proto), so polyglot services can share one central
+// policy.
+//
+// Server implements the service logic on plain Go messages that mirror the
+// proto definitions, keeping this module free of gRPC dependencies. To serve
+// it over gRPC, generate the stubs from passval.proto in the serving module
+// and forward each method to Server, mapping ErrInvalidArgument and context
+// errors to status codes:
+//
+//	func (s *policyServer) ValidatePassword(ctx context.Context, req *passvalpb.ValidatePasswordRequest) (*passvalpb.ValidatePasswordResponse, error) {
+//		resp, err := s.srv.ValidatePassword(ctx, &passvalgrpc.ValidatePasswordRequest{Password: req.GetPassword()})
+//		...
+//	}
+package passvalgrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	passval "github.com/fernandezvara/passvalidator"
+)
+
+// MaxGenerateCount caps the number of passwords returned by one GeneratePassword call.
+const MaxGenerateCount = 100
+
+// ErrInvalidArgument is wrapped by errors caused by invalid requests; gRPC
+// adapters map it to codes.InvalidArgument.
+var ErrInvalidArgument = errors.New("invalid argument")
+
+// ValidatePasswordRequest mirrors the proto message of the same name.
+type ValidatePasswordRequest struct {
+	Password string
+}
+
+// ValidatePasswordResponse mirrors the proto message of the same name.
+type ValidatePasswordResponse struct {
+	Pass        bool
+	Score       int32
+	Strength    string
+	ReasonCodes []string
+	RuleFails   []string
+	Warnings    []passval.Warning
+}
+
+// GeneratePasswordRequest mirrors the proto message of the same name.
+type GeneratePasswordRequest struct {
+	Count int32
+}
+
+// GeneratePasswordResponse mirrors the proto message of the same name.
+type GeneratePasswordResponse struct {
+	Passwords []string
+}
+
+// DescribePolicyResponse mirrors the proto message of the same name.
+type DescribePolicyResponse struct {
+	PolicyJSON string
+}
+
+// Server implements the PasswordPolicy service for a validator.
+type Server struct {
+	v *passval.PasswordValidator
+}
+
+// NewServer creates a server that serves v.
+func NewServer(v *passval.PasswordValidator) *Server {
+	return &Server{v: v}
+}
+
+// ValidatePassword checks a password against the policy. External checks
+// (breach lookups) run under ctx.
+func (s *Server) ValidatePassword(ctx context.Context, req *ValidatePasswordRequest) (*ValidatePasswordResponse, error) {
+	r, err := s.v.ValidateContext(ctx, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatePasswordResponse{
+		Pass:        r.Pass,
+		Score:       int32(r.Score),
+		Strength:    r.Strength,
+		ReasonCodes: r.Codes(),
+		RuleFails:   r.FailMessages(),
+		Warnings:    r.Warnings,
+	}, nil
+}
+
+// GeneratePassword creates req.Count passwords (default 1) that satisfy the policy.
+func (s *Server) GeneratePassword(ctx context.Context, req *GeneratePasswordRequest) (*GeneratePasswordResponse, error) {
+	count := int(req.Count)
+	if count == 0 {
+		count = 1
+	}
+	if count < 0 || count > MaxGenerateCount {
+		return nil, fmt.Errorf("%w: count must be between 1 and %d", ErrInvalidArgument, MaxGenerateCount)
+	}
+
+	resp := &GeneratePasswordResponse{Passwords: make([]string, 0, count)}
+	for i := 0; i < count; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		pwd, err := s.v.Generate()
+		if err != nil {
+			return nil, err
+		}
+		resp.Passwords = append(resp.Passwords, pwd)
+	}
+	return resp, nil
+}
+
+// DescribePolicy returns the configured policy as JSON.
+func (s *Server) DescribePolicy(ctx context.Context) (*DescribePolicyResponse, error) {
+	b, err := json.Marshal(s.v.Policy())
+	if err != nil {
+		return nil, err
+	}
+	return &DescribePolicyResponse{PolicyJSON: string(b)}, nil
+}
@@ -0,0 +1,52 @@
+package passvalgrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	passval "github.com/fernandezvara/passvalidator"
+)
+
+func TestServer(t *testing.T) {
+	v := passval.NewPasswordValidator(12, 20, true, true, true, true, 50)
+	s := NewServer(v)
+	ctx := context.Background()
+
+	resp, err := s.ValidatePassword(ctx, &ValidatePasswordRequest{Password: "password"})
+	if err != nil {
+		t.Fatalf("ValidatePassword() error: %v", err)
+	}
+	if resp.Pass || len(resp.ReasonCodes) == 0 {
+		t.Errorf("'password' should fail with reason codes, got %+v", resp)
+	}
+
+	gen, err := s.GeneratePassword(ctx, &GeneratePasswordRequest{Count: 3})
+	if err != nil {
+		t.Fatalf("GeneratePassword() error: %v", err)
+	}
+	if len(gen.Passwords) != 3 {
+		t.Errorf("expected 3 passwords, got %d", len(gen.Passwords))
+	}
+	for _, p := range gen.Passwords {
+		if pass, _ := v.Validate(p); !pass {
+			t.Errorf("generated password %q does not pass the policy", p)
+		}
+	}
+	if _, err := s.GeneratePassword(ctx, &GeneratePasswordRequest{Count: MaxGenerateCount + 1}); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("expected ErrInvalidArgument, got %v", err)
+	}
+
+	desc, err := s.DescribePolicy(ctx)
+	if err != nil {
+		t.Fatalf("DescribePolicy() error: %v", err)
+	}
+	var p passval.Policy
+	if err := json.Unmarshal([]byte(desc.PolicyJSON), &p); err != nil {
+		t.Fatalf("invalid policy JSON: %v", err)
+	}
+	if p.MinLength != 12 || !p.RequireSymbols {
+		t.Errorf("unexpected policy: %+v", p)
+	}
+}
@@ -0,0 +1,114 @@
+// Package passvalhttp exposes a passval validator over HTTP: a JSON validation
+// handler and a middleware that guards signup or password-change endpoints.
+package passvalhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	passval "github.com/fernandezvara/passvalidator"
+)
+
+// maxBodyBytes bounds the size of request bodies read by the handler and middleware.
+const maxBodyBytes = 64 << 10
+
+// Request is the JSON payload accepted by the handler.
+type Request struct {
+	Password string `json:"password"`
+	// UserInputs are user-specific terms (username, email, name) the password must not contain.
+	UserInputs []string `json:"user_inputs,omitempty"`
+}
+
+// Response is the JSON payload returned by the handler and by the middleware on rejection.
+type Response struct {
+	Pass        bool               `json:"pass"`
+	Score       int                `json:"score"`
+	Strength    string             `json:"strength"`
+	ReasonCodes []string           `json:"reason_codes"`
+	RuleFails   []passval.RuleFail `json:"rule_fails,omitempty"`
+	Warnings    []passval.Warning  `json:"warnings,omitempty"`
+	Suggestions []string           `json:"suggestions,omitempty"`
+}
+
+// RuleUserInput is the reason code for passwords containing one of the request's user inputs.
+const RuleUserInput = "user_input"
+
+// minUserInputLen is the shortest user input checked, to avoid matching initials.
+const minUserInputLen = 3
+
+type handler struct {
+	v passval.Validator
+}
+
+// NewHandler returns an http.Handler that accepts a POSTed Request and responds
+// with the validation Response. Validation failures are reported with status 200;
+// malformed requests get 400.
+func NewHandler(v passval.Validator) http.Handler {
+	return &handler{v: v}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req Request
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, Evaluate(h.v, req))
+}
+
+// Evaluate validates a request and builds the response payload.
+func Evaluate(v passval.Validator, req Request) Response {
+	result := v.ValidateResult(req.Password)
+
+	resp := Response{
+		Pass:        result.Pass,
+		Score:       result.Score,
+		Strength:    result.Strength,
+		ReasonCodes: result.Codes(),
+		RuleFails:   result.RuleFails,
+		Warnings:    result.Warnings,
+	}
+
+	if input := matchUserInput(req.Password, req.UserInputs); input != "" {
+		resp.Pass = false
+		resp.ReasonCodes = append([]string{RuleUserInput}, resp.ReasonCodes...)
+		resp.RuleFails = append(resp.RuleFails, passval.RuleFail{Code: RuleUserInput, Message: "contains personal information"})
+	}
+
+	if resp.Strength == "" {
+		// Results built outside passval, e.g. by a mock Validator.
+		resp.Strength = passval.StrengthLabel(result.Score)
+	}
+	resp.Suggestions = suggestionsFor(resp.ReasonCodes)
+	return resp
+}
+
+// matchUserInput returns the first user input contained in password, case-insensitively.
+func matchUserInput(password string, inputs []string) string {
+	lower := strings.ToLower(password)
+	for _, in := range inputs {
+		in = strings.ToLower(strings.TrimSpace(in))
+		if len(in) >= minUserInputLen && strings.Contains(lower, in) {
+			return in
+		}
+	}
+	return ""
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
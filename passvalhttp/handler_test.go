@@ -0,0 +1,236 @@
+package passvalhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	passval "github.com/fernandezvara/passvalidator"
+)
+
+func newValidator() *passval.PasswordValidator {
+	return passval.NewPasswordValidator(8, 64, true, true, true, true, 50)
+}
+
+func postJSON(t *testing.T, h http.Handler, body string) (*httptest.ResponseRecorder, Response) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var resp Response
+	if rec.Code == http.StatusOK || rec.Code == http.StatusUnprocessableEntity {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid JSON response: %v", err)
+		}
+	}
+	return rec, resp
+}
+
+func TestHandler(t *testing.T) {
+	h := NewHandler(newValidator())
+
+	rec, resp := postJSON(t, h, `{"password": "password"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if resp.Pass {
+		t.Error("'password' should not pass")
+	}
+	if len(resp.ReasonCodes) == 0 || len(resp.Suggestions) == 0 {
+		t.Errorf("expected reason codes and suggestions, got %+v", resp)
+	}
+
+	_, resp = postJSON(t, h, `{"password": "Xk9$mP2!vLq#7"}`)
+	if !resp.Pass || resp.Strength == "" {
+		t.Errorf("strong password should pass with a strength label, got %+v", resp)
+	}
+
+	_, resp = postJSON(t, h, `{"password": "Jdoe#Xk9$mP2!vLq", "user_inputs": ["jdoe", "jdoe@example.com"]}`)
+	if resp.Pass || resp.ReasonCodes[0] != RuleUserInput {
+		t.Errorf("password containing username should fail with %s, got %+v", RuleUserInput, resp)
+	}
+	if n := len(resp.RuleFails); n == 0 || resp.RuleFails[n-1].Code != RuleUserInput {
+		t.Errorf("RuleFails = %+v, want a %s fail last", resp.RuleFails, RuleUserInput)
+	}
+
+	if rec, _ := postJSON(t, h, `{not json`); rec.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for bad JSON, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/validate", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405 for GET, got %d", rec.Code)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var gotBody string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		if r.Form != nil {
+			gotBody = r.FormValue("username")
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	h := Middleware(newValidator(), "password")(next)
+
+	rec, resp := postJSON(t, h, `{"username": "jdoe", "password": "qwerty"}`)
+	if rec.Code != http.StatusUnprocessableEntity || resp.Pass {
+		t.Errorf("weak password should be rejected with 422, got %d", rec.Code)
+	}
+
+	body := `{"username": "jdoe", "password": "Xk9$mP2!vLq#7"}`
+	if rec, _ := postJSON(t, h, body); rec.Code != http.StatusCreated {
+		t.Errorf("strong password should reach the handler, got %d", rec.Code)
+	}
+	if gotBody != body {
+		t.Errorf("body not restored for next handler: %q", gotBody)
+	}
+
+	form := url.Values{"username": {"jdoe"}, "password": {"letmein"}}
+	req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("weak form password should be rejected with 422, got %d", rec.Code)
+	}
+
+	form.Set("password", "Xk9$mP2!vLq#7")
+	req = httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated || gotBody != "jdoe" {
+		t.Errorf("strong form password should reach the handler with form intact, got %d %q", rec.Code, gotBody)
+	}
+}
+
+func TestMiddlewareBodySources(t *testing.T) {
+	var got string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.FormValue("password")
+		w.WriteHeader(http.StatusCreated)
+	})
+	h := Middleware(newValidator(), "password")(next)
+
+	multipartBody := func(password string) (string, string) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		mw.WriteField("username", "jdoe")
+		mw.WriteField("password", password)
+		mw.Close()
+		return buf.String(), mw.FormDataContentType()
+	}
+	weak, weakType := multipartBody("123")
+	strong, strongType := multipartBody("Xk9$mP2!vLq#7")
+
+	for _, tc := range []struct {
+		name, target, contentType, body string
+		want                            int
+	}{
+		{"multipart weak", "/signup", weakType, weak, http.StatusUnprocessableEntity},
+		{"multipart strong", "/signup", strongType, strong, http.StatusCreated},
+		{"multipart weak with query", "/signup?password=Xk9$mP2!vLq%237", weakType, weak, http.StatusBadRequest},
+		{"form strong with weak query", "/signup?password=123", "application/x-www-form-urlencoded", "password=Xk9%24mP2%21vLq%237", http.StatusBadRequest},
+		{"JSON without content type", "/signup", "", `{"password": "qwerty"}`, http.StatusUnprocessableEntity},
+		{"JSON as text/plain", "/signup", "text/plain", `{"password": "qwerty"}`, http.StatusUnprocessableEntity},
+		{"missing field", "/signup", "application/json", `{"username": "jdoe"}`, http.StatusBadRequest},
+		{"missing form field", "/signup", "application/x-www-form-urlencoded", "username=jdoe", http.StatusBadRequest},
+		{"unreadable body", "/signup", "text/plain", "password=123", http.StatusBadRequest},
+	} {
+		got = ""
+		req := httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader(tc.body))
+		if tc.contentType != "" {
+			req.Header.Set("Content-Type", tc.contentType)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s: got %d, want %d", tc.name, rec.Code, tc.want)
+		}
+		if tc.want == http.StatusCreated && got != "Xk9$mP2!vLq#7" {
+			t.Errorf("%s: handler saw password %q", tc.name, got)
+		}
+	}
+}
+
+// stubValidator is a passval.Validator returning a fixed result.
+type stubValidator struct {
+	result passval.Result
+}
+
+func (s stubValidator) Validate(string) (bool, int) { return s.result.Pass, s.result.Score }
+
+func (s stubValidator) ValidateVerbose(p string) (bool, int, error) {
+	r := s.ValidateResult(p)
+	return r.Pass, r.Score, r.Err()
+}
+
+func (s stubValidator) ValidateResult(string) *passval.Result {
+	r := s.result
+	return &r
+}
+
+func TestOpenAPISchemas(t *testing.T) {
+	data, err := json.Marshal(map[string]any{"components": map[string]any{"schemas": OpenAPISchemas()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Components struct {
+			Schemas map[string]struct {
+				Enum []string `json:"enum"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	enum := doc.Components.Schemas["PasswordReasonCode"].Enum
+
+	// every code the handler reports is documented and has advice
+	for _, pwd := range []string{"password", "p@ssw0rd", "abc", "Summer2024$", "qwertydragon", "6915553412aB!", "abababab"} {
+		resp := Evaluate(newValidator(), Request{Password: pwd, UserInputs: []string{"summer"}})
+		for _, code := range resp.ReasonCodes {
+			if !slices.Contains(enum, code) {
+				t.Errorf("%q: reason code %s missing from the schema enum", pwd, code)
+			}
+		}
+	}
+	for _, code := range enum {
+		if _, ok := suggestions[code]; !ok {
+			t.Errorf("reason code %s has no suggestion", code)
+		}
+	}
+}
+
+func TestHandlerWithStubValidator(t *testing.T) {
+	stub := stubValidator{result: passval.Result{
+		Score:     10,
+		RuleFails: []passval.RuleFail{{Message: "rejected by stub"}},
+		Penalties: []passval.PenaltyDetail{{Rule: "common_password", Factor: 0.1}},
+	}}
+
+	_, resp := postJSON(t, NewHandler(stub), `{"password": "anything"}`)
+	if resp.Pass || resp.Score != 10 {
+		t.Errorf("expected the stub's failing result, got %+v", resp)
+	}
+	if len(resp.ReasonCodes) != 1 || resp.ReasonCodes[0] != "common_password" {
+		t.Errorf("ReasonCodes = %v, want [common_password]", resp.ReasonCodes)
+	}
+	if _, _, err := stub.ValidateVerbose("anything"); err == nil {
+		t.Error("Err() should describe a failing result built outside passval")
+	}
+}
@@ -0,0 +1,95 @@
+package passvalhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+
+	passval "github.com/fernandezvara/passvalidator"
+)
+
+// Middleware returns middleware that validates the password submitted in field
+// before calling the wrapped handler. The password is read from the request
+// body only, never from the query string: from form values for URL-encoded and
+// multipart forms, and from a JSON body (top-level string field) for any other
+// content type, including none. Requests without the field, with the field
+// in the query string (which handlers reading r.FormValue could pick instead)
+// or whose body cannot be read are rejected with 400, and requests whose password fails the
+// policy with 422 and a JSON Response; the request body is left intact for the
+// next handler.
+func Middleware(v passval.Validator, field string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Has(field) {
+				writeError(w, http.StatusBadRequest, "field "+field+" not allowed in the query string")
+				return
+			}
+			password, ok, err := extractPassword(w, r, field)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid request body")
+				return
+			}
+			if !ok {
+				writeError(w, http.StatusBadRequest, "missing field "+field)
+				return
+			}
+
+			if resp := Evaluate(v, Request{Password: password}); !resp.Pass {
+				writeJSON(w, http.StatusUnprocessableEntity, resp)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// extractPassword reads field from the request body, restoring the body for
+// JSON requests. ok is false if the body does not carry the field.
+func extractPassword(w http.ResponseWriter, r *http.Request, field string) (password string, ok bool, err error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	var values []string
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return "", false, err
+		}
+		values = r.PostForm[field]
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(maxBodyBytes); err != nil {
+			return "", false, err
+		}
+		values = r.MultipartForm.Value[field]
+	default:
+		return jsonPassword(r, field)
+	}
+	if len(values) == 0 {
+		return "", false, nil
+	}
+	return values[0], true, nil
+}
+
+// jsonPassword reads the top-level string field of a JSON body and restores
+// the body.
+func jsonPassword(r *http.Request, field string) (password string, ok bool, err error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", false, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return "", false, err
+	}
+	raw, present := fields[field]
+	if !present {
+		return "", false, nil
+	}
+	if err := json.Unmarshal(raw, &password); err != nil {
+		return "", false, err
+	}
+	return password, true, nil
+}
@@ -0,0 +1,94 @@
+package passvalhttp
+
+import (
+	"slices"
+
+	passval "github.com/fernandezvara/passvalidator"
+)
+
+// OpenAPISchemas returns OpenAPI 3 schemas for the handler's payloads, to be
+// merged into the components/schemas section of an API description:
+// PasswordValidationRequest, PasswordValidationResponse, PasswordRuleFail, PasswordWarning,
+// PasswordReasonCode (an enum of every reason code, including RuleUserInput)
+// and PasswordError. Generating them from the library keeps API documentation
+// in lockstep with Request, Response and the reason codes.
+func OpenAPISchemas() map[string]any {
+	ref := func(name string) map[string]any {
+		return map[string]any{"$ref": "#/components/schemas/" + name}
+	}
+	array := func(items map[string]any, desc string) map[string]any {
+		return map[string]any{"type": "array", "items": items, "description": desc}
+	}
+
+	return map[string]any{
+		"PasswordValidationRequest": map[string]any{
+			"type":     "object",
+			"required": []string{"password"},
+			"properties": map[string]any{
+				"password": map[string]any{"type": "string", "format": "password"},
+				"user_inputs": array(map[string]any{"type": "string"},
+					"User-specific terms (username, email, name) the password must not contain."),
+			},
+		},
+		"PasswordValidationResponse": map[string]any{
+			"type":     "object",
+			"required": []string{"pass", "score", "strength", "reason_codes"},
+			"properties": map[string]any{
+				"pass":  map[string]any{"type": "boolean"},
+				"score": map[string]any{"type": "integer", "minimum": 0, "maximum": 100},
+				"strength": map[string]any{
+					"type": "string",
+					"enum": []string{
+						passval.StrengthVeryWeak, passval.StrengthWeak, passval.StrengthFair,
+						passval.StrengthStrong, passval.StrengthVeryStrong,
+					},
+				},
+				"reason_codes": array(ref("PasswordReasonCode"),
+					"Codes of failed rules followed by the identifiers of applied penalties."),
+				"rule_fails":  array(ref("PasswordRuleFail"), "Failed rules with the values their messages were built from."),
+				"warnings":    array(ref("PasswordWarning"), "Findings that do not fail the policy."),
+				"suggestions": array(map[string]any{"type": "string"}, "User-facing advice for the reason codes."),
+			},
+		},
+		"PasswordRuleFail": map[string]any{
+			"type":     "object",
+			"required": []string{"code", "message"},
+			"properties": map[string]any{
+				"code": ref("PasswordReasonCode"),
+				"params": map[string]any{
+					"type":                 "object",
+					"additionalProperties": true,
+					"description":          `Values the message was built from, e.g. {"min": 8, "actual": 5} for too_short.`,
+				},
+				"message": map[string]any{"type": "string", "description": "Human-readable description in English."},
+			},
+		},
+		"PasswordWarning": map[string]any{
+			"type":     "object",
+			"required": []string{"code", "message"},
+			"properties": map[string]any{
+				"code": map[string]any{
+					"type":        "string",
+					"description": "A warning code, or the reason code of a rule demoted to a warning.",
+				},
+				"message": map[string]any{"type": "string"},
+			},
+		},
+		"PasswordReasonCode": map[string]any{
+			"type": "string",
+			"enum": reasonCodes(),
+		},
+		"PasswordError": map[string]any{
+			"type":     "object",
+			"required": []string{"error"},
+			"properties": map[string]any{
+				"error": map[string]any{"type": "string"},
+			},
+		},
+	}
+}
+
+// reasonCodes returns every reason code a Response can carry.
+func reasonCodes() []string {
+	return slices.Concat([]string{RuleUserInput}, passval.ReasonCodes())
+}
@@ -0,0 +1,66 @@
+package passvalhttp
+
+import passval "github.com/fernandezvara/passvalidator"
+
+// suggestions maps reason codes to user-facing advice.
+var suggestions = map[string]string{
+	passval.RuleTooShort:         "Use a longer password.",
+	passval.RuleTooLong:          "Use a shorter password.",
+	passval.RuleTooManyBytes:     "Use a shorter password or fewer special characters.",
+	passval.RuleMissingLower:     "Add a lowercase letter.",
+	passval.RuleMissingUpper:     "Add an uppercase letter.",
+	passval.RuleMissingNumber:    "Add a number.",
+	passval.RuleMissingSymbol:    "Add a symbol.",
+	passval.RuleMinLower:         "Add more lowercase letters.",
+	passval.RuleMinUpper:         "Add more uppercase letters.",
+	passval.RuleMinDigits:        "Add more numbers.",
+	passval.RuleMinSymbols:       "Add more symbols.",
+	passval.RuleMinCharClasses:   "Mix lowercase, uppercase, numbers and symbols.",
+	passval.RuleMinUniqueChars:   "Use more different characters.",
+	passval.RuleStructure:        "Follow the required layout, e.g. where letters and numbers go.",
+	passval.RuleNonASCII:         "Use only letters, numbers and symbols found on a US keyboard.",
+	passval.RuleNonPrintable:     "Remove tabs, line breaks and other invisible characters.",
+	passval.RuleDisallowedSymbol: "Remove symbols that are not allowed.",
+	passval.RuleDisallowedChar:   "Remove quotes, backslashes, spaces or other characters that are not allowed.",
+	passval.RuleBannedSubstring:  "Avoid the name of this site or company.",
+	passval.RuleSiteTerm:         "Avoid the name of this site or product, even with numbers added.",
+	passval.RuleBannedPattern:    "Avoid identifiers such as employee or ticket numbers.",
+	passval.RuleBreached:         "This password appeared in a data breach; choose another.",
+	passval.RuleBreachUnchecked:  "We could not check this password right now; try again shortly.",
+	passval.RuleDenylisted:       "Do not reuse a previous password or personal details.",
+	passval.RuleTooSimilar:       "Choose a new password rather than a variation of your old one.",
+	passval.RuleIncremented:      "Do not just count up the number in your old password.",
+	passval.RuleTooEasyToGuess:   "Make the password longer or less predictable.",
+	passval.RuleComplexity:       "Make the password less predictable.",
+	RuleUserInput:                "Avoid your name, username or email address.",
+	"common_password":            "Avoid common passwords.",
+	"common_password_leet":       "Replacing letters with look-alike symbols does not help much.",
+	"repeated_chars":             "Avoid repeated characters.",
+	"sequential_chars":           "Avoid sequences like abc or 123.",
+	"keyboard_pattern":           "Avoid keyboard patterns like qwerty.",
+	"dictionary_substring":       "Avoid common words; combine several unrelated words instead.",
+	"dictionary_concatenation":   "Joining common passwords is easy to guess; use unrelated, uncommon words.",
+	"repeated_pattern":           "Avoid repeating the same few characters.",
+	"topical_word":               "Avoid names of teams, cities, bands or heroes.",
+	"natural_word":               "Add more unrelated words or random characters; everyday words alone are guessed early.",
+	"season_year":                "Avoid months or seasons combined with a year.",
+	"date":                       "Avoid dates such as birthdays or anniversaries.",
+	"digit_run":                  "Avoid long runs of digits such as ID numbers.",
+	"phone_number":               "Avoid phone numbers.",
+	"email_address":              "Do not use an email address as your password.",
+	"url":                        "Do not use a website address as your password.",
+	"single_class":               "Mix letters with numbers or symbols.",
+}
+
+// suggestionsFor returns de-duplicated advice for the given reason codes.
+func suggestionsFor(codes []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, c := range codes {
+		if s, ok := suggestions[c]; ok && !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
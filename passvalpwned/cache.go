@@ -0,0 +1,93 @@
+package passvalpwned
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache stores range API responses keyed by the 5-character hash prefix, so
+// no plaintext or full hash is ever held. Implementations must be safe for
+// concurrent use; a shared cache (e.g. Redis) can implement it to serve
+// several instances.
+type Cache interface {
+	Get(prefix string) (body []byte, ok bool)
+	Set(prefix string, body []byte)
+}
+
+// LRUCache is an in-memory Cache holding a bounded number of responses, each
+// for a limited time.
+type LRUCache struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time // replaced in tests
+
+	mu      sync.Mutex
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	prefix  string
+	body    []byte
+	expires time.Time
+}
+
+// NewLRUCache returns a cache of up to size responses, each kept for ttl.
+// The range API's data changes rarely, so a ttl of hours is reasonable; a
+// ttl of 0 keeps entries until they are evicted.
+func NewLRUCache(size int, ttl time.Duration) *LRUCache {
+	return &LRUCache{
+		size:    max(size, 1),
+		ttl:     ttl,
+		now:     time.Now,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get returns the unexpired response stored for prefix.
+func (c *LRUCache) Get(prefix string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[prefix]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*lruEntry)
+	if c.ttl > 0 && !c.now().Before(e.expires) {
+		c.order.Remove(el)
+		delete(c.entries, prefix)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return e.body, true
+}
+
+// Set stores the response for prefix, evicting the least recently used entry
+// when the cache is full.
+func (c *LRUCache) Set(prefix string, body []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	expires := c.now().Add(c.ttl)
+	if el, ok := c.entries[prefix]; ok {
+		e := el.Value.(*lruEntry)
+		e.body, e.expires = body, expires
+		c.order.MoveToFront(el)
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).prefix)
+	}
+	c.entries[prefix] = c.order.PushFront(&lruEntry{prefix: prefix, body: body, expires: expires})
+}
+
+// Len returns the number of entries in the cache, including expired ones not
+// yet removed.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
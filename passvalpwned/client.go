@@ -0,0 +1,127 @@
+package passvalpwned
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultBaseURL is the Pwned Passwords range API.
+const DefaultBaseURL = "https://api.pwnedpasswords.com/range/"
+
+// prefixLen is the number of hex characters of the SHA-1 hash sent to the
+// range API (k-anonymity): the password and its full hash never leave the
+// process.
+const prefixLen = 5
+
+// maxRangeBytes bounds the size of a range response; padded responses are
+// around 30 KB.
+const maxRangeBytes = 1 << 20
+
+// Client checks passwords against the Pwned Passwords range API. The zero
+// value is ready to use; set Cache to absorb repeated lookups during signup
+// storms. A Client is safe for concurrent use if its Cache is.
+type Client struct {
+	// HTTPClient sends the requests; nil means http.DefaultClient. Set a
+	// Timeout on it, or pass a context with a deadline, to bound latency.
+	HTTPClient *http.Client
+	// BaseURL is the range endpoint the hash prefix is appended to; empty
+	// means DefaultBaseURL.
+	BaseURL string
+	// Cache stores range responses by hash prefix; nil disables caching.
+	Cache Cache
+	// MinCount is the number of breaches from which IsBreached reports a
+	// password; 0 or 1 report any listed password.
+	MinCount int
+}
+
+// Count returns the number of breaches password appears in, or 0 if it is
+// not listed.
+func (c *Client) Count(ctx context.Context, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLen], hash[prefixLen:]
+
+	body, ok := c.cached(prefix)
+	if !ok {
+		var err error
+		if body, err = c.fetchRange(ctx, prefix); err != nil {
+			return 0, err
+		}
+		if c.Cache != nil {
+			c.Cache.Set(prefix, body)
+		}
+	}
+	return rangeCount(body, suffix)
+}
+
+// IsBreached reports whether password appears in at least MinCount breaches.
+// It implements passval.BreachChecker.
+func (c *Client) IsBreached(ctx context.Context, password string) (bool, error) {
+	n, err := c.Count(ctx, password)
+	return n > 0 && n >= c.MinCount, err
+}
+
+func (c *Client) cached(prefix string) ([]byte, bool) {
+	if c.Cache == nil {
+		return nil, false
+	}
+	return c.Cache.Get(prefix)
+}
+
+// fetchRange returns the body of the range response for prefix. Padding is
+// requested so the response size does not reveal the prefix's bucket.
+func (c *Client) fetchRange(ctx context.Context, prefix string) ([]byte, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, base+prefix, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Add-Padding", "true")
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("range API: %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxRangeBytes))
+}
+
+// rangeCount finds suffix in a range response of "SUFFIX:COUNT" lines.
+// Padding entries have a count of 0 and are therefore never reported.
+func rangeCount(body []byte, suffix string) (int, error) {
+	for len(body) > 0 {
+		line := body
+		if i := bytes.IndexByte(body, '\n'); i >= 0 {
+			line, body = body[:i], body[i+1:]
+		} else {
+			body = nil
+		}
+		s, count, ok := bytes.Cut(bytes.TrimSpace(line), []byte{':'})
+		if !ok || !strings.EqualFold(string(s), suffix) {
+			continue
+		}
+		n, err := strconv.Atoi(string(count))
+		if err != nil {
+			return 0, fmt.Errorf("%w: count in range line %q", ErrMalformed, line)
+		}
+		return n, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,104 @@
+package passvalpwned
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// rangeServer serves range responses for the breached passwords, with a
+// padding entry, and counts the requests it receives.
+func rangeServer(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Add-Padding") != "true" {
+			t.Error("request without Add-Padding header")
+		}
+		prefix := strings.TrimPrefix(r.URL.Path, "/range/")
+		if len(prefix) != prefixLen {
+			http.Error(w, "bad prefix", http.StatusBadRequest)
+			return
+		}
+		for p, count := range breached {
+			sum := sha1.Sum([]byte(p))
+			hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+			if hash[:prefixLen] == prefix {
+				fmt.Fprintf(w, "%s:%d\r\n", hash[prefixLen:], count)
+			}
+		}
+		fmt.Fprint(w, "0000000000000000000000000000000000A:0\r\n")
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClient(t *testing.T) {
+	var requests atomic.Int32
+	srv := rangeServer(t, &requests)
+	c := &Client{BaseURL: srv.URL + "/range/", Cache: NewLRUCache(10, time.Hour)}
+	ctx := context.Background()
+
+	for p, want := range breached {
+		if got, err := c.Count(ctx, p); err != nil || got != want {
+			t.Errorf("Count(%q) = %d, %v, want %d", p, got, err, want)
+		}
+	}
+	if got, err := c.Count(ctx, "not-breached"); err != nil || got != 0 {
+		t.Errorf("Count(not-breached) = %d, %v, want 0", got, err)
+	}
+
+	before := requests.Load()
+	for range 5 {
+		if breached, err := c.IsBreached(ctx, "password"); err != nil || !breached {
+			t.Errorf("IsBreached(password) = %v, %v", breached, err)
+		}
+	}
+	if n := requests.Load() - before; n != 0 {
+		t.Errorf("cached lookups made %d requests, want 0", n)
+	}
+
+	c.MinCount = 100
+	if breached, _ := c.IsBreached(ctx, "Tr0ub4dor&3"); breached {
+		t.Error("a password below MinCount should not be reported")
+	}
+
+	failing := &Client{BaseURL: srv.URL + "/other/"}
+	if _, err := failing.Count(ctx, "password"); err == nil {
+		t.Error("expected an error for a non-200 response")
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := NewLRUCache(2, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Set("AAAAA", []byte("a"))
+	c.Set("BBBBB", []byte("b"))
+	if _, ok := c.Get("AAAAA"); !ok {
+		t.Fatal("AAAAA should be cached")
+	}
+	c.Set("CCCCC", []byte("c")) // evicts BBBBB, the least recently used
+	if _, ok := c.Get("BBBBB"); ok {
+		t.Error("BBBBB should have been evicted")
+	}
+	if body, ok := c.Get("CCCCC"); !ok || string(body) != "c" {
+		t.Errorf("Get(CCCCC) = %q, %v", body, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := c.Get("AAAAA"); ok {
+		t.Error("AAAAA should have expired")
+	}
+	if n := c.Len(); n != 1 {
+		t.Errorf("Len() = %d, want 1", n)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build unix
+
+package passvalpwned
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"syscall"
+)
+
+// mapFile memory-maps f read-only and closes it; the returned function
+// releases the mapping. Pages are loaded by the kernel as lookups touch them.
+func mapFile(f *os.File, size int64) (io.ReaderAt, func() error, error) {
+	defer f.Close()
+	if size == 0 {
+		return bytes.NewReader(nil), func() error { return nil }, nil
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bytes.NewReader(data), func() error { return syscall.Munmap(data) }, nil
+}
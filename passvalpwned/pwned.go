@@ -0,0 +1,288 @@
+// Package passvalpwned checks passwords against the Pwned Passwords dataset,
+// either through the range API (Client) or, for air-gapped deployments, an
+// offline copy (File). File lookups binary-search a memory-mapped file, so
+// the 30+ GB dataset is never loaded into RAM.
+//
+// Both implement passval.BreachChecker:
+//
+//	f, err := passvalpwned.Open("pwned-passwords-sha1-ordered-by-hash.txt", passvalpwned.FormatText)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	v := passval.NewPasswordValidator(12, 64, false, false, false, false, 50, passval.WithBreachChecker(f))
+package passvalpwned
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// Format is the layout of a Pwned Passwords file.
+type Format int
+
+const (
+	// FormatText is the official SHA-1 "ordered by hash" dump, or the single
+	// file written by the haveibeenpwned-downloader: one "HASH:COUNT" line per
+	// password, sorted by hash, with upper- or lowercase hex and LF or CRLF
+	// line endings.
+	FormatText Format = iota
+	// FormatBinary is a sorted sequence of raw 20-byte SHA-1 digests with no
+	// separators or counts, about half the size of the text dump.
+	FormatBinary
+)
+
+const (
+	hashLen    = sha1.Size
+	hexHashLen = 2 * hashLen
+	// maxLine bounds the length of a text line: the hash, a colon, a count
+	// and a line ending.
+	maxLine = 64
+	// scanWindow is the range size below which the text search reads the
+	// remaining lines at once instead of bisecting further.
+	scanWindow = 4096
+)
+
+// ErrMalformed is wrapped by errors for files that do not match their Format.
+var ErrMalformed = errors.New("malformed pwned passwords file")
+
+// File is an open Pwned Passwords file. It is safe for concurrent use.
+type File struct {
+	// MinCount is the number of breaches from which IsBreached reports a
+	// password; 0 or 1 report any listed password. Binary files carry no
+	// counts, so every listed password counts once. Set it before use.
+	MinCount int
+
+	r      io.ReaderAt
+	size   int64
+	format Format
+	close  func() error
+}
+
+// Open opens the file at path for lookups. The file must be sorted by hash,
+// as published; Open does not verify the order.
+func Open(path string, format Format) (*File, error) {
+	if format != FormatText && format != FormatBinary {
+		return nil, fmt.Errorf("unknown format %d", format)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	size := info.Size()
+	if format == FormatBinary && size%hashLen != 0 {
+		f.Close()
+		return nil, fmt.Errorf("%w: size %d is not a multiple of %d", ErrMalformed, size, hashLen)
+	}
+	r, closeFn, err := mapFile(f, size)
+	if err != nil {
+		return nil, fmt.Errorf("mapping %s: %w", path, err)
+	}
+	return &File{r: r, size: size, format: format, close: closeFn}, nil
+}
+
+// Close releases the file. It must not be called while lookups are running.
+func (f *File) Close() error {
+	return f.close()
+}
+
+// Count returns the number of breaches password appears in, or 0 if it is
+// not listed. For FormatBinary files it returns 1 for a listed password.
+func (f *File) Count(password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	if f.format == FormatBinary {
+		return f.countBinary(sum[:])
+	}
+	target := make([]byte, hexHashLen)
+	hex.Encode(target, sum[:])
+	return f.countText(bytes.ToUpper(target))
+}
+
+// IsBreached reports whether password appears in at least MinCount breaches.
+// It implements passval.BreachChecker; ctx is not used, as lookups only read
+// a few pages of the file.
+func (f *File) IsBreached(_ context.Context, password string) (bool, error) {
+	n, err := f.Count(password)
+	return n > 0 && n >= f.MinCount, err
+}
+
+// countBinary bisects the fixed-size records of a FormatBinary file.
+func (f *File) countBinary(target []byte) (int, error) {
+	var readErr error
+	rec := make([]byte, hashLen)
+	n := int(f.size / hashLen)
+	i := sort.Search(n, func(i int) bool {
+		if readErr != nil {
+			return true
+		}
+		if _, err := f.r.ReadAt(rec, int64(i)*hashLen); err != nil {
+			readErr = err
+			return true
+		}
+		return bytes.Compare(rec, target) >= 0
+	})
+	if readErr != nil {
+		return 0, readErr
+	}
+	if i == n {
+		return 0, nil
+	}
+	if _, err := f.r.ReadAt(rec, int64(i)*hashLen); err != nil {
+		return 0, err
+	}
+	if bytes.Equal(rec, target) {
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// countText bisects the variable-length lines of a FormatText file, keeping
+// lo at a line start and the target line, if listed, starting in [lo, hi).
+func (f *File) countText(target []byte) (int, error) {
+	lo, hi := int64(0), f.size
+	for hi-lo > scanWindow {
+		mid := lo + (hi-lo)/2
+		start, err := f.nextLineStart(mid)
+		if err != nil {
+			return 0, err
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+		line, err := f.readLine(start)
+		if err != nil {
+			return 0, err
+		}
+		hash, count, err := parseLine(line)
+		if err != nil {
+			return 0, err
+		}
+		switch c := compareHex(hash, target); {
+		case c == 0:
+			return count, nil
+		case c < 0:
+			lo = start + int64(len(line))
+		default:
+			hi = start
+		}
+	}
+	return f.scanText(lo, hi, target)
+}
+
+// scanText reads the lines starting in [lo, hi) and looks for target.
+func (f *File) scanText(lo, hi int64, target []byte) (int, error) {
+	end := min(hi+maxLine, f.size)
+	buf := make([]byte, end-lo)
+	if _, err := f.r.ReadAt(buf, lo); err != nil && err != io.EOF {
+		return 0, err
+	}
+	for off := 0; off < len(buf) && lo+int64(off) < hi; {
+		line := buf[off:]
+		if i := bytes.IndexByte(line, '\n'); i >= 0 {
+			line = line[:i+1]
+		}
+		off += len(line)
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		hash, count, err := parseLine(line)
+		if err != nil {
+			return 0, err
+		}
+		switch c := compareHex(hash, target); {
+		case c == 0:
+			return count, nil
+		case c > 0:
+			return 0, nil
+		}
+	}
+	return 0, nil
+}
+
+// nextLineStart returns the offset of the first line starting at or after
+// off, or the file size if there is none.
+func (f *File) nextLineStart(off int64) (int64, error) {
+	if off == 0 {
+		return 0, nil
+	}
+	buf := make([]byte, maxLine)
+	for pos := off - 1; pos < f.size; pos += maxLine {
+		n, err := f.r.ReadAt(buf, pos)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return pos + int64(i) + 1, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+	}
+	return f.size, nil
+}
+
+// readLine returns the line starting at off, including its line ending.
+func (f *File) readLine(off int64) ([]byte, error) {
+	buf := make([]byte, maxLine)
+	n, err := f.r.ReadAt(buf, off)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	line := buf[:n]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		return line[:i+1], nil
+	}
+	if off+int64(n) < f.size {
+		return nil, fmt.Errorf("%w: line at offset %d is longer than %d bytes", ErrMalformed, off, maxLine)
+	}
+	return line, nil
+}
+
+// parseLine splits a "HASH:COUNT" line. A line without a count counts once.
+func parseLine(line []byte) (hash []byte, count int, err error) {
+	line = bytes.TrimRight(line, "\r\n")
+	if len(line) < hexHashLen {
+		return nil, 0, fmt.Errorf("%w: short line %q", ErrMalformed, line)
+	}
+	hash, rest := line[:hexHashLen], line[hexHashLen:]
+	if len(rest) == 0 {
+		return hash, 1, nil
+	}
+	if rest[0] != ':' {
+		return nil, 0, fmt.Errorf("%w: line %q", ErrMalformed, line)
+	}
+	count, err = strconv.Atoi(string(rest[1:]))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: count in line %q", ErrMalformed, line)
+	}
+	return hash, count, nil
+}
+
+// compareHex compares a hex hash from the file, in either case, with an
+// uppercase target.
+func compareHex(hash, target []byte) int {
+	for i := range hash {
+		c := hash[i]
+		if 'a' <= c && c <= 'f' {
+			c -= 'a' - 'A'
+		}
+		if c != target[i] {
+			if c < target[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,160 @@
+package passvalpwned
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	passval "github.com/fernandezvara/passvalidator"
+)
+
+// breached maps the passwords written to every test file to their counts.
+var breached = map[string]int{
+	"password":    9545824,
+	"letmein1":    34567,
+	"Tr0ub4dor&3": 12,
+	"rare-one":    1,
+}
+
+// writeDataset writes a sorted dataset with the breached passwords and n
+// filler entries, in the given format, and returns its path.
+func writeDataset(t *testing.T, format Format, n int, lower, crlf bool) string {
+	t.Helper()
+	type entry struct {
+		hash  [sha1.Size]byte
+		count int
+	}
+	var entries []entry
+	for p, c := range breached {
+		entries = append(entries, entry{sha1.Sum([]byte(p)), c})
+	}
+	for i := range n {
+		entries = append(entries, entry{sha1.Sum(fmt.Appendf(nil, "filler-%d", i)), i%50 + 1})
+	}
+	slices.SortFunc(entries, func(a, b entry) int { return slices.Compare(a.hash[:], b.hash[:]) })
+
+	var b strings.Builder
+	for _, e := range entries {
+		if format == FormatBinary {
+			b.Write(e.hash[:])
+			continue
+		}
+		h := strings.ToUpper(hex.EncodeToString(e.hash[:]))
+		if lower {
+			h = strings.ToLower(h)
+		}
+		fmt.Fprintf(&b, "%s:%d", h, e.count)
+		if crlf {
+			b.WriteString("\r\n")
+		} else {
+			b.WriteString("\n")
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "pwned")
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		n      int
+		lower  bool
+		crlf   bool
+	}{
+		{"text", FormatText, 5000, false, true},
+		{"text lowercase LF", FormatText, 5000, true, false},
+		{"text small", FormatText, 10, false, true},
+		{"binary", FormatBinary, 5000, false, false},
+	}
+	for _, tt := range tests {
+		f, err := Open(writeDataset(t, tt.format, tt.n, tt.lower, tt.crlf), tt.format)
+		if err != nil {
+			t.Fatalf("%s: Open: %v", tt.name, err)
+		}
+		for p, want := range breached {
+			if tt.format == FormatBinary {
+				want = 1
+			}
+			if got, err := f.Count(p); err != nil || got != want {
+				t.Errorf("%s: Count(%q) = %d, %v, want %d", tt.name, p, got, err, want)
+			}
+		}
+		for i := range 200 {
+			p := fmt.Sprintf("not-breached-%d", i)
+			if got, err := f.Count(p); err != nil || got != 0 {
+				t.Errorf("%s: Count(%q) = %d, %v, want 0", tt.name, p, got, err)
+			}
+		}
+		if got, err := f.Count("filler-4321"); tt.n > 4321 && (err != nil || got == 0) {
+			t.Errorf("%s: Count(filler) = %d, %v, want listed", tt.name, got, err)
+		}
+		if err := f.Close(); err != nil {
+			t.Errorf("%s: Close: %v", tt.name, err)
+		}
+	}
+}
+
+func TestIsBreached(t *testing.T) {
+	f, err := Open(writeDataset(t, FormatText, 100, false, true), FormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	v := passval.NewPasswordValidator(8, 64, false, false, false, false, 0, passval.WithBreachChecker(f))
+	res, err := v.ValidateContext(context.Background(), "rare-one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Pass || !slices.Contains(res.Codes(), passval.RuleBreached) {
+		t.Errorf("ValidateContext(rare-one) = %v %v, want breached", res.Pass, res.Codes())
+	}
+
+	f.MinCount = 10
+	if breached, _ := f.IsBreached(context.Background(), "rare-one"); breached {
+		t.Error("a password below MinCount should not be reported")
+	}
+	if breached, _ := f.IsBreached(context.Background(), "Tr0ub4dor&3"); !breached {
+		t.Error("a password at MinCount should be reported")
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "short")
+	if err := os.WriteFile(path, make([]byte, 30), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(path, FormatBinary); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Open(30-byte binary) error = %v, want ErrMalformed", err)
+	}
+	if _, err := Open(path, Format(9)); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	if _, err := Open(filepath.Join(t.TempDir(), "missing"), FormatText); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+
+	if err := os.WriteFile(path, []byte("not a hash\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Open(path, FormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Count("password"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Count on a malformed file error = %v, want ErrMalformed", err)
+	}
+}
@@ -2,69 +2,226 @@ package passval
 
 import (
 	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 	"unicode"
+	"unicode/utf8"
 )
 
-// detectPenalties analyzes a password and returns all applicable multiplicative penalties.
-func detectPenalties(password string, dict *dictionary) []PenaltyDetail {
-	var penalties []PenaltyDetail
+// penaltyRules lists the identifiers of the penalties detectPenalties applies.
+var penaltyRules = []string{
+	"common_password", "common_password_leet",
+	"repeated_chars", "sequential_chars", "keyboard_pattern", "repeated_pattern",
+	"dictionary_concatenation", "dictionary_substring",
+	"topical_word", "natural_word", "season_year", "date", "digit_run", "phone_number",
+	"email_address", "url", "single_class",
+}
 
-	lower := strings.ToLower(password)
+// penaltyConfig tunes the detectors for the kind of input being analyzed.
+type penaltyConfig struct {
+	passphrase bool // input is a multi-word passphrase: character diversity is not meaningful
+	redact     bool // keep matched words out of descriptions
+
+	singleClass float64         // factor for passwords of one character class; 0 disables
+	disabled    map[string]bool // penalties turned off with WithDisabledPenalties
+	dictLimit   int             // maximum dictionary words collected per scan; 0 is unlimited
+	dictMinLen  int             // shortest dictionary word penalized; 0 is defaultDictMinMatch
+	dateOrders  dateOrder
+	topical     *topicalWords // names from WithTopicalWordlists, or nil
+	language    *dictionary   // words from WithEnglishWords and WithLanguageWords, or nil
+	trace       *DebugTrace   // records matches and candidates with WithDebugTrace, or nil
+}
 
-	// 1. Common password (exact match or leet-normalized)
-	if p := penaltyCommonPassword(lower, dict); p != nil {
-		penalties = append(penalties, *p)
+// describe formats a description mentioning word w: plain takes the word
+// itself, redacted only its length in characters.
+func (c penaltyConfig) describe(w, plain, redacted string) string {
+	if c.redact {
+		return fmt.Sprintf(redacted, utf8.RuneCountInString(w))
 	}
+	return fmt.Sprintf(plain, w)
+}
 
-	// 2. Repeated characters
-	if p := penaltyRepeatedChars(lower); p != nil {
-		penalties = append(penalties, *p)
+// penaltyConfig returns the detector configuration of the validator for a
+// password that is or is not a passphrase and must or must not stay secret.
+func (v *PasswordValidator) penaltyConfig(passphrase, secret bool) penaltyConfig {
+	return penaltyConfig{
+		passphrase:  passphrase,
+		redact:      v.redact || secret,
+		singleClass: v.singleClass,
+		dictLimit:   v.maxDictWords,
+		dictMinLen:  v.dictMinMatch,
+		dateOrders:  v.dateOrders,
+		topical:     v.topical,
+		language:    v.language,
+		disabled:    v.skipPenalties,
 	}
+}
 
-	// 3. Sequential characters (abc, 123, etc.)
-	if p := penaltySequentialChars(lower); p != nil {
-		penalties = append(penalties, *p)
+// WithDisabledPenalties turns off the penalties with the given identifiers
+// (PenaltyDetail.Rule values, e.g. "keyboard_pattern" for a kiosk where short
+// numeric codes are expected): they are neither applied to the score nor
+// reported. The other penalties and the rules are unaffected. NewValidatorStrict
+// reports unknown identifiers; ReasonCodes lists the known ones after the
+// Rule* codes.
+func WithDisabledPenalties(rules ...string) Option {
+	return func(v *PasswordValidator) {
+		if v.skipPenalties == nil {
+			v.skipPenalties = make(map[string]bool, len(rules))
+		}
+		for _, r := range rules {
+			v.skipPenalties[r] = true
+		}
 	}
+}
+
+// defaultDictMinMatch is the shortest dictionary word, in bytes, the
+// dictionary penalties count unless WithDictMinMatchLen says otherwise.
+const defaultDictMinMatch = 4
 
-	// 4. Keyboard patterns (qwerty, asdf, etc.)
-	if p := penaltyKeyboardPatterns(lower); p != nil {
-		penalties = append(penalties, *p)
+// WithDictMinMatchLen sets the shortest dictionary word, in bytes (characters
+// for ASCII words), that the dictionary_substring and dictionary_concatenation
+// penalties count: 4 by default. Raise it with large wordlists, where short
+// words such as "love" or "star" turn up in most long passphrases.
+// 0 restores the default; NewValidatorStrict reports a negative length.
+func WithDictMinMatchLen(n int) Option {
+	return func(v *PasswordValidator) {
+		v.dictMinMatch = n
 	}
+}
 
-	// 5. Dictionary substring detection (leet-normalized)
-	if p := penaltyDictionarySubstring(lower, dict); p != nil {
-		penalties = append(penalties, *p)
+// minDictMatch returns the shortest dictionary word the dictionary penalties
+// count.
+func (c penaltyConfig) minDictMatch() int {
+	if c.dictMinLen <= 0 {
+		return defaultDictMinMatch
 	}
+	return c.dictMinLen
+}
 
+// penaltyProblems describes the unknown penalty identifiers among disabled.
+func penaltyProblems(disabled map[string]bool) []string {
+	var problems []string
+	for _, r := range slices.Sorted(maps.Keys(disabled)) {
+		if !slices.Contains(penaltyRules, r) {
+			problems = append(problems, fmt.Sprintf("unknown penalty %q", r))
+		}
+	}
+	return problems
+}
+
+// DetectPenalties runs the penalty detectors on password without the rule
+// engine, for analytics pipelines and research tooling that score passwords
+// their own way. opts configure the detectors as they would a validator, e.g.
+// WithLeetMap, WithPassphrasePolicy, WithSingleClassPenalty or
+// WithRedactedMessages; options for rules have no effect. The embedded
+// dictionary is used.
+func DetectPenalties(password string, opts ...Option) []PenaltyDetail {
+	v := NewPasswordValidator(1, 1, false, false, false, false, 0, opts...)
+	_, isPassphrase := v.passphraseWords(password)
+	return detectPenalties(v.analyze(password), v.dictionary(), v.penaltyConfig(isPassphrase, false))
+}
+
+// detectPenalties analyzes a password and returns all applicable multiplicative penalties.
+func detectPenalties(a *analysis, dict *dictionary, cfg penaltyConfig) []PenaltyDetail {
+	penalties, _ := detectPenaltiesUntil(a, dict, cfg, time.Time{})
 	return penalties
 }
 
+// detectPenaltiesUntil is like detectPenalties but stops running detectors
+// once deadline has passed, if it is not zero. complete reports whether every
+// detector ran; a detector that has started is not interrupted.
+func detectPenaltiesUntil(a *analysis, dict *dictionary, cfg penaltyConfig, deadline time.Time) (penalties []PenaltyDetail, complete bool) {
+	detectors := []func() *PenaltyDetail{
+		// 1. Common password (exact match or leet-normalized)
+		func() *PenaltyDetail { return penaltyCommonPassword(a, dict, cfg) },
+		// 2. Repeated characters
+		func() *PenaltyDetail { return penaltyRepeatedChars(a, !cfg.passphrase) },
+		// 3. Sequential characters (abc, 123, etc.)
+		func() *PenaltyDetail { return penaltySequentialChars(a) },
+		// 4. Keyboard patterns (qwerty, asdf, etc.)
+		func() *PenaltyDetail { return penaltyKeyboardPatterns(a) },
+		// 5. Repeated units (abab, xyzxyz, etc.)
+		func() *PenaltyDetail { return penaltyRepeatedPattern(a) },
+		// 6. Dictionary words: a concatenation of several words covering most of
+		// the password, otherwise the longest contained word (leet-normalized)
+		func() *PenaltyDetail {
+			if cfg.disabled["dictionary_concatenation"] {
+				return penaltyDictionarySubstring(a, dict, cfg)
+			}
+			if p := penaltyDictionaryConcatenation(a, dict, cfg); p != nil {
+				if cfg.trace != nil {
+					cfg.trace.candidate(penaltyDictionarySubstring(a, dict, cfg), "superseded by dictionary_concatenation")
+				}
+				return p
+			}
+			return penaltyDictionarySubstring(a, dict, cfg)
+		},
+		// 7. Names from topical wordlists (liverpool1!, lakers2024)
+		func() *PenaltyDetail { return penaltyTopicalWord(a, cfg.topical, dict, cfg) },
+		// 8. Everyday words from natural-language wordlists (mountain7!)
+		func() *PenaltyDetail { return penaltyNaturalWord(a, cfg.language, dict, cfg) },
+		// 9. A month or season next to a year (Summer2024)
+		func() *PenaltyDetail { return penaltySeasonYear(a, cfg) },
+		// 10. A calendar date, weighted by the locale's ordering
+		func() *PenaltyDetail { return penaltyDate(a, cfg) },
+		// 11. Digit runs shaped like phone or ID numbers
+		func() *PenaltyDetail { return penaltyDigitRun(a) },
+		// 12. The password is an email address or a web address
+		func() *PenaltyDetail { return penaltyAddress(a) },
+		// 13. A single character class, when configured
+		func() *PenaltyDetail { return penaltySingleClass(a, cfg) },
+	}
+	for _, detect := range detectors {
+		if !deadline.IsZero() && !time.Now().Before(deadline) {
+			return withReferences(penalties), false
+		}
+		p := detect()
+		switch {
+		case p == nil:
+		case cfg.disabled[p.Rule]:
+			cfg.trace.candidate(p, "disabled")
+		default:
+			cfg.trace.candidate(p, "")
+			penalties = append(penalties, *p)
+		}
+	}
+	return withReferences(penalties), true
+}
+
 // --- Common password (exact match) ---
 
-func penaltyCommonPassword(lower string, dict *dictionary) *PenaltyDetail {
+func penaltyCommonPassword(a *analysis, dict *dictionary, cfg penaltyConfig) *PenaltyDetail {
 	if dict == nil {
 		return nil
 	}
 
-	// Check exact match
-	if dict.contains(lower) {
-		return &PenaltyDetail{
-			Rule:   "common_password",
-			Factor: 0.1, // devastating penalty
-			Desc:   "password is in the common passwords list",
+	// Check exact match, then the reversed password ("drowssap")
+	for _, f := range a.forms() {
+		if dict.contains(f.s) {
+			return a.whole(&PenaltyDetail{
+				Rule:   "common_password",
+				Factor: 0.1, // devastating penalty
+				Desc:   "password is in the common passwords list" + f.note,
+				Match:  f.s,
+			})
 		}
 	}
 
-	// Check leet-speak normalized variants
-	variants := leetVariants(lower)
-	for _, v := range variants {
-		if dict.contains(v) {
-			return &PenaltyDetail{
+	// Check leet-speak substitutions
+	for _, f := range a.forms() {
+		if w := dict.leetMatch(f.s, a.leet); w != "" {
+			return a.whole(&PenaltyDetail{
 				Rule:   "common_password_leet",
 				Factor: 0.15,
-				Desc:   fmt.Sprintf("password matches common password via leet-speak (%s)", v),
-			}
+				Desc: cfg.describe(w, "password matches common password via leet-speak (%s)",
+					"password matches common password via leet-speak (%d chars)") + f.note,
+				Match: w,
+			})
 		}
 	}
 
@@ -73,30 +230,11 @@ func penaltyCommonPassword(lower string, dict *dictionary) *PenaltyDetail {
 
 // --- Repeated characters ---
 
-func penaltyRepeatedChars(lower string) *PenaltyDetail {
-	if len(lower) < 3 {
+func penaltyRepeatedChars(a *analysis, checkDiversity bool) *PenaltyDetail {
+	if len(a.lower) < 3 {
 		return nil
 	}
-
-	maxRepeat := 1
-	current := 1
-	for i := 1; i < len(lower); i++ {
-		if lower[i] == lower[i-1] {
-			current++
-			if current > maxRepeat {
-				maxRepeat = current
-			}
-		} else {
-			current = 1
-		}
-	}
-
-	// Also check ratio of unique chars to total length
-	unique := make(map[rune]bool)
-	for _, r := range lower {
-		unique[r] = true
-	}
-	uniqueRatio := float64(len(unique)) / float64(len(lower))
+	maxRepeat := a.maxRepeat
 
 	var factor float64 = 1.0
 	var reasons []string
@@ -109,68 +247,69 @@ func penaltyRepeatedChars(lower string) *PenaltyDetail {
 		reasons = append(reasons, fmt.Sprintf("%d consecutive repeated characters", maxRepeat))
 	}
 
-	if uniqueRatio < 0.4 {
-		factor *= 0.5
-		reasons = append(reasons, fmt.Sprintf("low character diversity (%.0f%% unique)", uniqueRatio*100))
-	} else if uniqueRatio < 0.6 {
-		factor *= 0.7
-		reasons = append(reasons, fmt.Sprintf("moderate character diversity (%.0f%% unique)", uniqueRatio*100))
+	// Also check ratio of unique chars to total length
+	if checkDiversity {
+		n := len(a.lower)
+		if a.clusters != nil {
+			n = a.length()
+		}
+		uniqueRatio := float64(a.uniqueRunes) / float64(n)
+
+		if uniqueRatio < 0.4 {
+			factor *= 0.5
+			reasons = append(reasons, fmt.Sprintf("low character diversity (%.0f%% unique)", uniqueRatio*100))
+		} else if uniqueRatio < 0.6 {
+			factor *= 0.7
+			reasons = append(reasons, fmt.Sprintf("moderate character diversity (%.0f%% unique)", uniqueRatio*100))
+		}
 	}
 
-	if factor < 1.0 {
-		return &PenaltyDetail{
-			Rule:   "repeated_chars",
-			Factor: factor,
-			Desc:   strings.Join(reasons, "; "),
-		}
+	if factor == 1.0 {
+		return nil
 	}
-	return nil
+	p := &PenaltyDetail{
+		Rule:   "repeated_chars",
+		Factor: factor,
+		Desc:   strings.Join(reasons, "; "),
+	}
+	if maxRepeat >= 3 {
+		return a.spanned(p, a.repeatAt, a.repeatEnd)
+	}
+	return a.whole(p)
 }
 
 // --- Sequential characters ---
 
-func penaltySequentialChars(lower string) *PenaltyDetail {
-	if len(lower) < 3 {
+func penaltySequentialChars(a *analysis) *PenaltyDetail {
+	if len(a.lower) < 3 {
 		return nil
 	}
+	maxSeq := a.maxSequence
 
-	maxSeq := 1
-	current := 1
-	for i := 1; i < len(lower); i++ {
-		diff := int(lower[i]) - int(lower[i-1])
-		if diff == 1 || diff == -1 {
-			current++
-			if current > maxSeq {
-				maxSeq = current
-			}
-		} else {
-			current = 1
-		}
-	}
-
-	if maxSeq >= 5 {
-		return &PenaltyDetail{
+	var p *PenaltyDetail
+	switch {
+	case maxSeq >= 5:
+		p = &PenaltyDetail{
 			Rule:   "sequential_chars",
 			Factor: 0.3,
 			Desc:   fmt.Sprintf("long sequential pattern detected (%d chars)", maxSeq),
 		}
-	}
-	if maxSeq >= 4 {
-		return &PenaltyDetail{
+	case maxSeq >= 4:
+		p = &PenaltyDetail{
 			Rule:   "sequential_chars",
 			Factor: 0.5,
 			Desc:   fmt.Sprintf("sequential pattern detected (%d chars)", maxSeq),
 		}
-	}
-	if maxSeq >= 3 {
-		return &PenaltyDetail{
+	case maxSeq >= 3:
+		p = &PenaltyDetail{
 			Rule:   "sequential_chars",
 			Factor: 0.7,
 			Desc:   fmt.Sprintf("short sequential pattern detected (%d chars)", maxSeq),
 		}
+	default:
+		return nil
 	}
-
-	return nil
+	return a.spanned(p, a.sequenceAt, a.sequenceAt+maxSeq)
 }
 
 // --- Keyboard patterns ---
@@ -187,124 +326,536 @@ var keyboardRows = []string{
 	"yujm",
 }
 
-func penaltyKeyboardPatterns(lower string) *PenaltyDetail {
-	bestMatch := 0
-
+// keyboardKeys holds each keyboard row forwards and backwards, as runes,
+// built once for all validations.
+var keyboardKeys = func() [][]rune {
+	var keys [][]rune
 	for _, row := range keyboardRows {
-		match := longestCommonSubstringLen(lower, row)
-		if match > bestMatch {
-			bestMatch = match
+		keys = append(keys, []rune(row), []rune(reverseString(row)))
+	}
+	return keys
+}()
+
+// shiftedKeys maps the symbols typed with Shift on a US keyboard to their
+// keys, so that "!@#$" and "1@3$" read as the walk "1234".
+var shiftedKeys = map[rune]rune{
+	'!': '1', '@': '2', '#': '3', '$': '4', '%': '5',
+	'^': '6', '&': '7', '*': '8', '(': '9', ')': '0',
+}
+
+// unshift returns runes with shifted symbols replaced by their keys, and
+// whether any was replaced.
+func unshift(runes []rune) ([]rune, bool) {
+	var out []rune
+	for i, r := range runes {
+		if k, ok := shiftedKeys[r]; ok {
+			if out == nil {
+				out = slices.Clone(runes)
+			}
+			out[i] = k
+		}
+	}
+	return out, out != nil
+}
+
+// minWalkLink is the shortest keyboard run that extends a walk started on
+// another row, as in "qwerty123456".
+const minWalkLink = 3
+
+// keyboardWalk returns the start and length of the longest keyboard walk in
+// runes: a run along a keyboard row, forwards or backwards, possibly followed
+// directly by runs of at least minWalkLink keys on other rows.
+func keyboardWalk(runes []rune) (start, n int) {
+	// run[i] is the longest row run starting at rune i
+	run := make([]int, len(runes)+1)
+	for _, keys := range keyboardKeys {
+		for i := range runes {
+			j := slices.Index(keys, runes[i])
+			if j < 0 {
+				continue
+			}
+			k := 1
+			for i+k < len(runes) && j+k < len(keys) && runes[i+k] == keys[j+k] {
+				k++
+			}
+			run[i] = max(run[i], k)
+		}
+	}
+
+	// chain[i] is the length of the walk starting at rune i
+	chain := make([]int, len(runes)+1)
+	for i := len(runes) - 1; i >= 0; i-- {
+		chain[i] = run[i]
+		if next := i + run[i]; run[i] >= minWalkLink && run[next] >= minWalkLink {
+			chain[i] += chain[next]
 		}
-		// Also check reversed row
-		rev := reverseString(row)
-		match = longestCommonSubstringLen(lower, rev)
-		if match > bestMatch {
-			bestMatch = match
+		if chain[i] > n {
+			start, n = i, chain[i]
 		}
 	}
+	return start, n
+}
+
+func penaltyKeyboardPatterns(a *analysis) *PenaltyDetail {
+	bestMatch, bestAt := 0, 0
+	shifted := false
 
-	if bestMatch >= 6 {
-		return &PenaltyDetail{
+	// Letters are matched in lowercase, so capitals typed with Shift do not
+	// break a walk; the unshifted form does the same for symbols.
+	forms := [][]rune{a.runes}
+	if u, ok := unshift(a.runes); ok {
+		forms = append(forms, u)
+	}
+	for i, form := range forms {
+		if at, match := keyboardWalk(form); match > bestMatch {
+			bestMatch, bestAt, shifted = match, at, i == 1
+		}
+	}
+
+	var p *PenaltyDetail
+	switch {
+	case bestMatch >= 6:
+		p = &PenaltyDetail{
 			Rule:   "keyboard_pattern",
 			Factor: 0.2,
 			Desc:   fmt.Sprintf("long keyboard pattern detected (%d chars)", bestMatch),
 		}
-	}
-	if bestMatch >= 5 {
-		return &PenaltyDetail{
+	case bestMatch >= 5:
+		p = &PenaltyDetail{
 			Rule:   "keyboard_pattern",
 			Factor: 0.4,
 			Desc:   fmt.Sprintf("keyboard pattern detected (%d chars)", bestMatch),
 		}
-	}
-	if bestMatch >= 4 {
-		return &PenaltyDetail{
+	case bestMatch >= 4:
+		p = &PenaltyDetail{
 			Rule:   "keyboard_pattern",
 			Factor: 0.6,
 			Desc:   fmt.Sprintf("short keyboard pattern detected (%d chars)", bestMatch),
 		}
+	default:
+		return nil
 	}
-
-	return nil
+	if shifted {
+		p.Desc += " with shifted symbols"
+	}
+	return a.spanned(p, bestAt, bestAt+bestMatch)
 }
 
-// --- Dictionary substring (leet-normalized) ---
+// --- Repeated patterns ---
 
-func penaltyDictionarySubstring(lower string, dict *dictionary) *PenaltyDetail {
-	if dict == nil {
+// maxPatternUnit is the longest repeating unit looked for by penaltyRepeatedPattern.
+const maxPatternUnit = 4
+
+// penaltyRepeatedPattern detects a short unit repeated back to back
+// ("abababab", "xyxyxyxy", "abcabcabc"), which the repeated character and
+// sequence checks miss. The factor falls with the share of the password the
+// repetition covers.
+func penaltyRepeatedPattern(a *analysis) *PenaltyDetail {
+	start, length, unit := repeatedUnit(a.runes)
+	if unit == 0 {
 		return nil
 	}
 
-	// Check if any common password >= 4 chars is a substring of the password
-	normalized := leetNormalize(lower)
+	ratio := float64(length) / float64(len(a.runes))
+	var factor float64
+	switch {
+	case ratio >= 0.8:
+		factor = 0.3
+	case ratio >= 0.5:
+		factor = 0.5
+	case ratio >= 0.3:
+		factor = 0.7
+	default:
+		return nil
+	}
+	return a.spanned(&PenaltyDetail{
+		Rule:   "repeated_pattern",
+		Factor: factor,
+		Desc:   fmt.Sprintf("%d-character pattern repeated over %.0f%% of the password", unit, ratio*100),
+		Match:  a.password[a.offsets[start]:a.offsets[start+unit]],
+	}, start, start+length)
+}
 
-	longestMatch := ""
-	for _, word := range dict.words {
-		if len(word) < 4 {
-			continue
+// repeatedUnit returns the longest run of runes that repeats a unit of 2 to
+// maxPatternUnit runes, with its start, length and unit length. Bigrams must
+// occur three times and longer units twice; units of one repeated rune are
+// left to the repeated character check. unit is 0 if there is no such run.
+func repeatedUnit(runes []rune) (start, length, unit int) {
+	for u := 2; u <= maxPatternUnit; u++ {
+		minLength := 2 * u
+		if u == 2 {
+			minLength = 3 * u
 		}
-		if strings.Contains(lower, word) || strings.Contains(normalized, word) {
-			if len(word) > len(longestMatch) {
-				longestMatch = word
+		// runes [runStart, i) repeat their first u runes while runes[i] == runes[i-u]
+		runStart := 0
+		for i := u; i <= len(runes); i++ {
+			if i < len(runes) && runes[i] == runes[i-u] {
+				continue
 			}
+			if n := i - runStart; n >= minLength && n > length && !uniform(runes[runStart:runStart+u]) {
+				start, length, unit = runStart, n, u
+			}
+			runStart = i - u + 1
 		}
 	}
+	return start, length, unit
+}
 
-	if longestMatch == "" {
-		return nil
+// uniform reports whether all runes of s are the same.
+func uniform(s []rune) bool {
+	for _, r := range s {
+		if r != s[0] {
+			return false
+		}
 	}
+	return true
+}
 
-	ratio := float64(len(longestMatch)) / float64(len(lower))
+// --- Dictionary coverage ---
+
+// dictTiling returns the non-overlapping matches that together cover the most
+// characters, in order of position, and the number they cover. Of tilings
+// with the same coverage it prefers the one with fewer, longer words, so
+// "monkeydragon" is monkey+dragon and fragments of a longer word do not add
+// up past the word itself.
+func dictTiling(matches []dictMatch) (tiling []dictMatch, covered int) {
+	ms := slices.Clone(matches)
+	slices.SortFunc(ms, func(a, b dictMatch) int { return a.end - b.end })
+
+	// best[i] is the best tiling of the first i matches: its coverage, its
+	// number of words, and the index of its last match, or -1 for none.
+	type cell struct{ covered, words, last int }
+	best := make([]cell, len(ms)+1)
+	best[0].last = -1
+	for i, m := range ms {
+		// Matches are sorted by end, so those ending by m.start are a prefix.
+		p := sort.Search(i, func(j int) bool { return ms[j].end > m.start })
+		take := cell{best[p].covered + m.end - m.start, best[p].words + 1, i}
+		skip := best[i]
+		if take.covered > skip.covered || take.covered == skip.covered && take.words < skip.words {
+			best[i+1] = take
+		} else {
+			best[i+1] = skip
+		}
+	}
 
-	if ratio >= 0.8 {
-		// Password is mostly a dictionary word with minor additions
-		return &PenaltyDetail{
-			Rule:   "dictionary_substring",
-			Factor: 0.2,
-			Desc:   fmt.Sprintf("password is mostly the dictionary word '%s'", longestMatch),
+	for i := len(ms); i > 0 && best[i].last >= 0; {
+		m := ms[best[i].last]
+		tiling = append(tiling, m)
+		i = sort.Search(best[i].last, func(j int) bool { return ms[j].end > m.start })
+	}
+	slices.Reverse(tiling)
+	return tiling, best[len(ms)].covered
+}
+
+// dictWords returns the distinct words of matches, in order.
+func dictWords(matches []dictMatch) []string {
+	var words []string
+	for _, m := range matches {
+		if !slices.Contains(words, m.word) {
+			words = append(words, m.word)
 		}
 	}
-	if ratio >= 0.5 {
-		return &PenaltyDetail{
-			Rule:   "dictionary_substring",
-			Factor: 0.5,
-			Desc:   fmt.Sprintf("password contains dictionary word '%s'", longestMatch),
+	return words
+}
+
+// --- Dictionary substring (leet-normalized) ---
+
+// penaltyDictionarySubstring penalizes dictionary words by the share of the
+// password that they cover together, without overlapping, and by their
+// context: words that stand on their own at class or case transitions
+// ("Xk9dragonQ7") take the full penalty, words all running into other
+// letters half of it, and fragments of a longer capitalized word ("rangers"
+// in "StrangersInParadise42!") count for nothing unless the words make up
+// most of the password. Reading the password backwards is tried too.
+func penaltyDictionarySubstring(a *analysis, dict *dictionary, cfg penaltyConfig) *PenaltyDetail {
+	if dict == nil {
+		return nil
+	}
+
+	var best *PenaltyDetail
+	var bestCovered int
+	for _, f := range a.forms() {
+		var matches []dictMatch
+		for _, m := range cfg.scanWords(a, "dictionary", dict, f, cfg.minDictMatch()) {
+			m.start, m.end = a.spanOf(m, f)
+			matches = append(matches, m)
+		}
+		p, covered := dictionarySubstringPenalty(a, matches, cfg, f.note)
+		if p == nil {
+			continue
 		}
+		if best == nil || p.Factor < best.Factor || p.Factor == best.Factor && covered > bestCovered {
+			p, best, bestCovered = best, p, covered
+		}
+		cfg.trace.candidate(p, "weaker than the match in the other direction")
 	}
-	if ratio >= 0.3 {
-		return &PenaltyDetail{
-			Rule:   "dictionary_substring",
-			Factor: 0.7,
-			Desc:   fmt.Sprintf("password contains dictionary word '%s'", longestMatch),
+	return best
+}
+
+// dictionarySubstringPenalty returns the penalty for the dictionary matches
+// at rune spans of the password, and the characters they cover, or nil if
+// they do not count.
+func dictionarySubstringPenalty(a *analysis, matches []dictMatch, cfg penaltyConfig, note string) (*PenaltyDetail, int) {
+	n := float64(len(a.runes))
+	tiling, covered := dictTiling(matches)
+	if len(tiling) == 0 {
+		return nil, 0
+	}
+	if float64(covered)/n >= 0.8 {
+		// Password is mostly dictionary words with minor additions
+		return dictionarySubstringDetail(a, tiling, 0.2, cfg, true, note), covered
+	}
+
+	// Fragments of longer capitalized words do not count, and words running
+	// into other letters on either side count less.
+	var counted []dictMatch
+	for _, m := range matches {
+		startAligned, endAligned := a.boundary(m.start), a.boundary(m.end)
+		if !startAligned && a.inCapitalizedWord(m.start) || !endAligned && a.inCapitalizedWord(m.end-1) {
+			continue
 		}
+		counted = append(counted, m)
 	}
+	tiling, covered = dictTiling(counted)
 
-	return nil
+	var factor float64
+	switch coverage := float64(covered) / n; {
+	case coverage >= 0.5:
+		factor = 0.5
+	case coverage >= 0.3:
+		factor = 0.7
+	default:
+		return nil, 0
+	}
+	if !slices.ContainsFunc(tiling, func(m dictMatch) bool { return a.boundary(m.start) && a.boundary(m.end) }) {
+		factor = 1 - (1-factor)/2
+	}
+	return dictionarySubstringDetail(a, tiling, factor, cfg, false, note), covered
 }
 
-// --- Helpers ---
+// dictionarySubstringDetail builds the dictionary_substring penalty for the
+// words of tiling, spanning from the first to the last.
+func dictionarySubstringDetail(a *analysis, tiling []dictMatch, factor float64, cfg penaltyConfig, mostly bool, note string) *PenaltyDetail {
+	words := dictWords(tiling)
+	var desc string
+	switch {
+	case len(words) == 1 && mostly:
+		desc = cfg.describe(words[0], "password is mostly the dictionary word '%s'",
+			"password is mostly a common dictionary word (%d chars)")
+	case len(words) == 1:
+		desc = cfg.describe(words[0], "password contains dictionary word '%s'",
+			"password contains a common dictionary word (%d chars)")
+	case cfg.redact && mostly:
+		desc = fmt.Sprintf("password is mostly %d common dictionary words", len(words))
+	case cfg.redact:
+		desc = fmt.Sprintf("password contains %d common dictionary words", len(words))
+	case mostly:
+		desc = fmt.Sprintf("password is mostly dictionary words '%s'", strings.Join(words, "', '"))
+	default:
+		desc = fmt.Sprintf("password contains dictionary words '%s'", strings.Join(words, "', '"))
+	}
+	return a.spanned(&PenaltyDetail{
+		Rule:   "dictionary_substring",
+		Factor: factor,
+		Desc:   desc + note,
+		Match:  strings.Join(words, "+"),
+	}, tiling[0].start, tiling[len(tiling)-1].end)
+}
+
+// --- Dictionary concatenation ---
 
-func longestCommonSubstringLen(a, b string) int {
-	if len(a) == 0 || len(b) == 0 {
-		return 0
+// minConcatCoverage is the share of a password that matched dictionary words
+// must cover for it to count as a concatenation of common passwords.
+const minConcatCoverage = 0.7
+
+// penaltyDictionaryConcatenation detects passwords built from two or more
+// dictionary words ("qwertydragon", "letmeinmonkey"). Coverage is counted
+// over the non-overlapping words that cover the most of the password, so
+// overlapping fragments of one longer word ("considerations") do not add up
+// to a concatenation. The factor falls with the share of the password the
+// words cover.
+func penaltyDictionaryConcatenation(a *analysis, dict *dictionary, cfg penaltyConfig) *PenaltyDetail {
+	if dict == nil {
+		return nil
 	}
 
-	maxLen := 0
-	// Simple O(n*m) approach — fine for short strings (passwords)
-	for i := 0; i < len(a); i++ {
-		for j := 0; j < len(b); j++ {
-			k := 0
-			for i+k < len(a) && j+k < len(b) && a[i+k] == b[j+k] {
-				k++
-			}
-			if k > maxLen {
-				maxLen = k
+	tiling, covered := dictTiling(cfg.scanWords(a, "dictionary", dict, a.forms()[0], cfg.minDictMatch()))
+	words := dictWords(tiling)
+	coverage := float64(covered) / float64(len(a.runes))
+	if coverage < minConcatCoverage || len(words) < 2 {
+		return nil
+	}
+
+	desc := fmt.Sprintf("password is mostly dictionary words '%s' (%.0f%% coverage)", strings.Join(words, "', '"), coverage*100)
+	if cfg.redact {
+		desc = fmt.Sprintf("password is mostly %d common dictionary words (%.0f%% coverage)", len(words), coverage*100)
+	}
+	return a.spanned(&PenaltyDetail{
+		Rule:   "dictionary_concatenation",
+		Factor: math.Round((1-0.8*coverage)*100) / 100,
+		Desc:   desc,
+		Match:  strings.Join(words, "+"),
+	}, tiling[0].start, tiling[len(tiling)-1].end)
+}
+
+// --- Digit runs and phone numbers ---
+
+// minDigitRunCoverage is the share of a password that a digit run must cover
+// to be penalized.
+const minDigitRunCoverage = 0.6
+
+// penaltyDigitRun detects passwords that are mostly a run of 7-15 digits, the
+// shape of phone and national ID numbers ("6915553412aB!"). Phone separators
+// inside the run ("(691) 555-3412") are skipped, and runs in a common phone
+// format are penalized harder.
+func penaltyDigitRun(a *analysis) *PenaltyDetail {
+	start, end, digits := longestDigitRun(a.runes)
+	if digits < 7 || digits > 15 || float64(end-start) < minDigitRunCoverage*float64(len(a.runes)) {
+		return nil
+	}
+
+	p := &PenaltyDetail{
+		Rule:   "digit_run",
+		Factor: 0.5,
+		Desc:   fmt.Sprintf("password is mostly a run of %d digits", digits),
+	}
+	if isPhoneNumber(a.runes[start:end]) {
+		p.Rule, p.Factor = "phone_number", 0.3
+		p.Desc = fmt.Sprintf("password is mostly a phone number (%d digits)", digits)
+	}
+	p.Match = a.password[a.offsets[start]:a.offsets[end]]
+	return a.spanned(p, start, end)
+}
+
+// longestDigitRun returns the rune span and digit count of the run of ASCII
+// digits with the most digits, allowing phone separators between them and a
+// leading '+' or '('.
+func longestDigitRun(runes []rune) (start, end, digits int) {
+	for i := 0; i < len(runes); i++ {
+		if !isASCIIDigit(runes[i]) && (!strings.ContainsRune("+(", runes[i]) || i+1 == len(runes) || !isASCIIDigit(runes[i+1])) {
+			continue
+		}
+		j, last, n := i, i, 0
+		for ; j < len(runes) && (isASCIIDigit(runes[j]) || j == i || strings.ContainsRune(phoneSeparators, runes[j])); j++ {
+			if isASCIIDigit(runes[j]) {
+				last, n = j, n+1
 			}
 		}
+		if n > digits {
+			start, end, digits = i, last+1, n
+		}
+		i = j - 1
+	}
+	return start, end, digits
+}
+
+// --- Email addresses and URLs ---
+
+var (
+	emailPattern = regexp.MustCompile(`[a-z0-9._%+-]+@[a-z0-9-]+(?:\.[a-z0-9-]+)*\.[a-z]{2,24}`)
+	urlPattern   = regexp.MustCompile(`([a-z][a-z0-9+.-]*://)?(www\.)?(?:[a-z0-9-]+\.)+([a-z]{2,24})(?:/[!-~]*)?`)
+)
+
+// commonTLDs are the top-level domains that mark a bare host name
+// ("facebook.com") as a web address; with a scheme or "www." any is accepted.
+var commonTLDs = []string{
+	"com", "net", "org", "edu", "gov", "info", "biz", "io", "co", "me", "tv", "app", "dev", "xyz",
+	"us", "uk", "ca", "au", "de", "fr", "es", "it", "nl", "be", "ch", "at", "se", "no", "dk", "fi",
+	"pl", "pt", "ru", "br", "ar", "mx", "cl", "in", "jp", "cn", "kr", "eu",
+}
+
+// minAddressCoverage is the share of the password an email address or URL
+// must cover to be penalized: the password is the address, perhaps with a
+// digit or symbol added.
+const minAddressCoverage = 0.75
+
+// penaltyAddress detects passwords that are an email address
+// ("john.doe@gmail.com") or a web address ("www.facebook.com"), which are
+// easy to find out and which attackers try from leaked account data,
+// whatever words they contain.
+func penaltyAddress(a *analysis) *PenaltyDetail {
+	if m := emailPattern.FindStringIndex(a.lower); m != nil {
+		return addressPenalty(a, m, &PenaltyDetail{
+			Rule:   "email_address",
+			Factor: 0.2,
+			Desc:   "password is an email address",
+		})
+	}
+	for _, m := range urlPattern.FindAllStringSubmatchIndex(a.lower, -1) {
+		scheme, www, tld := m[2] >= 0, m[4] >= 0, a.lower[m[6]:m[7]]
+		if scheme || www || slices.Contains(commonTLDs, tld) {
+			return addressPenalty(a, m[:2], &PenaltyDetail{
+				Rule:   "url",
+				Factor: 0.25,
+				Desc:   "password is a web address",
+			})
+		}
 	}
-	return maxLen
+	return nil
+}
+
+// addressPenalty returns p spanning the byte range m of a.lower, or nil if
+// the range covers less than minAddressCoverage of the password.
+func addressPenalty(a *analysis, m []int, p *PenaltyDetail) *PenaltyDetail {
+	start := utf8.RuneCountInString(a.lower[:m[0]])
+	end := start + utf8.RuneCountInString(a.lower[m[0]:m[1]])
+	if float64(end-start) < minAddressCoverage*float64(len(a.runes)) {
+		return nil
+	}
+	p.Match = a.password[a.offsets[start]:a.offsets[end]]
+	return a.spanned(p, start, end)
+}
+
+// phoneSeparators may appear between the digits of a formatted phone number.
+const phoneSeparators = " -.()"
+
+// isPhoneNumber reports whether a digit run is in a common phone format: an
+// international number with a leading '+', or a North American number of
+// ten digits (eleven with a leading 1) whose area code and exchange do not
+// start with 0 or 1.
+func isPhoneNumber(run []rune) bool {
+	var digits []rune
+	for _, r := range run {
+		if isASCIIDigit(r) {
+			digits = append(digits, r)
+		}
+	}
+	if run[0] == '+' {
+		return len(digits) >= 8
+	}
+	if len(digits) == 11 && digits[0] == '1' {
+		digits = digits[1:]
+	}
+	return len(digits) == 10 && digits[0] >= '2' && digits[3] >= '2'
 }
 
+func isASCIIDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+// --- Single character class ---
+
+// penaltySingleClass penalizes passwords made entirely of one character class
+// ("asdkjhqwelkjzxc", "849302184093") whatever their length, for policies
+// that drop composition rules.
+func penaltySingleClass(a *analysis, cfg penaltyConfig) *PenaltyDetail {
+	if cfg.singleClass <= 0 {
+		return nil
+	}
+	class := singleClass(len(a.runes), a.lowerCount, a.upperCount, a.numberCount, a.symbolCount)
+	if class == "" {
+		return nil
+	}
+	return a.whole(&PenaltyDetail{
+		Rule:   "single_class",
+		Factor: cfg.singleClass,
+		Desc:   "password uses only " + class,
+	})
+}
+
+// --- Helpers ---
+
 func reverseString(s string) string {
 	runes := []rune(s)
 	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
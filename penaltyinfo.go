@@ -0,0 +1,127 @@
+package passval
+
+import "strings"
+
+// PenaltyExplanation documents a penalty for help pages: why passwords with
+// the pattern are weak and how much the score is reduced.
+type PenaltyExplanation struct {
+	Code      string `json:"code"`      // the PenaltyDetail.Rule, e.g. "keyboard_pattern"
+	Reference string `json:"reference"` // stable identifier, e.g. "penalty/keyboard-pattern"
+	Title     string `json:"title"`
+	Rationale string `json:"rationale"`
+}
+
+// penaltyExplanations holds the title and rationale of every penalty, for
+// passwords and PINs.
+var penaltyExplanations = map[string]PenaltyExplanation{
+	"common_password": {
+		Title:     "Common password",
+		Rationale: "Attackers try lists of the most used passwords first, so a listed password falls within the first guesses.",
+	},
+	"common_password_leet": {
+		Title:     "Common password with substitutions",
+		Rationale: "Replacing letters with look-alike digits and symbols (p@ssw0rd) is one of the first rules cracking tools apply to common passwords.",
+	},
+	"repeated_chars": {
+		Title:     "Repeated characters",
+		Rationale: "A run of the same character adds length but almost no guesses: an attacker only needs the character and the run length.",
+	},
+	"sequential_chars": {
+		Title:     "Sequential characters",
+		Rationale: "Sequences such as abc or 4321 are fixed by their first character, length and direction, so they add few guesses.",
+	},
+	"keyboard_pattern": {
+		Title:     "Keyboard pattern",
+		Rationale: "Walks along the keyboard (qwerty, asdf, 1qaz) are in every cracking dictionary, with or without Shift.",
+	},
+	"repeated_pattern": {
+		Title:     "Repeated pattern",
+		Rationale: "Repeating a short unit (abcabc) is barely harder to guess than the unit itself.",
+	},
+	"dictionary_concatenation": {
+		Title:     "Joined common words",
+		Rationale: "Combinator attacks join words from common password lists, so a password made of a few of them is guessed quickly.",
+	},
+	"dictionary_substring": {
+		Title:     "Common word",
+		Rationale: "A common word makes up a large part of the password; attackers try words with added digits and symbols early.",
+	},
+	"topical_word": {
+		Title:     "Popular name",
+		Rationale: "Names of teams, cities, bands and heroes are what many people pick first, and attackers add them to their wordlists.",
+	},
+	"natural_word": {
+		Title:     "Everyday word",
+		Rationale: "Attackers try the vocabulary of a language after leaked passwords; a few thousand everyday words add far fewer guesses than random characters of the same length.",
+	},
+	"season_year": {
+		Title:     "Season or month with a year",
+		Rationale: "Passwords like Summer2024 follow forced rotation schedules and are among the first guesses in password spraying.",
+	},
+	"date": {
+		Title:     "Date",
+		Rationale: "Birthdates and anniversaries are among the first guesses of targeted attacks, and there are only about 36,500 dates in a century.",
+	},
+	"digit_run": {
+		Title:     "Long run of digits",
+		Rationale: "Long digit runs are often ID, account or card numbers that can be found or guessed from personal data.",
+	},
+	"phone_number": {
+		Title:     "Phone number",
+		Rationale: "Phone numbers are public or easy to find, and their country and area codes are predictable.",
+	},
+	"email_address": {
+		Title:     "Email address",
+		Rationale: "Email addresses are public or easy to find, and leaked account data pairs them with the accounts attackers target.",
+	},
+	"url": {
+		Title:     "Web address",
+		Rationale: "Addresses of popular sites, often the site the password is for, are short lists that attackers try early.",
+	},
+	"single_class": {
+		Title:     "Single character class",
+		Rationale: "A password of only letters or only digits draws every character from a small pool.",
+	},
+	"common_pin": {
+		Title:     "Common PIN",
+		Rationale: "A small set of PINs (1234, 0000, 1111) covers a large share of real PINs and is tried first.",
+	},
+	"repeated_digits": {
+		Title:     "Repeated digits",
+		Rationale: "PINs with long runs or few distinct digits are a small, frequently tried subset of all PINs.",
+	},
+	"sequential_digits": {
+		Title:     "Sequential digits",
+		Rationale: "Ascending and descending digit runs are among the most common PINs.",
+	},
+	"date_pin": {
+		Title:     "Date-shaped PIN",
+		Rationale: "Years and dates, such as birthdays, make up a large share of chosen PINs and are easy to find out.",
+	},
+}
+
+// PenaltyInfo returns the explanation of the penalty code, as found in
+// PenaltyDetail.Rule and Result.Codes, and whether the code is known. Use the
+// Reference to link help articles to deductions without matching on
+// descriptions, which may change.
+func PenaltyInfo(code string) (PenaltyExplanation, bool) {
+	e, ok := penaltyExplanations[code]
+	if !ok {
+		return PenaltyExplanation{}, false
+	}
+	e.Code, e.Reference = code, penaltyReference(code)
+	return e, true
+}
+
+// penaltyReference returns the stable identifier of the penalty code.
+func penaltyReference(code string) string {
+	return "penalty/" + strings.ReplaceAll(code, "_", "-")
+}
+
+// withReferences sets the Reference of each penalty and returns penalties.
+func withReferences(penalties []PenaltyDetail) []PenaltyDetail {
+	for i := range penalties {
+		penalties[i].Reference = penaltyReference(penalties[i].Rule)
+	}
+	return penalties
+}
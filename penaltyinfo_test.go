@@ -0,0 +1,36 @@
+package passval
+
+import "testing"
+
+func TestPenaltyInfo(t *testing.T) {
+	for _, code := range append(penaltyRules, "common_pin", "repeated_digits", "sequential_digits", "date_pin") {
+		e, ok := PenaltyInfo(code)
+		if !ok {
+			t.Errorf("PenaltyInfo(%q) not found", code)
+			continue
+		}
+		if e.Code != code || e.Reference == "" || e.Title == "" || e.Rationale == "" {
+			t.Errorf("PenaltyInfo(%q) = %+v, want all fields set", code, e)
+		}
+	}
+	if _, ok := PenaltyInfo(RuleTooShort); ok {
+		t.Errorf("PenaltyInfo(%q) found, want only penalties", RuleTooShort)
+	}
+
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0)
+	r := v.ValidateResult("qwerty123")
+	if len(r.Penalties) == 0 {
+		t.Fatal("ValidateResult(qwerty123) has no penalties")
+	}
+	for _, p := range r.Penalties {
+		if e, _ := PenaltyInfo(p.Rule); p.Reference != e.Reference {
+			t.Errorf("%s: Reference = %q, want %q", p.Rule, p.Reference, e.Reference)
+		}
+	}
+
+	_, _, err := NewPINPolicy(4, 8, 50).ValidateVerbose("1234")
+	ve, ok := err.(*ValidationError)
+	if !ok || len(ve.Penalties) == 0 || ve.Penalties[0].Reference != "penalty/common-pin" {
+		t.Errorf("ValidateVerbose(1234) err = %v, want a common-pin reference", err)
+	}
+}
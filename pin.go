@@ -0,0 +1,271 @@
+package passval
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// PIN length bounds accepted by PINPolicy.
+const (
+	MinPINLength = 4
+	MaxPINLength = 12
+)
+
+// pinCurveK is the score curve constant for PINs: a random 4-digit PIN scores ~63
+// and a random 6-digit PIN ~78, so short numeric codes can be policy-driven.
+var pinCurveK = MinPINLength * math.Log2(10)
+
+// commonPINs is the PIN denylist: the most frequently chosen codes and
+// keypad shapes (2580 is the middle column of a phone keypad).
+var commonPINs = map[string]bool{
+	"1234": true, "0000": true, "1111": true, "2222": true, "3333": true,
+	"4444": true, "5555": true, "6666": true, "7777": true, "8888": true,
+	"9999": true, "1212": true, "1004": true, "2000": true, "6969": true,
+	"1122": true, "1313": true, "4321": true, "2001": true, "1010": true,
+	"2580": true, "0852": true, "1470": true, "7410": true, "3690": true,
+	"0963": true, "1379": true, "1397": true, "7931": true, "2468": true,
+	"1357": true, "0123": true, "9876": true, "5683": true, "0007": true,
+	"123456": true, "654321": true, "111111": true, "000000": true, "121212": true,
+	"112233": true, "159753": true, "147258": true, "258369": true, "789456": true,
+	"123123": true, "696969": true, "101010": true, "159357": true, "123321": true,
+	"666666": true, "777777": true, "888888": true, "999999": true, "147852": true,
+	"12345678": true, "87654321": true, "11111111": true, "00000000": true,
+	"1234567890": true, "0987654321": true,
+}
+
+// PINPolicy validates and generates numeric PINs of 4–12 digits. PINs are
+// scored on their own curve with detectors tuned for short digit strings.
+type PINPolicy struct {
+	MinLength  int
+	MaxLength  int
+	Complexity int // minimum complexity score 0-100
+}
+
+// NewPINPolicy creates a PIN policy. Lengths are clamped to 4–12 digits and
+// complexity to 0-100.
+func NewPINPolicy(min, max, complexity int) *PINPolicy {
+	if min < MinPINLength {
+		min = MinPINLength
+	}
+	if max > MaxPINLength {
+		max = MaxPINLength
+	}
+	if max < min {
+		max = min
+	}
+	if complexity < 0 {
+		complexity = 0
+	}
+	if complexity > 100 {
+		complexity = 100
+	}
+	return &PINPolicy{
+		MinLength:  min,
+		MaxLength:  max,
+		Complexity: complexity,
+	}
+}
+
+// Validate returns whether the PIN passes the policy and its score (0-100).
+func (p *PINPolicy) Validate(pin string) (bool, int) {
+	pass, score, _ := p.validate(pin)
+	return pass, score
+}
+
+// ValidateVerbose returns pass/fail, the score, and a *ValidationError detailing
+// failed rules and applied penalties. error is nil only if the PIN passes.
+func (p *PINPolicy) ValidateVerbose(pin string) (bool, int, error) {
+	pass, score, vErr := p.validate(pin)
+	if pass {
+		return true, score, nil
+	}
+	return false, score, vErr
+}
+
+func (p *PINPolicy) validate(pin string) (bool, int, *ValidationError) {
+	vErr := &ValidationError{}
+
+	if len(pin) < p.MinLength {
+		vErr.fail(RuleTooShort, fmt.Sprintf("too short: minimum %d digits", p.MinLength), "min", p.MinLength, "actual", len(pin))
+	}
+	if len(pin) > p.MaxLength {
+		vErr.fail(RuleTooLong, fmt.Sprintf("too long: maximum %d digits", p.MaxLength), "max", p.MaxLength, "actual", len(pin))
+	}
+	if !isAllDigits(pin) {
+		vErr.fail(RulePINNotNumeric, "PIN must contain only digits")
+		return false, 0, vErr
+	}
+
+	entropy := float64(len(pin)) * math.Log2(10)
+	score := int(math.Round(100.0 * (1.0 - math.Exp(-entropy/pinCurveK))))
+
+	for _, pen := range detectPINPenalties(pin) {
+		score = int(float64(score) * pen.Factor)
+		vErr.Penalties = append(vErr.Penalties, pen)
+	}
+
+	rulesPass := len(vErr.RuleFails) == 0
+	complexityPass := score >= p.Complexity
+	if !complexityPass {
+		vErr.fail(RuleComplexity, fmt.Sprintf("complexity %d below threshold %d", score, p.Complexity), "min", p.Complexity, "actual", score)
+	}
+
+	return rulesPass && complexityPass, score, vErr
+}
+
+// GeneratePIN creates a random PIN of n digits that passes the policy.
+// It retries until a valid PIN is produced (max 1000 attempts).
+func (p *PINPolicy) GeneratePIN(n int) (string, error) {
+	if n < p.MinLength || n > p.MaxLength {
+		return "", fmt.Errorf("PIN length %d outside policy range %d-%d", n, p.MinLength, p.MaxLength)
+	}
+
+	const maxAttempts = 1000
+
+	for i := 0; i < maxAttempts; i++ {
+		pin := make([]byte, n)
+		for j := range pin {
+			d, err := randIndex(len(numberChars))
+			if err != nil {
+				return "", err
+			}
+			pin[j] = numberChars[d]
+		}
+		if pass, _ := p.Validate(string(pin)); pass {
+			return string(pin), nil
+		}
+	}
+	return "", fmt.Errorf("failed to generate a valid PIN after %d attempts", maxAttempts)
+}
+
+// detectPINPenalties returns the multiplicative penalties for a digit-only PIN.
+func detectPINPenalties(pin string) []PenaltyDetail {
+	var penalties []PenaltyDetail
+
+	if commonPINs[pin] {
+		penalties = append(penalties, PenaltyDetail{
+			Rule:   "common_pin",
+			Factor: 0.1,
+			Desc:   "PIN is in the common PINs list",
+		})
+	}
+
+	// Repeated digits: long runs and few distinct digits
+	maxRun, run := 1, 1
+	distinct := map[byte]bool{pin[0]: true}
+	for i := 1; i < len(pin); i++ {
+		distinct[pin[i]] = true
+		if pin[i] == pin[i-1] {
+			run++
+			if run > maxRun {
+				maxRun = run
+			}
+		} else {
+			run = 1
+		}
+	}
+	if maxRun >= 3 || len(distinct) <= len(pin)/3 {
+		penalties = append(penalties, PenaltyDetail{
+			Rule:   "repeated_digits",
+			Factor: 0.4,
+			Desc:   fmt.Sprintf("repeated digits (%d consecutive, %d distinct)", maxRun, len(distinct)),
+		})
+	} else if len(distinct) <= len(pin)/2 {
+		penalties = append(penalties, PenaltyDetail{
+			Rule:   "repeated_digits",
+			Factor: 0.7,
+			Desc:   fmt.Sprintf("few distinct digits (%d)", len(distinct)),
+		})
+	}
+
+	// Sequential digits: ascending or descending runs
+	maxSeq, seq := 1, 1
+	for i := 1; i < len(pin); i++ {
+		diff := int(pin[i]) - int(pin[i-1])
+		if diff == 1 || diff == -1 {
+			seq++
+			if seq > maxSeq {
+				maxSeq = seq
+			}
+		} else {
+			seq = 1
+		}
+	}
+	if maxSeq*4 >= len(pin)*3 {
+		penalties = append(penalties, PenaltyDetail{
+			Rule:   "sequential_digits",
+			Factor: 0.3,
+			Desc:   fmt.Sprintf("PIN is mostly a sequence (%d digits)", maxSeq),
+		})
+	} else if maxSeq >= 3 {
+		penalties = append(penalties, PenaltyDetail{
+			Rule:   "sequential_digits",
+			Factor: 0.7,
+			Desc:   fmt.Sprintf("sequential digits detected (%d digits)", maxSeq),
+		})
+	}
+
+	if shape := pinDateShape(pin); shape != "" {
+		penalties = append(penalties, PenaltyDetail{
+			Rule:   "date_pin",
+			Factor: 0.3,
+			Desc:   fmt.Sprintf("PIN looks like a %s", shape),
+		})
+	}
+
+	return withReferences(penalties)
+}
+
+// pinDateShape reports whether a PIN looks like a year or a date, returning a
+// short description of the shape or "" if it does not.
+func pinDateShape(pin string) string {
+	switch len(pin) {
+	case 4:
+		if isPlausibleYear(pin) {
+			return "year"
+		}
+		if isDayMonth(pin[:2], pin[2:]) || isDayMonth(pin[2:], pin[:2]) {
+			return "day and month"
+		}
+	case 6:
+		// DDMMYY, MMDDYY, YYMMDD
+		if isDayMonth(pin[:2], pin[2:4]) || isDayMonth(pin[2:4], pin[:2]) || isDayMonth(pin[4:], pin[2:4]) {
+			return "date"
+		}
+	case 8:
+		// DDMMYYYY, MMDDYYYY, YYYYMMDD
+		if isPlausibleYear(pin[4:]) && (isDayMonth(pin[:2], pin[2:4]) || isDayMonth(pin[2:4], pin[:2])) {
+			return "date"
+		}
+		if isPlausibleYear(pin[:4]) && isDayMonth(pin[6:], pin[4:6]) {
+			return "date"
+		}
+	}
+	return ""
+}
+
+// isPlausibleYear reports whether s is a 4-digit year between 1900 and 2099.
+func isPlausibleYear(s string) bool {
+	y, err := strconv.Atoi(s)
+	return err == nil && len(s) == 4 && y >= 1900 && y <= 2099
+}
+
+// isDayMonth reports whether day and month are 2-digit strings forming a valid day and month.
+func isDayMonth(day, month string) bool {
+	d, errD := strconv.Atoi(day)
+	m, errM := strconv.Atoi(month)
+	return errD == nil && errM == nil && d >= 1 && d <= 31 && m >= 1 && m <= 12
+}
+
+func isAllDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
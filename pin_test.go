@@ -0,0 +1,62 @@
+package passval
+
+import (
+	"testing"
+)
+
+func TestPINPolicy_Validate(t *testing.T) {
+	p := NewPINPolicy(4, 8, 50)
+
+	tests := []struct {
+		name     string
+		pin      string
+		wantPass bool
+	}{
+		{"common", "1234", false},
+		{"keypad column", "2580", false},
+		{"all same", "0000", false},
+		{"year", "1987", false},
+		{"date", "25121990", false},
+		{"sequence", "345678", false},
+		{"non-digit", "12a4", false},
+		{"too short", "739", false},
+		{"too long", "739184620", false},
+		{"random 4", "7392", true},
+		{"random 6", "739184", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pass, score, err := p.ValidateVerbose(tt.pin)
+			if pass != tt.wantPass {
+				t.Errorf("Validate(%q) = %v (score=%d, err=%v), want %v", tt.pin, pass, score, err, tt.wantPass)
+			}
+		})
+	}
+}
+
+func TestPINPolicy_Clamping(t *testing.T) {
+	p := NewPINPolicy(2, 20, 150)
+	if p.MinLength != MinPINLength || p.MaxLength != MaxPINLength || p.Complexity != 100 {
+		t.Errorf("unexpected clamping: %+v", p)
+	}
+}
+
+func TestGeneratePIN(t *testing.T) {
+	p := NewPINPolicy(4, 8, 50)
+
+	pin, err := p.GeneratePIN(6)
+	if err != nil {
+		t.Fatalf("GeneratePIN() error: %v", err)
+	}
+	if len(pin) != 6 || !isAllDigits(pin) {
+		t.Errorf("expected 6 digits, got %q", pin)
+	}
+	if pass, score := p.Validate(pin); !pass {
+		t.Errorf("generated PIN %q did not pass (score=%d)", pin, score)
+	}
+
+	if _, err := p.GeneratePIN(10); err == nil {
+		t.Error("expected error for length outside policy range")
+	}
+}
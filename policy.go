@@ -0,0 +1,214 @@
+package passval
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Policy is a serializable description of a validator's configuration, used to
+// describe, export and compare policies.
+type Policy struct {
+	MinLength      int  `json:"min_length"`
+	MaxLength      int  `json:"max_length"` // NoMax (0) = unlimited
+	RequireLower   bool `json:"require_lower"`
+	RequireUpper   bool `json:"require_upper"`
+	RequireNumbers bool `json:"require_numbers"`
+	RequireSymbols bool `json:"require_symbols"`
+	Complexity     int  `json:"complexity"`
+	WarnThreshold  int  `json:"warn_threshold,omitempty"`
+
+	StrengthLevels []StrengthLevel `json:"strength_levels,omitempty"` // unset = the scale of StrengthLabel
+
+	MinLower          int `json:"min_lower,omitempty"`
+	MinUpper          int `json:"min_upper,omitempty"`
+	MinDigits         int `json:"min_digits,omitempty"`
+	MinSymbols        int `json:"min_symbols,omitempty"`
+	MinCharClasses    int `json:"min_char_classes,omitempty"`
+	MinUniqueChars    int `json:"min_unique_chars,omitempty"`
+	ExemptLength      int `json:"exempt_length,omitempty"`
+	MaxBytes          int `json:"max_bytes,omitempty"`
+	MinChangeDistance int `json:"min_change_distance"`
+
+	AllowedSymbols       string   `json:"allowed_symbols,omitempty"`
+	DisallowedChars      string   `json:"disallowed_chars,omitempty"`
+	ASCIIOnly            bool     `json:"ascii_only,omitempty"`
+	PrintableOnly        bool     `json:"printable_only,omitempty"`
+	BannedSubstrings     []string `json:"banned_substrings,omitempty"`
+	SiteTerms            []string `json:"site_terms,omitempty"`
+	BannedPatterns       []string `json:"banned_patterns,omitempty"`
+	StructureRules       []string `json:"structure_rules,omitempty"`
+	Presets              []Preset `json:"presets,omitempty"`
+	LocaleHints          []string `json:"locale_hints,omitempty"`
+	Topics               []string `json:"topics,omitempty"`
+	EnglishWords         bool     `json:"english_words,omitempty"`
+	LanguageWords        int      `json:"language_words,omitempty"` // words added with WithLanguageWords
+	DisabledPenalties    []string `json:"disabled_penalties,omitempty"`
+	PassphraseMinWords   int      `json:"passphrase_min_words,omitempty"`
+	PassphraseMinWordLen int      `json:"passphrase_min_word_len,omitempty"`
+	WordlistSize         int      `json:"wordlist_size,omitempty"` // words of a WithWordlist list
+	MinGuesses           float64  `json:"min_guesses,omitempty"`
+	RandomTokenBits      float64  `json:"random_token_bits,omitempty"`
+	MaxAnalyzedRunes     int      `json:"max_analyzed_runes,omitempty"`
+	MaxDictScanWords     int      `json:"max_dict_scan_words,omitempty"`
+	DictMinMatchLen      int      `json:"dict_min_match_len,omitempty"` // 0 = 4
+	EntropyMode          string   `json:"entropy_mode"`
+	BreachCheck          bool     `json:"breach_check"`
+}
+
+// Policy returns a description of the validator's configuration.
+func (v *PasswordValidator) Policy() Policy {
+	p := Policy{
+		MinLength:            v.MinLength,
+		MaxLength:            v.MaxLength,
+		RequireLower:         v.RequireLower,
+		RequireUpper:         v.RequireUpper,
+		RequireNumbers:       v.RequireNumbers,
+		RequireSymbols:       v.RequireSymbols,
+		Complexity:           v.Complexity,
+		WarnThreshold:        v.WarnThreshold,
+		StrengthLevels:       slices.Clone(v.strengthLevels),
+		MinLower:             v.MinLower,
+		MinUpper:             v.MinUpper,
+		MinDigits:            v.MinDigits,
+		MinSymbols:           v.MinSymbols,
+		MinCharClasses:       v.minCharClasses,
+		MinUniqueChars:       v.minUnique,
+		ExemptLength:         v.exemptLength,
+		MaxBytes:             v.maxBytes,
+		MinChangeDistance:    v.changeDistance,
+		AllowedSymbols:       v.allowedSymbols,
+		DisallowedChars:      v.forbiddenChars,
+		ASCIIOnly:            v.asciiOnly,
+		PrintableOnly:        v.printableOnly,
+		PassphraseMinWords:   v.passphraseMinWords,
+		PassphraseMinWordLen: v.passphraseMinWordLen,
+		MinGuesses:           v.minGuesses,
+		RandomTokenBits:      v.tokenBits,
+		MaxAnalyzedRunes:     v.maxAnalyzed,
+		MaxDictScanWords:     v.maxDictWords,
+		DictMinMatchLen:      v.dictMinMatch,
+		EnglishWords:         v.englishWords,
+		LanguageWords:        len(v.languageWords),
+		LocaleHints:          slices.Clone(v.localeHints),
+		Presets:              slices.Clone(v.presets),
+		DisabledPenalties:    slices.Sorted(maps.Keys(v.skipPenalties)),
+		EntropyMode:          v.entropyMode.String(),
+		BreachCheck:          v.breachChecker != nil,
+	}
+	if v.wordlist != nil {
+		p.WordlistSize = v.wordlist.Len()
+	}
+	for _, t := range v.bannedTerms {
+		p.BannedSubstrings = append(p.BannedSubstrings, t.term)
+	}
+	for _, t := range v.siteTerms {
+		p.SiteTerms = append(p.SiteTerms, t.term)
+	}
+	for _, re := range v.bannedPatterns {
+		p.BannedPatterns = append(p.BannedPatterns, re.String())
+	}
+	for _, t := range v.topics {
+		p.Topics = append(p.Topics, string(t))
+	}
+	for _, rule := range v.structureRules {
+		p.StructureRules = append(p.StructureRules, rule.String())
+	}
+	return p
+}
+
+// ClientPolicy is a compact description of a policy for client-side hints,
+// e.g. to render a requirements checklist in a single-page app that mirrors
+// the server-side validator. Its requirements carry the Rule* codes the
+// server reports when they fail.
+type ClientPolicy struct {
+	MinLength        int                 `json:"min_length"`
+	MaxLength        int                 `json:"max_length"` // NoMax (0) = unlimited
+	MaxBytes         int                 `json:"max_bytes,omitempty"`
+	Requirements     []ClientRequirement `json:"requirements"`
+	ExemptLength     int                 `json:"exempt_length,omitempty"` // number, symbol and class requirements are waived from this length
+	AllowedSymbols   string              `json:"allowed_symbols,omitempty"`
+	DisallowedChars  string              `json:"disallowed_chars,omitempty"`
+	ASCIIOnly        bool                `json:"ascii_only,omitempty"`
+	PrintableOnly    bool                `json:"printable_only,omitempty"`
+	BannedSubstrings []string            `json:"banned_substrings,omitempty"`
+	SiteTerms        []string            `json:"site_terms,omitempty"`
+	BannedPatterns   []string            `json:"banned_patterns,omitempty"` // RE2 syntax
+	MinScore         int                 `json:"min_score"`
+	StrengthLevels   []StrengthLevel     `json:"strength_levels,omitempty"` // unset = very_weak, weak, fair, strong, very_strong from 0, 20, 40, 60, 80
+}
+
+// ClientRequirement is one checkable item of a ClientPolicy.
+type ClientRequirement struct {
+	Code    string `json:"code"` // the Rule* code reported when the requirement fails
+	Min     int    `json:"min"`
+	Message string `json:"message"`
+}
+
+// ClientPolicy returns the client-side description of the validator's policy.
+func (v *PasswordValidator) ClientPolicy() ClientPolicy {
+	p := ClientPolicy{
+		MinLength:       v.MinLength,
+		MaxLength:       v.MaxLength,
+		MaxBytes:        v.maxBytes,
+		ExemptLength:    v.exemptLength,
+		AllowedSymbols:  v.allowedSymbols,
+		DisallowedChars: v.forbiddenChars,
+		ASCIIOnly:       v.asciiOnly,
+		PrintableOnly:   v.printableOnly,
+		MinScore:        v.Complexity,
+		StrengthLevels:  slices.Clone(v.strengthLevels),
+		Requirements: []ClientRequirement{{
+			Code:    RuleTooShort,
+			Min:     v.MinLength,
+			Message: fmt.Sprintf("at least %d characters", v.MinLength),
+		}},
+	}
+	for _, c := range []struct {
+		rule     classRule
+		min      int
+		required bool
+	}{
+		{lowerClassRule, v.MinLower, v.RequireLower},
+		{upperClassRule, v.MinUpper, v.RequireUpper},
+		{numberClassRule, v.MinDigits, v.RequireNumbers},
+		{symbolClassRule, v.MinSymbols, v.RequireSymbols},
+	} {
+		if r, ok := c.rule.requirement(c.min, c.required); ok {
+			p.Requirements = append(p.Requirements, r)
+		}
+	}
+	if v.minCharClasses > 0 {
+		p.Requirements = append(p.Requirements, ClientRequirement{
+			Code:    RuleMinCharClasses,
+			Min:     v.minCharClasses,
+			Message: fmt.Sprintf("at least %d of lowercase, uppercase, numbers and symbols", v.minCharClasses),
+		})
+	}
+	if v.minUnique > 0 {
+		p.Requirements = append(p.Requirements, ClientRequirement{
+			Code:    RuleMinUniqueChars,
+			Min:     v.minUnique,
+			Message: fmt.Sprintf("at least %d different characters", v.minUnique),
+		})
+	}
+	for _, rule := range v.structureRules {
+		p.Requirements = append(p.Requirements, ClientRequirement{Code: RuleStructure, Message: rule.String()})
+	}
+	for _, t := range v.bannedTerms {
+		p.BannedSubstrings = append(p.BannedSubstrings, t.term)
+	}
+	for _, t := range v.siteTerms {
+		p.SiteTerms = append(p.SiteTerms, t.term)
+	}
+	for _, re := range v.bannedPatterns {
+		p.BannedPatterns = append(p.BannedPatterns, re.String())
+	}
+	return p
+}
+
+// ExportClientPolicy returns the ClientPolicy of the validator as compact JSON.
+func (v *PasswordValidator) ExportClientPolicy() ([]byte, error) {
+	return json.Marshal(v.ClientPolicy())
+}
@@ -0,0 +1,130 @@
+package passval
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Preset names the password constraints of a backend system, for validating
+// and generating passwords of service accounts that the system must accept.
+type Preset string
+
+const (
+	// PresetLDAP is for LDAP directories (OpenLDAP, 389 Directory Server,
+	// Active Directory) provisioned through LDIF and command-line tools:
+	// printable ASCII, at most 128 characters, and no leading space, colon or
+	// less-than sign or trailing space, which LDIF values cannot carry
+	// unencoded.
+	PresetLDAP Preset = "ldap"
+	// PresetKerberos is for Kerberos principals (MIT, Heimdal, Active
+	// Directory KDCs): printable ASCII, since clients derive keys from
+	// non-ASCII passwords inconsistently, at most 127 characters, the Windows
+	// logon limit, and no leading or trailing space.
+	PresetKerberos Preset = "kerberos"
+	// PresetSAP is for SAP NetWeaver ABAP users: printable ASCII, at most 40
+	// characters, not starting with a question mark, exclamation mark or
+	// space, and not starting with three identical characters.
+	PresetSAP Preset = "sap"
+	// PresetOracleDB is for Oracle Database users created with unquoted
+	// passwords (CREATE USER ... IDENTIFIED BY): at most 30 characters,
+	// letters, digits and the symbols _ $ # only, starting with a letter.
+	PresetOracleDB Preset = "oracle"
+)
+
+// presetConstraints are the constraints a Preset applies.
+type presetConstraints struct {
+	maxLength int
+	symbols   string // allowed symbols, or "" for all
+	rules     []StructureRule
+}
+
+var presets = map[Preset]presetConstraints{
+	PresetLDAP: {
+		maxLength: 128,
+		rules:     []StructureRule{NoLeadingChars(" :<"), NoTrailingChars(" ")},
+	},
+	PresetKerberos: {
+		maxLength: 127,
+		rules:     []StructureRule{NoLeadingChars(" "), NoTrailingChars(" ")},
+	},
+	PresetSAP: {
+		maxLength: 40,
+		rules: []StructureRule{NoLeadingChars("?! "), {
+			desc: "first three characters must not be identical",
+			check: func(runes []rune) bool {
+				return len(runes) < 3 || runes[0] != runes[1] || runes[1] != runes[2]
+			},
+		}},
+	},
+	PresetOracleDB: {
+		maxLength: 30,
+		symbols:   "_$#",
+		rules:     []StructureRule{FirstChar(ClassLetter)},
+	},
+}
+
+// Presets returns the known presets, sorted by name.
+func Presets() []Preset {
+	names := make([]Preset, 0, len(presets))
+	for p := range presets {
+		names = append(names, p)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// WithPreset applies the constraints of the backend p on top of the policy:
+// MaxLength is lowered to the backend's limit, only printable ASCII is
+// accepted, symbols are narrowed to those the backend allows, and its rules
+// on leading and trailing characters are added as structure rules, so that
+// Generate produces passwords the backend accepts. The length, class and
+// complexity settings of the validator are kept; choose a MinLength within
+// the backend's limit. NewValidatorStrict reports an unknown preset.
+func WithPreset(p Preset) Option {
+	return func(v *PasswordValidator) {
+		v.presets = append(v.presets, p)
+		c, ok := presets[p]
+		if !ok {
+			return
+		}
+		if v.MaxLength == NoMax || v.MaxLength > c.maxLength {
+			v.MaxLength = c.maxLength
+		}
+		v.asciiOnly, v.printableOnly = true, true
+		switch {
+		case c.symbols == "":
+		case v.allowedSymbols == "":
+			v.allowedSymbols = c.symbols
+		default:
+			// With none of the caller's symbols allowed, fall back to the
+			// backend's rather than lifting the restriction.
+			if v.allowedSymbols = keepChars(v.allowedSymbols, c.symbols); v.allowedSymbols == "" {
+				v.allowedSymbols = c.symbols
+			}
+		}
+		v.structureRules = append(v.structureRules, c.rules...)
+	}
+}
+
+// presetProblems describes the unknown presets among ps.
+func presetProblems(ps []Preset) []string {
+	var problems []string
+	for _, p := range ps {
+		if _, ok := presets[p]; !ok {
+			problems = append(problems, fmt.Sprintf("unknown preset %q", p))
+		}
+	}
+	return problems
+}
+
+// keepChars returns the characters of s that are in keep.
+func keepChars(s, keep string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(keep, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,67 @@
+package passval
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPresets(t *testing.T) {
+	tests := []struct {
+		preset Preset
+		max    int
+		reject []string
+	}{
+		{PresetLDAP, 128, []string{":Xk9$mP2!vLq", "<Xk9$mP2!vLq", " Xk9$mP2!vLq", "Xk9$mP2!vLq ", "Xk9$mP2!vLqé"}},
+		{PresetKerberos, 127, []string{" Xk9$mP2!vLq", "Xk9$mP2!vLq "}},
+		{PresetSAP, 40, []string{"?Xk9$mP2!vLq", "!Xk9$mP2!vLq", "XXXk9$mP2!vLq"}},
+		{PresetOracleDB, 30, []string{"9Xk_mP2#vLqz", "Xk9!mP2#vLqz", "Xk9_mP2#vLqzXk9_mP2#vLqzXk9_mP2#"}},
+	}
+	for _, tt := range tests {
+		v := NewPasswordValidator(12, NoMax, true, true, true, true, 40, WithPreset(tt.preset))
+		if v.MaxLength != tt.max {
+			t.Errorf("%s: MaxLength = %d, want %d", tt.preset, v.MaxLength, tt.max)
+		}
+		for _, pw := range tt.reject {
+			if r := v.ValidateResult(pw); r.Pass {
+				t.Errorf("%s: %q passed, want it rejected", tt.preset, pw)
+			}
+		}
+		for i := 0; i < 50; i++ {
+			pw, err := v.Generate()
+			if err != nil {
+				t.Fatalf("%s: Generate() error: %v", tt.preset, err)
+			}
+			if r := v.ValidateResult(pw); !r.Pass {
+				t.Fatalf("%s: generated %q fails: %v", tt.preset, pw, r.FailMessages())
+			}
+		}
+		if !slices.Equal(v.Policy().Presets, []Preset{tt.preset}) {
+			t.Errorf("%s: Policy().Presets = %v", tt.preset, v.Policy().Presets)
+		}
+	}
+}
+
+func TestPresetOracleSymbols(t *testing.T) {
+	v := NewPasswordValidator(12, 64, true, true, true, true, 40, WithAllowedSymbols("#!"), WithPreset(PresetOracleDB))
+	if v.allowedSymbols != "#" {
+		t.Errorf("allowed symbols = %q, want the intersection \"#\"", v.allowedSymbols)
+	}
+	v = NewPasswordValidator(12, 64, true, true, true, true, 40, WithAllowedSymbols("!"), WithPreset(PresetOracleDB))
+	if v.allowedSymbols != "_$#" {
+		t.Errorf("allowed symbols = %q, want Oracle's when none of the caller's are allowed", v.allowedSymbols)
+	}
+}
+
+func TestPresetStrict(t *testing.T) {
+	if _, err := NewValidatorStrict(12, 64, true, true, true, true, 40, WithPreset("db2")); !errors.Is(err, ErrInvalidPolicy) || !strings.Contains(err.Error(), `unknown preset "db2"`) {
+		t.Errorf("unknown preset: err = %v", err)
+	}
+	if _, err := NewValidatorStrict(32, 64, true, true, true, true, 40, WithPreset(PresetOracleDB)); !errors.Is(err, ErrInvalidPolicy) || !strings.Contains(err.Error(), "above maximum length 30") {
+		t.Errorf("minimum above the preset's maximum: err = %v", err)
+	}
+	if got := Presets(); !slices.Equal(got, []Preset{PresetKerberos, PresetLDAP, PresetOracleDB, PresetSAP}) {
+		t.Errorf("Presets() = %v", got)
+	}
+}
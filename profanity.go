@@ -0,0 +1,67 @@
+package passval
+
+import (
+	_ "embed"
+	"sort"
+	"strings"
+)
+
+//go:embed data/profanity.txt
+var profanityData string
+
+// defaultProfanity is the embedded list generated passwords and passphrases
+// are screened against.
+var defaultProfanity []bannedTerm
+
+func init() {
+	defaultProfanity = newBannedTerms(strings.Split(profanityData, "\n"))
+}
+
+// WithProfanityList replaces the embedded list of offensive substrings that
+// Generate screens its output against. Matching is case-insensitive and
+// leet-aware. Call it with no words to disable screening.
+func WithProfanityList(words ...string) Option {
+	return func(v *PasswordValidator) {
+		v.profanity = newBannedTerms(words)
+	}
+}
+
+// WithPassphraseProfanityList replaces the embedded list of offensive
+// substrings GeneratePassphrase screens its output against. Call it with no
+// words to disable screening.
+func WithPassphraseProfanityList(words ...string) PassphraseOption {
+	return func(c *passphraseConfig) {
+		c.profanity = newBannedTerms(words)
+	}
+}
+
+// offensivePassphrase reports whether the words of a passphrase spell one of
+// terms, either as a whole word or across a word boundary. Terms inside a
+// longer word ("grass", "shell") are legitimate and allowed.
+func offensivePassphrase(parts []string, terms []bannedTerm) bool {
+	joined := strings.ToLower(strings.Join(parts, ""))
+	starts := make([]int, len(parts)) // offset of each word in joined
+	off := 0
+	for i, p := range parts {
+		starts[i] = off
+		off += len(p)
+	}
+
+	for _, t := range terms {
+		for from := 0; ; {
+			i := strings.Index(joined[from:], t.term)
+			if i < 0 {
+				break
+			}
+			start := from + i
+			end := start + len(t.term)
+			w := sort.SearchInts(starts, start+1) - 1 // word containing start
+			wordEnd := starts[w] + len(parts[w])
+			if end > wordEnd || (start == starts[w] && end == wordEnd) {
+				return true
+			}
+			from = start + 1
+		}
+	}
+	return false
+}
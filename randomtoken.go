@@ -0,0 +1,61 @@
+package passval
+
+// minHexToken is the shortest run of hex digits recognized as a random token.
+const minHexToken = 16
+
+// WithRandomTokenExemption waives the composition rules (required and
+// minimum counts of each character class, and WithMinCharClasses) for
+// passwords pasted from generators, UUIDs and blobs of 16 or more hex digits,
+// whose measured randomness is at least minEntropyBits. Randomness is the Shannon entropy of
+// the hex digits, about 115 bits for a random UUID or 32 hex digits, so a
+// repetitive "deadbeefdeadbeef" does not qualify at 64 bits. Qualifying
+// tokens are scored on that measured randomness, without the penalties for
+// patterns that occur in hex digits by chance; length, character
+// restrictions and banned terms still apply.
+func WithRandomTokenExemption(minEntropyBits float64) Option {
+	return func(v *PasswordValidator) {
+		v.tokenBits = minEntropyBits
+	}
+}
+
+// randomTokenBits returns the measured randomness of password in bits if it
+// is a UUID or a hex blob in one letter case, and whether it is one.
+func randomTokenBits(password string) (bits float64, ok bool) {
+	hex := password
+	if isUUID(password) {
+		hex = password[:8] + password[9:13] + password[14:18] + password[19:23] + password[24:]
+	}
+	if len(hex) < minHexToken {
+		return 0, false
+	}
+	var lower, upper bool
+	for i := 0; i < len(hex); i++ {
+		switch c := hex[i]; {
+		case '0' <= c && c <= '9':
+		case 'a' <= c && c <= 'f':
+			lower = true
+		case 'A' <= c && c <= 'F':
+			upper = true
+		default:
+			return 0, false
+		}
+	}
+	if lower && upper {
+		return 0, false
+	}
+	return calculateShannonEntropy(hex), true
+}
+
+// isUUID reports whether s has the 8-4-4-4-12 layout of a UUID. The groups
+// are checked as hex by randomTokenBits.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for _, i := range []int{8, 13, 18, 23} {
+		if s[i] != '-' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,44 @@
+package passval
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRandomTokenExemption(t *testing.T) {
+	v := NewPasswordValidator(12, 64, true, true, true, true, 50, WithRandomTokenExemption(64))
+	strict := NewPasswordValidator(12, 64, true, true, true, true, 50)
+
+	for _, tc := range []struct {
+		pwd    string
+		exempt bool
+	}{
+		{"f47ac10b-58cc-4372-a567-0e02b2c3d479", true},
+		{"9F86D081884C7D659A2FEAA0C55AD015", true},
+		{"3b5d3c7d207e37dceeedd301e35e2e58", true},
+		{"deadbeefdeadbeef", false},         // hex, but too repetitive
+		{"9F86d081884C7D659A2FEAA0", false}, // mixed case is not a generator's output
+		{"f47ac10b58cc", false},             // too short
+	} {
+		r := v.ValidateResult(tc.pwd)
+		composition := slices.ContainsFunc(r.Codes(), func(c string) bool {
+			return c == RuleMissingUpper || c == RuleMissingLower || c == RuleMissingSymbol
+		})
+		if composition == tc.exempt {
+			t.Errorf("%q: Codes() = %v, want exempt %v", tc.pwd, r.Codes(), tc.exempt)
+		}
+		if !tc.exempt {
+			continue
+		}
+		if r.Score < 50 || !r.Pass {
+			t.Errorf("%q: ValidateResult = %+v, want a pass", tc.pwd, r)
+		}
+		if sr := strict.ValidateResult(tc.pwd); sr.Pass {
+			t.Errorf("%q: passes without the exemption", tc.pwd)
+		}
+	}
+
+	if p := v.Policy(); p.RandomTokenBits != 64 {
+		t.Errorf("Policy().RandomTokenBits = %v, want 64", p.RandomTokenBits)
+	}
+}
@@ -0,0 +1,112 @@
+package passval
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// AuditRecord is the per-password line of an audit report.
+type AuditRecord struct {
+	Index    int      `json:"index"`              // 0-based position among the audited passwords
+	Hash     string   `json:"hash,omitempty"`     // HMAC-SHA256 of the password, with ReportWriter.HashKey
+	Password string   `json:"password,omitempty"` // only with ReportWriter.Plaintext
+	Pass     bool     `json:"pass"`
+	Score    int      `json:"score"`
+	Codes    []string `json:"codes"`
+}
+
+// ReportWriter serializes per-password audit results as CSV or JSON Lines,
+// for loading into BI tools. By default a record identifies its password by
+// index only; set HashKey to join records on a keyed hash and Plaintext to
+// include the password itself. Set the fields before the first Write.
+type ReportWriter struct {
+	// HashKey, if set, adds the hex HMAC-SHA256 of each password under this
+	// key. A key keeps the hashes from being cracked like plain SHA-256
+	// without it; use the same key to compare reports.
+	HashKey []byte
+	// Plaintext includes each password in its record.
+	Plaintext bool
+
+	w      *bufio.Writer
+	csv    *csv.Writer // nil for JSON Lines
+	header bool        // the CSV header has been written
+}
+
+// NewCSVReportWriter returns a writer of CSV records with a header line:
+// index, hash and password (if enabled), pass, score and codes, the reason
+// codes separated by semicolons.
+func NewCSVReportWriter(w io.Writer) *ReportWriter {
+	bw := bufio.NewWriter(w)
+	return &ReportWriter{w: bw, csv: csv.NewWriter(bw)}
+}
+
+// NewJSONLReportWriter returns a writer of one AuditRecord JSON object per line.
+func NewJSONLReportWriter(w io.Writer) *ReportWriter {
+	return &ReportWriter{w: bufio.NewWriter(w)}
+}
+
+// Write writes the record of the password at index with its result.
+func (rw *ReportWriter) Write(index int, password string, r *Result) error {
+	rec := AuditRecord{Index: index, Pass: r.Pass, Score: r.Score, Codes: r.Codes()}
+	if rec.Codes == nil {
+		rec.Codes = []string{}
+	}
+	if rw.HashKey != nil {
+		mac := hmac.New(sha256.New, rw.HashKey)
+		mac.Write([]byte(password))
+		rec.Hash = hex.EncodeToString(mac.Sum(nil))
+	}
+	if rw.Plaintext {
+		rec.Password = password
+	}
+
+	if rw.csv == nil {
+		b, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		b = append(b, '\n')
+		_, err = rw.w.Write(b)
+		return err
+	}
+
+	if !rw.header {
+		rw.header = true
+		if err := rw.csv.Write(rw.columns("index", "hash", "password", "pass", "score", "codes")); err != nil {
+			return err
+		}
+	}
+	return rw.csv.Write(rw.columns(strconv.Itoa(rec.Index), rec.Hash, rec.Password,
+		strconv.FormatBool(rec.Pass), strconv.Itoa(rec.Score), strings.Join(rec.Codes, ";")))
+}
+
+// columns returns the CSV fields of a row given in full column order,
+// leaving out the hash and password columns when they are not enabled.
+func (rw *ReportWriter) columns(index, hash, password, pass, score, codes string) []string {
+	row := []string{index}
+	if rw.HashKey != nil {
+		row = append(row, hash)
+	}
+	if rw.Plaintext {
+		row = append(row, password)
+	}
+	return append(row, pass, score, codes)
+}
+
+// Flush writes any buffered records to the underlying writer.
+func (rw *ReportWriter) Flush() error {
+	if rw.csv != nil {
+		rw.csv.Flush()
+		if err := rw.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return rw.w.Flush()
+}
@@ -0,0 +1,79 @@
+package passval
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestReportWriterCSV(t *testing.T) {
+	a := NewAuditor(NewPasswordValidator(8, 64, true, true, true, true, 50))
+	var buf bytes.Buffer
+	rw := NewCSVReportWriter(&buf)
+	rw.HashKey = []byte("audit-2024")
+
+	report, err := a.AuditTo(strings.NewReader("password\n\nXq7#zR2mK9w!\n"), rw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Total != 2 {
+		t.Errorf("Total = %d, want 2", report.Total)
+	}
+	if strings.Contains(buf.String(), "password,") || strings.Contains(buf.String(), "Xq7#") {
+		t.Errorf("report contains plaintext:\n%s", buf.String())
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"index", "hash", "pass", "score", "codes"}; strings.Join(rows[0], ",") != strings.Join(want, ",") {
+		t.Errorf("header = %v, want %v", rows[0], want)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want a header and 2 records", len(rows))
+	}
+	if rows[1][0] != "0" || len(rows[1][1]) != 64 || rows[1][2] != "false" || !strings.Contains(rows[1][4], "common_password") {
+		t.Errorf("record 0 = %v, want index 0, a hash, a failure and common_password", rows[1])
+	}
+	if rows[2][0] != "1" || rows[2][2] != "true" {
+		t.Errorf("record 1 = %v, want index 1 passing", rows[2])
+	}
+}
+
+func TestReportWriterJSONL(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 50)
+	var buf bytes.Buffer
+	rw := NewJSONLReportWriter(&buf)
+	rw.Plaintext = true
+	for i, pw := range []string{"password", "Xq7#zR2mK9w!"} {
+		if err := rw.Write(i, pw, v.ValidateResult(pw)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	var recs []AuditRecord
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		var rec AuditRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			t.Fatalf("line %q: %v", sc.Text(), err)
+		}
+		recs = append(recs, rec)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("got %d records, want 2", len(recs))
+	}
+	if recs[0].Password != "password" || recs[0].Hash != "" || recs[0].Pass || len(recs[0].Codes) == 0 {
+		t.Errorf("record 0 = %+v, want the plaintext, no hash and failing codes", recs[0])
+	}
+	if recs[1].Index != 1 || !recs[1].Pass || recs[1].Codes == nil {
+		t.Errorf("record 1 = %+v, want index 1 passing with empty codes", recs[1])
+	}
+}
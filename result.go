@@ -0,0 +1,183 @@
+package passval
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Warning codes identify soft findings that do not fail the policy.
+const (
+	WarnNearMinLength  = "near_min_length"
+	WarnBelowThreshold = "below_warn_threshold"
+	WarnDictionaryWord = "dictionary_word"
+	WarnCommonPassword = "common_password"
+	WarnSingleClass    = "single_class"
+	WarnBreachSkipped  = "breach_check_skipped"
+	WarnPartialScan    = "partial_penalty_scan"
+	WarnTruncated      = "analysis_truncated"
+	WarnConfusable     = "confusable_characters"
+)
+
+// nearMinLengthMargin is how many characters above MinLength still warn.
+const nearMinLengthMargin = 2
+
+// Strength labels describe a score on a five-step scale, weakest first.
+const (
+	StrengthVeryWeak   = "very_weak"
+	StrengthWeak       = "weak"
+	StrengthFair       = "fair"
+	StrengthStrong     = "strong"
+	StrengthVeryStrong = "very_strong"
+)
+
+// StrengthLabel maps a 0-100 score to a strength label for display in meters.
+// Scores below 20 are very weak, then each step of 20 is one label stronger.
+// Validators may use their own scale, see WithStrengthLevels.
+func StrengthLabel(score int) string {
+	return strengthLabel(defaultStrengthLevels, score)
+}
+
+// Warning is a finding that does not fail the policy but should be surfaced,
+// e.g. to nudge users whose passwords barely pass.
+type Warning struct {
+	Code    string `json:"code"` // a Warn* constant, or the Rule* code of a rule demoted by WithWarnOnly
+	Message string `json:"message"`
+}
+
+// Result is the full outcome of validating a password.
+type Result struct {
+	Pass      bool
+	Score     int     // complexity score 0-100 after penalties
+	Strength  string  // label of Score on the validator's strength scale
+	Entropy   float64 // raw entropy bits before penalties
+	RuleFails []RuleFail
+	Penalties []PenaltyDetail
+	Warnings  []Warning
+	Segments  []Segment   // guess estimates per part, with WithSegmentEstimates
+	Debug     *DebugTrace // matches and candidate penalties, with WithDebugTrace
+
+	err *ValidationError
+}
+
+// Err returns the *ValidationError describing failed rules and applied penalties,
+// or nil if the password passed.
+func (r *Result) Err() error {
+	if r.Pass {
+		return nil
+	}
+	return r.validationError()
+}
+
+// Codes returns the reason codes of the failed rules followed by the
+// identifiers of the applied penalties.
+func (r *Result) Codes() []string {
+	return r.validationError().Codes()
+}
+
+// FailMessages returns the messages of the failed rules, for outputs that
+// carry plain descriptions.
+func (r *Result) FailMessages() []string {
+	msgs := make([]string, len(r.RuleFails))
+	for i, f := range r.RuleFails {
+		msgs[i] = f.Message
+	}
+	return msgs
+}
+
+// validationError returns the error recorded during validation, or one built
+// from the exported fields for results constructed outside this package (e.g.
+// by a mock Validator).
+func (r *Result) validationError() *ValidationError {
+	if r.err != nil {
+		return r.err
+	}
+	return &ValidationError{RuleFails: r.RuleFails, Penalties: r.Penalties}
+}
+
+// WithWarnThreshold sets a score below which passing passwords produce a warning,
+// distinct from the Complexity threshold that fails them.
+func WithWarnThreshold(score int) Option {
+	return func(v *PasswordValidator) {
+		v.WarnThreshold = score
+	}
+}
+
+// WithWarnOnly turns the given rules (Rule* codes, e.g. RuleTooManyBytes or
+// RuleDisallowedSymbol) into warnings: they are reported in Result.Warnings
+// instead of failing validation.
+func WithWarnOnly(codes ...string) Option {
+	return func(v *PasswordValidator) {
+		if v.warnOnly == nil {
+			v.warnOnly = make(map[string]bool, len(codes))
+		}
+		for _, c := range codes {
+			v.warnOnly[c] = true
+		}
+	}
+}
+
+// demoteRuleFails removes rule failures configured as warn-only from vErr and
+// returns them as warnings.
+func (v *PasswordValidator) demoteRuleFails(vErr *ValidationError) []Warning {
+	if len(v.warnOnly) == 0 {
+		return nil
+	}
+
+	var warnings []Warning
+	fails := vErr.RuleFails[:0]
+	for _, f := range vErr.RuleFails {
+		if v.warnOnly[f.Code] {
+			warnings = append(warnings, Warning{Code: f.Code, Message: f.Message})
+			continue
+		}
+		fails = append(fails, f)
+	}
+	vErr.RuleFails = fails
+	return warnings
+}
+
+// addRuleFail records a rule failure found after the local checks (e.g. by an
+// external check), honouring WithWarnOnly.
+func (v *PasswordValidator) addRuleFail(r *Result, f RuleFail) {
+	if v.warnOnly[f.Code] {
+		r.Warnings = append(r.Warnings, Warning{Code: f.Code, Message: f.Message})
+		return
+	}
+	r.err.RuleFails = append(r.err.RuleFails, f)
+	r.RuleFails = r.err.RuleFails
+	r.Pass = false
+}
+
+// warnings returns the soft findings for a validated password.
+func (v *PasswordValidator) warnings(password string, length int, r *Result) []Warning {
+	var warnings []Warning
+
+	if length >= v.MinLength && length < v.MinLength+nearMinLengthMargin {
+		warnings = append(warnings, Warning{
+			Code:    WarnNearMinLength,
+			Message: fmt.Sprintf("close to minimum length (%d of %d characters)", length, v.MinLength),
+		})
+	}
+	if r.Pass && r.Score < v.WarnThreshold {
+		warnings = append(warnings, Warning{
+			Code:    WarnBelowThreshold,
+			Message: fmt.Sprintf("score %d is below the recommended %d", r.Score, v.WarnThreshold),
+		})
+	}
+	lower, upper, number, symbol := charClassCounts(password)
+	if class := singleClass(utf8.RuneCountInString(password), lower, upper, number, symbol); class != "" {
+		warnings = append(warnings, Warning{Code: WarnSingleClass, Message: "uses only " + class})
+	}
+	for _, p := range r.Penalties {
+		switch {
+		case strings.HasPrefix(p.Rule, "common_password"):
+			warnings = append(warnings, Warning{Code: WarnCommonPassword, Message: "is a common password"})
+		case p.Rule == "dictionary_substring":
+			warnings = append(warnings, Warning{Code: WarnDictionaryWord, Message: "contains a dictionary word"})
+		case p.Rule == "dictionary_concatenation":
+			warnings = append(warnings, Warning{Code: WarnDictionaryWord, Message: "is made of dictionary words"})
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,57 @@
+package passval
+
+import (
+	"strings"
+	"testing"
+)
+
+func hasWarning(r *Result, code string) bool {
+	for _, w := range r.Warnings {
+		if w.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func TestValidateResult_Warnings(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 20, WithWarnThreshold(90))
+
+	r := v.ValidateResult("Xk9$mP2!")
+	if !r.Pass {
+		t.Fatalf("expected pass, got %v", r.Err())
+	}
+	if !hasWarning(r, WarnNearMinLength) {
+		t.Errorf("expected %s warning, got %v", WarnNearMinLength, r.Warnings)
+	}
+	if !hasWarning(r, WarnBelowThreshold) {
+		t.Errorf("expected %s warning for score %d, got %v", WarnBelowThreshold, r.Score, r.Warnings)
+	}
+
+	r = v.ValidateResult("Xk9$mP2!vLq#7wZ@")
+	if len(r.Warnings) != 0 {
+		t.Errorf("expected no warnings for strong password, got %v", r.Warnings)
+	}
+	if r.Err() != nil {
+		t.Errorf("expected nil error on pass, got %v", r.Err())
+	}
+}
+
+func TestWithWarnOnly(t *testing.T) {
+	long := strings.Repeat("xK9$", 20)
+
+	strict := NewPasswordValidator(8, 128, false, false, false, false, 0, WithMaxBytes(BcryptMaxBytes))
+	if r := strict.ValidateResult(long); r.Pass {
+		t.Error("80-byte password should fail the byte limit")
+	}
+
+	lenient := NewPasswordValidator(8, 128, false, false, false, false, 0,
+		WithMaxBytes(BcryptMaxBytes), WithWarnOnly(RuleTooManyBytes))
+	r := lenient.ValidateResult(long)
+	if !r.Pass {
+		t.Errorf("demoted rule should not fail validation: %v", r.Err())
+	}
+	if !hasWarning(r, RuleTooManyBytes) {
+		t.Errorf("expected %s warning, got %v", RuleTooManyBytes, r.Warnings)
+	}
+}
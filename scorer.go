@@ -0,0 +1,63 @@
+package passval
+
+import "strings"
+
+// maxScorerResults bounds the results a Scorer keeps, one per length of the
+// text being typed.
+const maxScorerResults = 128
+
+// Scorer scores a password as it is typed, for live strength meters. Each
+// Update scores the current text; the results of its prefixes are kept, so
+// deleting characters, or retyping ones just deleted, costs a map lookup
+// rather than a new analysis. A Scorer is not safe for concurrent use: give
+// each session its own. Keystrokes are not reported to Metrics or an
+// EventSink; validate the final password with ValidateResult.
+type Scorer struct {
+	p       *CompiledPolicy
+	text    string
+	results map[string]*Result
+}
+
+// NewScorer returns a Scorer for the validator's policy, compiled once so
+// that every keystroke runs on prepared structures. Later changes to v, such
+// as SetDictionary, do not affect it.
+func (v *PasswordValidator) NewScorer() *Scorer {
+	return &Scorer{p: v.Compile(), results: make(map[string]*Result)}
+}
+
+// Update scores text, the full current contents of the password field, and
+// returns its result. The Result is shared with later calls for the same
+// text and must not be modified.
+func (s *Scorer) Update(text string) *Result {
+	if r, ok := s.results[text]; ok {
+		s.text = text
+		return r
+	}
+	// Keep only the results of the prefixes of text: those of text the user
+	// has moved away from (a pasted or edited middle) will not recur.
+	switch {
+	case len(s.results) >= maxScorerResults:
+		clear(s.results)
+	case !strings.HasPrefix(text, s.text):
+		for k := range s.results {
+			if !strings.HasPrefix(text, k) {
+				delete(s.results, k)
+			}
+		}
+	}
+	r := s.p.v.validate(text)
+	s.results[text], s.text = r, text
+	return r
+}
+
+// Score is Update returning only the score.
+func (s *Scorer) Score(text string) int {
+	return s.Update(text).Score
+}
+
+// Reset forgets the scored texts, e.g. when the field is cleared or the
+// session ends.
+func (s *Scorer) Reset() {
+	clear(s.results)
+	s.text = ""
+}
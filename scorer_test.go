@@ -0,0 +1,52 @@
+package passval
+
+import "testing"
+
+func TestScorer(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 50)
+	s := v.NewScorer()
+
+	typed := "Xk9$mP2!vLq"
+	var results []*Result
+	for i := 1; i <= len(typed); i++ {
+		r := s.Update(typed[:i])
+		if want := v.ValidateResult(typed[:i]); r.Score != want.Score || r.Pass != want.Pass {
+			t.Fatalf("Update(%q) = %d %v, want %d %v", typed[:i], r.Score, r.Pass, want.Score, want.Pass)
+		}
+		results = append(results, r)
+	}
+
+	// Backspacing returns the results already computed.
+	for i := len(typed) - 1; i >= 1; i-- {
+		if r := s.Update(typed[:i]); r != results[i-1] {
+			t.Fatalf("Update(%q) after backspace recomputed the result", typed[:i])
+		}
+	}
+
+	// Editing the middle drops the results that are no longer prefixes.
+	s.Update("Xk9$mP2!vLq")
+	s.Update("Xk9#")
+	if len(s.results) != 4 {
+		t.Errorf("kept %d results after an edit, want 4 (X, Xk, Xk9, Xk9#)", len(s.results))
+	}
+	if got := s.Score(""); got != 0 {
+		t.Errorf("Score(\"\") = %d, want 0", got)
+	}
+
+	s.Reset()
+	if len(s.results) != 0 {
+		t.Errorf("Reset kept %d results", len(s.results))
+	}
+}
+
+func TestScorerBounded(t *testing.T) {
+	s := NewPasswordValidator(1, NoMax, false, false, false, false, 0).NewScorer()
+	text := ""
+	for i := 0; i < 3*maxScorerResults; i++ {
+		text += "a"
+		s.Update(text)
+		if len(s.results) > maxScorerResults {
+			t.Fatalf("Scorer holds %d results, want at most %d", len(s.results), maxScorerResults)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package passval
+
+import (
+	_ "embed"
+	"slices"
+	"unicode"
+)
+
+// seasonsData lists month and season names, with common abbreviations, in
+// English, Spanish, French, German, Italian, Portuguese and Dutch.
+//
+//go:embed data/seasons.txt
+var seasonsData string
+
+// seasonWords is the trie of seasonsData, matched leet-aware like the dictionary.
+var seasonWords = loadDictionary(seasonsData)
+
+// penaltySeasonYear detects a month or season next to a year, the
+// "Summer2024$" and "January2023!" family that dominates corporate password
+// resets. Years are two digits or 19xx/20xx, before or after the word, with
+// at most one separator between them; the word must not continue a longer
+// one on its other side, so "Omar1990" is not March.
+func penaltySeasonYear(a *analysis, cfg penaltyConfig) *PenaltyDetail {
+	letterAt := func(i int) bool {
+		return i >= 0 && i < len(a.runes) && unicode.IsLetter(a.runes[i])
+	}
+	for _, m := range seasonWords.leetMatches(a.lower, 3, a.leet) {
+		start, end := m.start, m.end
+		if n := yearAt(a.runes, end, 1); n > 0 && !letterAt(start-1) {
+			end += n
+		} else if n := yearAt(a.runes, start, -1); n > 0 && !letterAt(end) {
+			start -= n
+		} else {
+			continue
+		}
+		return a.spanned(&PenaltyDetail{
+			Rule:   "season_year",
+			Factor: 0.15,
+			Desc: cfg.describe(m.word, "password is built on the month or season '%s' and a year",
+				"password is built on a month or season (%d chars) and a year"),
+			Match: a.password[a.offsets[start]:a.offsets[end]],
+		}, start, end)
+	}
+	return nil
+}
+
+// yearAt returns how many runes a year takes up reading from rune index i
+// forwards (dir 1) or from i-1 backwards (dir -1), including one optional
+// separator next to the word, or 0 if there is no year there.
+func yearAt(runes []rune, i, dir int) int {
+	at := func(k int) (rune, bool) {
+		j := i + k
+		if dir < 0 {
+			j = i - 1 - k
+		}
+		if j < 0 || j >= len(runes) {
+			return 0, false
+		}
+		return runes[j], true
+	}
+
+	skip := 0
+	if r, ok := at(0); ok && (r == ' ' || r == '-' || r == '_' || r == '.' || r == '\'') {
+		skip = 1
+	}
+	var digits []rune
+	for k := skip; ; k++ {
+		r, ok := at(k)
+		if !ok || !isASCIIDigit(r) {
+			break
+		}
+		digits = append(digits, r)
+	}
+	if dir < 0 {
+		slices.Reverse(digits)
+	}
+
+	switch {
+	case len(digits) == 2:
+	case len(digits) == 4 && (string(digits[:2]) == "19" || string(digits[:2]) == "20"):
+	default:
+		return 0
+	}
+	return skip + len(digits)
+}
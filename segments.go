@@ -0,0 +1,211 @@
+package passval
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PatternBruteforce is the Segment pattern of parts of a password that match
+// no penalty and are estimated as random characters.
+const PatternBruteforce = "bruteforce"
+
+// Segment is a part of a password with an estimate of the guesses an
+// attacker needs to find it, showing where a password loses strength, e.g.
+// "dragon" 10^3.2, "2024" 10^2 and a random tail 10^7.
+type Segment struct {
+	Token        string  `json:"token,omitempty"` // password[Start:End]; empty for ValidateBytes
+	Start        int     `json:"start"`
+	End          int     `json:"end"`
+	Pattern      string  `json:"pattern"` // the penalty that matched the segment, or PatternBruteforce
+	Guesses      float64 `json:"guesses"`
+	GuessesLog10 float64 `json:"guesses_log10"`
+}
+
+// WithSegmentEstimates fills Result.Segments with a breakdown of the password
+// into penalized patterns and random parts, each with a guess estimate. The
+// breakdown explains the score but does not change it.
+func WithSegmentEstimates() Option {
+	return func(v *PasswordValidator) {
+		v.segments = true
+	}
+}
+
+// segmentPassword covers the password with the spans of penalties, cheapest
+// per character first and without overlaps, and fills the gaps with
+// bruteforceSegments. Penalties about the character classes of the whole
+// password explain no particular part of it and are skipped.
+func segmentPassword(password string, penalties []PenaltyDetail, dict *dictionary, secret bool) []Segment {
+	var candidates []Segment
+	for _, p := range penalties {
+		if p.Rule == "single_class" || p.End <= p.Start {
+			continue
+		}
+		token := password[p.Start:p.End]
+		candidates = append(candidates, newSegment(token, p.Start, p.End, p.Rule,
+			patternGuesses(p.Rule, token, p.Match, dict)))
+	}
+	perChar := func(s Segment) float64 {
+		return s.GuessesLog10 / float64(utf8.RuneCountInString(password[s.Start:s.End]))
+	}
+	slices.SortStableFunc(candidates, func(a, b Segment) int {
+		if c := cmp.Compare(perChar(a), perChar(b)); c != 0 {
+			return c
+		}
+		return (b.End - b.Start) - (a.End - a.Start)
+	})
+
+	var segments []Segment
+	for _, c := range candidates {
+		if !slices.ContainsFunc(segments, func(s Segment) bool { return c.Start < s.End && s.Start < c.End }) {
+			segments = append(segments, c)
+		}
+	}
+	slices.SortFunc(segments, func(a, b Segment) int { return a.Start - b.Start })
+
+	var out []Segment
+	pos := 0
+	for _, s := range append(segments, Segment{Start: len(password)}) {
+		if s.Start > pos {
+			out = append(out, bruteforceSegments(password, pos, s.Start)...)
+		}
+		if s.End > s.Start {
+			out = append(out, s)
+		}
+		pos = max(pos, s.End)
+	}
+	if secret {
+		for i := range out {
+			out[i].Token = ""
+		}
+	}
+	return out
+}
+
+func newSegment(token string, start, end int, pattern string, guesses float64) Segment {
+	guesses = max(guesses, 1)
+	return Segment{
+		Token:        token,
+		Start:        start,
+		End:          end,
+		Pattern:      pattern,
+		Guesses:      guesses,
+		GuessesLog10: math.Log10(guesses),
+	}
+}
+
+// bruteforceSegments splits password[start:end] into bruteforce segments,
+// separating runs of two or more digits (years, PINs) from the rest so that
+// each is estimated from its own character pool.
+func bruteforceSegments(password string, start, end int) []Segment {
+	var out []Segment
+	add := func(from, to int) {
+		if to > from {
+			out = append(out, newSegment(password[from:to], from, to, PatternBruteforce, bruteforceGuesses(password[from:to])))
+		}
+	}
+	from := start
+	for i := start; i < end; {
+		if !isASCIIDigit(rune(password[i])) {
+			i++
+			continue
+		}
+		j := i
+		for j < end && isASCIIDigit(rune(password[j])) {
+			j++
+		}
+		if j-i >= 2 {
+			add(from, i)
+			add(i, j)
+			from = j
+		}
+		i = j
+	}
+	add(from, end)
+	return out
+}
+
+// naturalVocabulary is the size of the vocabulary assumed for natural-language
+// words: the everyday words of a language an attacker's wordlist holds.
+const naturalVocabulary = 20000
+
+// patternGuesses estimates the guesses to find token given that it matches
+// the pattern of the penalty rule. match is the penalty's Match, if any.
+func patternGuesses(rule, token, match string, dict *dictionary) float64 {
+	n := float64(utf8.RuneCountInString(token))
+	words := float64(max(len(dict.words), 1))
+	switch rule {
+	case "common_password":
+		return words
+	case "common_password_leet":
+		return words * 10 // substitution variants
+	case "dictionary_substring":
+		// One guess per word, for each of the words joined in match
+		return math.Pow(words, float64(strings.Count(match, "+")+1)) * caseVariants(token)
+	case "dictionary_concatenation":
+		return words * words * caseVariants(token)
+	case "repeated_chars":
+		first, _ := utf8.DecodeRuneInString(token)
+		return float64(effectivePoolSize(string(first))) * n
+	case "repeated_pattern":
+		if match == "" {
+			first, _ := utf8.DecodeRuneInString(token)
+			match = string(first)
+		}
+		return bruteforceGuesses(match) * n / float64(utf8.RuneCountInString(match))
+	case "sequential_chars":
+		first, _ := utf8.DecodeRuneInString(token)
+		base := 26.0
+		if unicode.IsDigit(first) {
+			base = 10
+		}
+		return base * n * 2 // start, length and direction
+	case "keyboard_pattern":
+		return 94 * n * 4 // start key, length and a few turns
+	case "natural_word":
+		return math.Pow(naturalVocabulary, float64(strings.Count(match, "+")+1)) * caseVariants(token)
+	case "topical_word":
+		return 1000 * caseVariants(token) // the size of a topical wordlist
+	case "season_year":
+		return float64(len(seasonWords.words)) * 200 * caseVariants(token)
+	case "date":
+		return 365 * 100 * 3 // day of a century, in one of three orderings
+	case "phone_number":
+		return math.Pow(10, max(digitCount(token)-3, 1)) // country or area code is guessable
+	case "email_address":
+		return words * 1000 // a common name or word at one of a thousand providers
+	case "url":
+		return 1e6 // one of the most visited sites
+	}
+	return bruteforceGuesses(token)
+}
+
+// bruteforceGuesses estimates token as random characters from the pool of
+// its character classes.
+func bruteforceGuesses(token string) float64 {
+	return math.Pow(float64(max(effectivePoolSize(token), 1)), float64(utf8.RuneCountInString(token)))
+}
+
+// caseVariants returns 2 for tokens with uppercase letters (a capitalized
+// word), otherwise 1.
+func caseVariants(token string) float64 {
+	for _, r := range token {
+		if unicode.IsUpper(r) {
+			return 2
+		}
+	}
+	return 1
+}
+
+func digitCount(s string) float64 {
+	n := 0
+	for _, r := range s {
+		if isASCIIDigit(r) {
+			n++
+		}
+	}
+	return float64(n)
+}
@@ -0,0 +1,35 @@
+package passval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSegmentEstimates(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0, WithSegmentEstimates())
+
+	r := v.ValidateResult("dragon2024Xk9$mP")
+	var parts []string
+	for _, s := range r.Segments {
+		parts = append(parts, s.Token+"/"+s.Pattern)
+		if s.Guesses < 1 || s.Token != "dragon2024Xk9$mP"[s.Start:s.End] {
+			t.Errorf("bad segment %+v", s)
+		}
+	}
+	if got, want := strings.Join(parts, " "), "dragon/dictionary_substring 2024/bruteforce Xk9$mP/bruteforce"; got != want {
+		t.Logf("segments: %+v", r.Segments)
+		t.Errorf("segments = %s, want %s", got, want)
+	}
+
+	r = v.ValidateResult("qwerty1234")
+	if len(r.Segments) == 0 || r.Segments[0].Start != 0 || r.Segments[len(r.Segments)-1].End != len("qwerty1234") {
+		t.Errorf("segments should cover the password: %+v", r.Segments)
+	}
+
+	if r := v.ValidateBytes([]byte("dragon2024Xk9$mP")); len(r.Segments) == 0 || r.Segments[0].Token != "" {
+		t.Errorf("ValidateBytes segments should carry no tokens: %+v", r.Segments)
+	}
+	if r := NewPasswordValidator(8, 64, false, false, false, false, 0).ValidateResult("dragon2024"); r.Segments != nil {
+		t.Error("segments should only be estimated with WithSegmentEstimates")
+	}
+}
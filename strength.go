@@ -0,0 +1,99 @@
+package passval
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// StrengthLevel is one step of a strength scale: scores from MinScore up to
+// the MinScore of the next level get Label.
+type StrengthLevel struct {
+	Label    string `json:"label"`
+	MinScore int    `json:"min_score"`
+}
+
+// String describes the level as its label and lowest score, e.g. "fair 30+".
+func (l StrengthLevel) String() string {
+	return fmt.Sprintf("%s %d+", l.Label, l.MinScore)
+}
+
+// formatStrengthLevels describes a strength scale, or the default one if
+// levels is empty.
+func formatStrengthLevels(levels []StrengthLevel) string {
+	if len(levels) == 0 {
+		levels = defaultStrengthLevels
+	}
+	parts := make([]string, len(levels))
+	for i, l := range levels {
+		parts[i] = l.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// defaultStrengthLevels is the scale of StrengthLabel.
+var defaultStrengthLevels = []StrengthLevel{
+	{StrengthVeryWeak, 0},
+	{StrengthWeak, 20},
+	{StrengthFair, 40},
+	{StrengthStrong, 60},
+	{StrengthVeryStrong, 80},
+}
+
+// WithStrengthLevels replaces the strength scale of the validator, which
+// labels Result.Strength, so that a meter shows the same boundaries the
+// Complexity threshold is set against. The levels may be given in any order
+// and use any labels; scores below the lowest MinScore get its label. For
+// example, "weak" below 30, "fair" to 60, "strong" to 80 and "very_strong"
+// above:
+//
+//	WithStrengthLevels(
+//		StrengthLevel{StrengthWeak, 0}, StrengthLevel{StrengthFair, 30},
+//		StrengthLevel{StrengthStrong, 60}, StrengthLevel{StrengthVeryStrong, 80},
+//	)
+func WithStrengthLevels(levels ...StrengthLevel) Option {
+	return func(v *PasswordValidator) {
+		v.strengthLevels = slices.Clone(levels)
+		slices.SortStableFunc(v.strengthLevels, func(a, b StrengthLevel) int { return a.MinScore - b.MinScore })
+	}
+}
+
+// StrengthLabel maps a 0-100 score to a label of the validator's strength
+// scale: the default scale of the package-level StrengthLabel, or the one set
+// with WithStrengthLevels.
+func (v *PasswordValidator) StrengthLabel(score int) string {
+	if len(v.strengthLevels) == 0 {
+		return strengthLabel(defaultStrengthLevels, score)
+	}
+	return strengthLabel(v.strengthLevels, score)
+}
+
+// strengthLabel returns the label of the highest of levels, sorted by
+// MinScore, that score reaches, or of the lowest if it reaches none.
+func strengthLabel(levels []StrengthLevel, score int) string {
+	label := levels[0].Label
+	for _, l := range levels[1:] {
+		if score < l.MinScore {
+			break
+		}
+		label = l.Label
+	}
+	return label
+}
+
+// strengthLevelProblems describes the levels that are out of range,
+// unlabeled or start at the same score as another.
+func strengthLevelProblems(levels []StrengthLevel) []string {
+	var problems []string
+	for i, l := range levels {
+		switch {
+		case l.Label == "":
+			problems = append(problems, fmt.Sprintf("strength level at score %d has no label", l.MinScore))
+		case l.MinScore < 0 || l.MinScore > 100:
+			problems = append(problems, fmt.Sprintf("strength level %q starts at score %d, outside 0-100", l.Label, l.MinScore))
+		case i > 0 && l.MinScore == levels[i-1].MinScore:
+			problems = append(problems, fmt.Sprintf("strength levels %q and %q both start at score %d", levels[i-1].Label, l.Label, l.MinScore))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,55 @@
+package passval
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStrengthLevels(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0, WithStrengthLevels(
+		StrengthLevel{StrengthVeryStrong, 80}, StrengthLevel{StrengthWeak, 0},
+		StrengthLevel{StrengthStrong, 60}, StrengthLevel{StrengthFair, 30},
+	))
+	tests := []struct {
+		score int
+		want  string
+	}{
+		{0, StrengthWeak}, {29, StrengthWeak}, {30, StrengthFair}, {59, StrengthFair},
+		{60, StrengthStrong}, {80, StrengthVeryStrong}, {100, StrengthVeryStrong},
+	}
+	for _, tt := range tests {
+		if got := v.StrengthLabel(tt.score); got != tt.want {
+			t.Errorf("StrengthLabel(%d) = %q, want %q", tt.score, got, tt.want)
+		}
+	}
+
+	r := v.ValidateResult("Xk9$mP2!vLq")
+	if r.Strength != v.StrengthLabel(r.Score) {
+		t.Errorf("Result.Strength = %q for score %d, want %q", r.Strength, r.Score, v.StrengthLabel(r.Score))
+	}
+	if got := v.Policy().StrengthLevels; len(got) != 4 || got[0].Label != StrengthWeak {
+		t.Errorf("Policy().StrengthLevels = %v, want the sorted scale", got)
+	}
+
+	def := NewPasswordValidator(8, 64, false, false, false, false, 0)
+	for score := 0; score <= 100; score++ {
+		if got, want := def.StrengthLabel(score), StrengthLabel(score); got != want {
+			t.Fatalf("default StrengthLabel(%d) = %q, want %q", score, got, want)
+		}
+	}
+	if changes := DiffPolicies(def, v); len(changes) != 1 || changes[0].Field != "strength_levels" {
+		t.Errorf("DiffPolicies = %v, want one strength_levels change", changes)
+	}
+}
+
+func TestStrengthLevelsStrict(t *testing.T) {
+	for _, levels := range [][]StrengthLevel{
+		{{"weak", 0}, {"", 50}},
+		{{"weak", 0}, {"strong", 120}},
+		{{"weak", 0}, {"fair", 40}, {"good", 40}},
+	} {
+		if _, err := NewValidatorStrict(8, 64, false, false, false, false, 0, WithStrengthLevels(levels...)); !errors.Is(err, ErrInvalidPolicy) {
+			t.Errorf("levels %v: err = %v, want ErrInvalidPolicy", levels, err)
+		}
+	}
+}
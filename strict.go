@@ -0,0 +1,82 @@
+package passval
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidPolicy is reported by NewValidatorStrict for contradictory or out
+// of range settings.
+var ErrInvalidPolicy = errors.New("invalid password policy")
+
+// NewValidatorStrict is NewPasswordValidator for configurations that should
+// fail loudly at startup: instead of adjusting nonsensical settings (a
+// maximum below the minimum, a complexity above 100) it returns an error
+// wrapping ErrInvalidPolicy that lists every problem. It also rejects
+// policies no password can satisfy, such as per-class minimums that add up to
+// more than the maximum length, or that Generate could not satisfy.
+func NewValidatorStrict(min, max int, lower, upper, numbers, symbols bool, complexity int, opts ...Option) (*PasswordValidator, error) {
+	var problems []string
+	if min < 1 {
+		problems = append(problems, fmt.Sprintf("minimum length %d is below 1", min))
+	}
+	if max < NoMax {
+		problems = append(problems, fmt.Sprintf("maximum length %d is negative; use NoMax for no maximum", max))
+	} else if max != NoMax && max < min {
+		problems = append(problems, fmt.Sprintf("maximum length %d is below minimum length %d", max, min))
+	}
+	if complexity < 0 || complexity > 100 {
+		problems = append(problems, fmt.Sprintf("complexity %d is outside 0-100", complexity))
+	}
+
+	v := NewPasswordValidator(min, max, lower, upper, numbers, symbols, complexity, opts...)
+	problems = append(problems, v.configProblems()...)
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPolicy, strings.Join(problems, "; "))
+	}
+	return v, nil
+}
+
+// MustNewValidator is like NewValidatorStrict but panics if the policy is
+// invalid. It simplifies initialization of global validators.
+func MustNewValidator(min, max int, lower, upper, numbers, symbols bool, complexity int, opts ...Option) *PasswordValidator {
+	v, err := NewValidatorStrict(min, max, lower, upper, numbers, symbols, complexity, opts...)
+	if err != nil {
+		panic("passval: MustNewValidator: " + err.Error())
+	}
+	return v
+}
+
+// configProblems describes the settings of v that options leave
+// contradictory or out of range.
+func (v *PasswordValidator) configProblems() []string {
+	var problems []string
+	counts := []struct {
+		name string
+		n    int
+	}{
+		{"minimum lowercase letters", v.MinLower}, {"minimum uppercase letters", v.MinUpper},
+		{"minimum digits", v.MinDigits}, {"minimum symbols", v.MinSymbols},
+		{"minimum unique characters", v.minUnique}, {"length exemption", v.exemptLength},
+		{"byte limit", v.maxBytes}, {"dictionary minimum match length", v.dictMinMatch},
+	}
+	for _, c := range counts {
+		if c.n < 0 {
+			problems = append(problems, fmt.Sprintf("%s %d is negative", c.name, c.n))
+		}
+	}
+	if v.WarnThreshold < 0 || v.WarnThreshold > 100 {
+		problems = append(problems, fmt.Sprintf("warn threshold %d is outside 0-100", v.WarnThreshold))
+	}
+	if v.MaxLength != NoMax && v.MinLength > v.MaxLength {
+		problems = append(problems, fmt.Sprintf("minimum length %d is above maximum length %d", v.MinLength, v.MaxLength))
+	}
+	problems = append(problems, presetProblems(v.presets)...)
+	problems = append(problems, penaltyProblems(v.skipPenalties)...)
+	problems = append(problems, strengthLevelProblems(v.strengthLevels)...)
+	if err := v.checkGenerationCharsets(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	return problems
+}
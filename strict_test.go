@@ -0,0 +1,69 @@
+package passval
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewValidatorStrict(t *testing.T) {
+	v, err := NewValidatorStrict(12, NoMax, true, true, true, false, 60, WithMinClassCounts(2, 1, 1, 0))
+	if err != nil {
+		t.Fatalf("valid policy: %v", err)
+	}
+	if v.MinLength != 12 || v.MaxLength != NoMax || v.MinLower != 2 {
+		t.Errorf("got MinLength=%d MaxLength=%d MinLower=%d", v.MinLength, v.MaxLength, v.MinLower)
+	}
+
+	tests := []struct {
+		name string
+		v    func() (*PasswordValidator, error)
+		want []string
+	}{
+		{"max below min", func() (*PasswordValidator, error) {
+			return NewValidatorStrict(12, 8, true, true, true, true, 50)
+		}, []string{"maximum length 8 is below minimum length 12"}},
+		{"out of range", func() (*PasswordValidator, error) {
+			return NewValidatorStrict(0, -5, true, true, true, true, 120)
+		}, []string{"minimum length 0", "maximum length -5 is negative", "complexity 120"}},
+		{"class minimums", func() (*PasswordValidator, error) {
+			return NewValidatorStrict(8, 10, true, true, true, true, 50, WithMinClassCounts(4, 4, 4, 0))
+		}, []string{"per-class minimums need 13 characters"}},
+		{"negative option", func() (*PasswordValidator, error) {
+			return NewValidatorStrict(8, 64, true, true, true, true, 50, WithMinUniqueChars(-1), WithWarnThreshold(101))
+		}, []string{"minimum unique characters -1", "warn threshold 101"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := tt.v()
+			if v != nil || !errors.Is(err, ErrInvalidPolicy) {
+				t.Fatalf("got %v, %v; want ErrInvalidPolicy", v, err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("error %q does not mention %q", err, w)
+				}
+			}
+		})
+	}
+}
+
+func TestMustHelpers(t *testing.T) {
+	v := MustNewValidator(10, 32, true, true, true, true, 50)
+	if pwd := v.MustGenerate(); len(pwd) < 10 || len(pwd) > 32 {
+		t.Errorf("MustGenerate() = %q, want 10-32 characters", pwd)
+	}
+
+	mustPanic := func(name string, f func()) {
+		t.Helper()
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("%s did not panic", name)
+			}
+		}()
+		f()
+	}
+	mustPanic("MustNewValidator", func() { MustNewValidator(12, 8, true, true, true, true, 50) })
+	unsatisfiable := NewPasswordValidator(8, 10, true, true, true, true, 50, WithMinClassCounts(4, 4, 4, 0))
+	mustPanic("MustGenerate", func() { unsatisfiable.MustGenerate() })
+}
@@ -0,0 +1,190 @@
+package passval
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// CharClass is a set of character classes, combined with |.
+type CharClass uint8
+
+// Character classes for structure rules. Lowercase and uppercase cover
+// letters with case, digits are Unicode decimal digits, and symbols are
+// punctuation and symbol characters.
+const (
+	ClassLower CharClass = 1 << iota
+	ClassUpper
+	ClassDigit
+	ClassSymbol
+
+	ClassLetter = ClassLower | ClassUpper
+)
+
+// String describes the set, e.g. "letter or digit".
+func (c CharClass) String() string {
+	var names []string
+	if c&ClassLetter == ClassLetter {
+		names = append(names, "letter")
+	} else if c&ClassLower != 0 {
+		names = append(names, "lowercase letter")
+	} else if c&ClassUpper != 0 {
+		names = append(names, "uppercase letter")
+	}
+	if c&ClassDigit != 0 {
+		names = append(names, "digit")
+	}
+	if c&ClassSymbol != 0 {
+		names = append(names, "symbol")
+	}
+	if len(names) == 0 {
+		return "nothing"
+	}
+	return strings.Join(names, " or ")
+}
+
+// contains reports whether r belongs to one of the classes of c. Runes outside
+// the four classes, such as spaces, belong to none.
+func (c CharClass) contains(r rune) bool {
+	switch {
+	case unicode.IsLower(r):
+		return c&ClassLower != 0
+	case unicode.IsUpper(r):
+		return c&ClassUpper != 0
+	case unicode.IsDigit(r):
+		return c&ClassDigit != 0
+	case isSymbol(r):
+		return c&ClassSymbol != 0
+	}
+	return false
+}
+
+// StructureRule is a constraint on the layout of a password, such as where
+// digits may appear, for backends that impose one. Build rules with FirstChar,
+// LastChar, NoLeadingChars, NoTrailingChars, NoDigitPadding or StructureFunc.
+type StructureRule struct {
+	desc  string
+	check func(runes []rune) bool // reports whether the password satisfies the rule
+}
+
+// String returns the requirement the rule imposes, e.g.
+// "first character must be a letter".
+func (s StructureRule) String() string {
+	return s.desc
+}
+
+// FirstChar requires the first character of the password to belong to class.
+func FirstChar(class CharClass) StructureRule {
+	return StructureRule{
+		desc: "first character must be " + withArticle(class.String()),
+		check: func(runes []rune) bool {
+			return len(runes) > 0 && class.contains(runes[0])
+		},
+	}
+}
+
+// LastChar requires the last character of the password to belong to class.
+func LastChar(class CharClass) StructureRule {
+	return StructureRule{
+		desc: "last character must be " + withArticle(class.String()),
+		check: func(runes []rune) bool {
+			return len(runes) > 0 && class.contains(runes[len(runes)-1])
+		},
+	}
+}
+
+// NoDigitPadding rejects passwords that are a run of letters padded with
+// digits on one side only, such as "Summer2024" or "2024summer".
+func NoDigitPadding() StructureRule {
+	return StructureRule{
+		desc: "must not be a word padded with digits",
+		check: func(runes []rune) bool {
+			letters := func(r rune) bool { return unicode.IsLetter(r) }
+			digits := func(r rune) bool { return unicode.IsDigit(r) }
+			return !splitsInto(runes, letters, digits) && !splitsInto(runes, digits, letters)
+		},
+	}
+}
+
+// NoLeadingChars forbids passwords starting with any of chars, such as
+// characters a backend treats specially at the start of a value.
+func NoLeadingChars(chars string) StructureRule {
+	return StructureRule{
+		desc: fmt.Sprintf("first character must not be any of %q", chars),
+		check: func(runes []rune) bool {
+			return len(runes) == 0 || !strings.ContainsRune(chars, runes[0])
+		},
+	}
+}
+
+// NoTrailingChars forbids passwords ending with any of chars.
+func NoTrailingChars(chars string) StructureRule {
+	return StructureRule{
+		desc: fmt.Sprintf("last character must not be any of %q", chars),
+		check: func(runes []rune) bool {
+			return len(runes) == 0 || !strings.ContainsRune(chars, runes[len(runes)-1])
+		},
+	}
+}
+
+// StructureFunc returns a custom rule that fails when check returns false.
+// desc states the requirement and is reported as the failure message. check
+// receives a string copy of the password, which ValidateBytes cannot zero.
+func StructureFunc(desc string, check func(password string) bool) StructureRule {
+	return StructureRule{
+		desc: desc,
+		check: func(runes []rune) bool {
+			return check(string(runes))
+		},
+	}
+}
+
+// WithStructureRules fails validation when the password breaks any of rules.
+// A failure is a hard rule failure with code RuleStructure and the rule's
+// requirement as message.
+func WithStructureRules(rules ...StructureRule) Option {
+	return func(v *PasswordValidator) {
+		v.structureRules = append(v.structureRules, rules...)
+	}
+}
+
+// withArticle prefixes noun with "a" or "an".
+func withArticle(noun string) string {
+	if strings.ContainsRune("aeiou", rune(noun[0])) {
+		return "an " + noun
+	}
+	return "a " + noun
+}
+
+// splitsInto reports whether runes is a non-empty run matching head followed
+// by a non-empty run matching tail, and nothing else.
+func splitsInto(runes []rune, head, tail func(rune) bool) bool {
+	i := 0
+	for i < len(runes) && head(runes[i]) {
+		i++
+	}
+	if i == 0 || i == len(runes) {
+		return false
+	}
+	for _, r := range runes[i:] {
+		if !tail(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkStructureRules records a failure for each rule password breaks. The
+// rune copy of password is zeroed afterwards, as it may hold a secret.
+func checkStructureRules(vErr *ValidationError, password string, rules []StructureRule) {
+	if len(rules) == 0 {
+		return
+	}
+	runes := []rune(password)
+	defer clear(runes)
+	for _, rule := range rules {
+		if !rule.check(runes) {
+			vErr.fail(RuleStructure, rule.desc, "rule", rule.desc)
+		}
+	}
+}
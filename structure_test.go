@@ -0,0 +1,64 @@
+package passval
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestStructureRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     StructureRule
+		password string
+		wantPass bool
+	}{
+		{"first letter", FirstChar(ClassLetter), "abc123!?", true},
+		{"first letter digit", FirstChar(ClassLetter), "1abc23!?", false},
+		{"first upper", FirstChar(ClassUpper), "abc123!?", false},
+		{"last digit or symbol", LastChar(ClassDigit | ClassSymbol), "abc123!?", true},
+		{"last digit or symbol letter", LastChar(ClassDigit | ClassSymbol), "abc123!x", false},
+		{"trailing digits", NoDigitPadding(), "Summer2024", false},
+		{"leading digits", NoDigitPadding(), "2024summer", false},
+		{"digits inside", NoDigitPadding(), "Sum2024mer", true},
+		{"with symbol", NoDigitPadding(), "Summer2024!", true},
+		{"letters only", NoDigitPadding(), "Summertime", true},
+		{"custom", StructureFunc("must not contain spaces", func(p string) bool { return !strings.Contains(p, " ") }), "abc def1", false},
+	}
+	for _, tt := range tests {
+		v := NewPasswordValidator(4, 64, false, false, false, false, 0, WithStructureRules(tt.rule))
+		res := v.ValidateResult(tt.password)
+		if res.Pass != tt.wantPass {
+			t.Errorf("%s: Validate(%q) = %v (%v), want %v", tt.name, tt.password, res.Pass, res.RuleFails, tt.wantPass)
+		}
+		if !tt.wantPass && (!slices.Contains(res.Codes(), RuleStructure) || !slices.Contains(res.FailMessages(), tt.rule.String())) {
+			t.Errorf("%s: Validate(%q) codes = %v, fails = %v", tt.name, tt.password, res.Codes(), res.RuleFails)
+		}
+	}
+
+	if got := FirstChar(ClassLetter).String(); got != "first character must be a letter" {
+		t.Errorf("FirstChar(ClassLetter) = %q", got)
+	}
+	if got := LastChar(ClassUpper | ClassDigit).String(); got != "last character must be an uppercase letter or digit" {
+		t.Errorf("LastChar(ClassUpper|ClassDigit) = %q", got)
+	}
+}
+
+func TestStructureRulesPolicy(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, false, 0, WithStructureRules(FirstChar(ClassLetter), NoDigitPadding()))
+
+	want := []string{"first character must be a letter", "must not be a word padded with digits"}
+	if got := v.Policy().StructureRules; !slices.Equal(got, want) {
+		t.Errorf("Policy().StructureRules = %v, want %v", got, want)
+	}
+
+	for i := 0; i < 20; i++ {
+		pwd, err := v.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error: %v", err)
+		}
+		if !FirstChar(ClassLetter).check([]rune(pwd)) {
+			t.Errorf("generated password %q does not start with a letter", pwd)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package passval
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxSuggestAttempts bounds the candidates each SuggestStronger strategy tries.
+const maxSuggestAttempts = 30
+
+// SuggestStronger proposes up to three strengthened variants of a weak
+// password for "try something like..." flows: one with random words inserted,
+// one with random characters appended, and one with random characters breaking
+// up the weakest part (e.g. a dictionary word). Every suggestion passes the
+// policy; strategies that find none within their attempts are left out.
+//
+// It is optional. A suggestion keeps part of the user's rejected choice, so a
+// generated password or passphrase is stronger where users will accept one.
+func (v *PasswordValidator) SuggestStronger(password string) ([]string, error) {
+	if err := v.checkGenerationCharsets(); err != nil {
+		return nil, err
+	}
+	r := v.random()
+	strategies := []func(io.Reader, []rune, int) (string, error){
+		v.suggestWords,
+		v.suggestLength,
+		v.suggestBreakUp,
+	}
+
+	var suggestions []string
+	for _, strategy := range strategies {
+		for i := 0; i < maxSuggestAttempts; i++ {
+			s, err := strategy(r, []rune(password), i)
+			if err != nil {
+				return nil, fmt.Errorf("reading random source: %w", err)
+			}
+			if s == "" || slices.Contains(suggestions, s) || findBannedTerm(s, v.profanity, v.leet) != "" {
+				continue
+			}
+			if v.validate(s).Pass {
+				suggestions = append(suggestions, s)
+				break
+			}
+		}
+	}
+	return suggestions, nil
+}
+
+// suggestWords inserts 1 + attempt/10 capitalized random words, each joined
+// with a random symbol, at random positions of password.
+func (v *PasswordValidator) suggestWords(r io.Reader, password []rune, attempt int) (string, error) {
+	for range 1 + attempt/10 {
+		var wl Wordlist = defaultWordlist
+		if v.wordlist != nil {
+			wl = v.wordlist
+		}
+		w, err := randIndexFrom(r, wl.Len())
+		if err != nil {
+			return "", err
+		}
+		word := wl.WordAt(w)
+		sep, err := v.randomChars(r, 1)
+		if err != nil {
+			return "", err
+		}
+		at, err := randIndexFrom(r, len(password)+1)
+		if err != nil {
+			return "", err
+		}
+		first, size := utf8.DecodeRuneInString(word)
+		insert := []rune(sep + string(unicode.ToUpper(first)) + word[size:])
+		password = slices.Insert(password, at, insert...)
+	}
+	return string(password), nil
+}
+
+// suggestLength appends 3 + attempt/3 random characters from the generation charset.
+func (v *PasswordValidator) suggestLength(r io.Reader, password []rune, attempt int) (string, error) {
+	extra, err := v.randomChars(r, 3+attempt/3)
+	if err != nil {
+		return "", err
+	}
+	return string(password) + extra, nil
+}
+
+// suggestBreakUp inserts 2 + attempt/5 random characters at random positions
+// inside the span of the strongest penalty, so that the word or pattern it
+// found no longer reads through.
+func (v *PasswordValidator) suggestBreakUp(r io.Reader, password []rune, attempt int) (string, error) {
+	a := v.analyze(string(password))
+	_, isPassphrase := v.passphraseWords(a.password)
+	penalties := detectPenalties(a, v.dictionary(), v.penaltyConfig(isPassphrase, false))
+	if len(penalties) == 0 {
+		return "", nil
+	}
+	worst := penalties[0]
+	for _, p := range penalties[1:] {
+		if p.Factor < worst.Factor {
+			worst = p
+		}
+	}
+	start := len([]rune(a.password[:worst.Start]))
+	end := start + len([]rune(a.password[worst.Start:worst.End]))
+	if end-start < 2 {
+		return "", nil
+	}
+
+	for range 2 + attempt/5 {
+		c, err := v.randomChars(r, 1)
+		if err != nil {
+			return "", err
+		}
+		at, err := randIndexFrom(r, end-start-1)
+		if err != nil {
+			return "", err
+		}
+		password = slices.Insert(password, start+1+at, []rune(c)...)
+		end++
+	}
+	return string(password), nil
+}
+
+// randomChars returns n random characters from the generation charset,
+// including one of each required class when n leaves room for them.
+func (v *PasswordValidator) randomChars(r io.Reader, n int) (string, error) {
+	charset, required := v.generationPlan()
+	buf := make([]byte, n)
+	if len(required) <= n {
+		err := v.fillCandidate(r, buf)
+		return string(buf), err
+	}
+	for i := range buf {
+		c, err := randIndexFrom(r, len(charset))
+		if err != nil {
+			return "", err
+		}
+		buf[i] = charset[c]
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,51 @@
+package passval
+
+import (
+	mrand "math/rand"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSuggestStronger(t *testing.T) {
+	v := NewPasswordValidator(10, 64, true, true, true, true, 60, WithRandSource(mrand.New(mrand.NewSource(1))))
+	for _, pwd := range []string{"password", "Summer2024$", "dragon", "qwerty123"} {
+		suggestions, err := v.SuggestStronger(pwd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(suggestions) == 0 {
+			t.Errorf("%q: expected at least one suggestion", pwd)
+		}
+		for _, s := range suggestions {
+			if ok, score := v.Validate(s); !ok {
+				t.Errorf("%q: suggestion %q does not pass (score %d)", pwd, s, score)
+			}
+		}
+	}
+
+	failing := NewPasswordValidator(10, 64, true, true, true, true, 60, WithRandSource(strings.NewReader("")))
+	if _, err := failing.SuggestStronger("password"); err == nil {
+		t.Error("expected a random source error")
+	}
+}
+
+func TestSuggestStrongerNonASCIIWords(t *testing.T) {
+	wl, err := NewWordlist([]string{"éclair", "ñandú", "øresund"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewPasswordValidator(10, 64, true, true, true, true, 60, WithWordlist(wl), WithRandSource(mrand.New(mrand.NewSource(1))))
+	suggestions, err := v.SuggestStronger("dragon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(suggestions) == 0 || !strings.ContainsAny(suggestions[0], "ÉÑØ") {
+		t.Errorf("expected a suggestion with a capitalized list word first, got %q", suggestions)
+	}
+	for _, s := range suggestions {
+		if !utf8.ValidString(s) || strings.ContainsRune(s, utf8.RuneError) {
+			t.Errorf("suggestion %q is not valid UTF-8", s)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package passval
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	vowelChars     = "aeiou"
+	consonantChars = "bcdfghjklmnpqrstvwxyz"
+)
+
+// GenerateFromTemplate creates a random password in a fixed format, for
+// legacy systems that require one ("two letters, six digits, one symbol" is
+// "aa999999#"). Each placeholder of tpl is replaced by a random character:
+//
+//	c  lowercase consonant    C  uppercase consonant
+//	v  lowercase vowel        V  uppercase vowel
+//	a  lowercase letter       A  uppercase letter
+//	9  digit                  #  symbol
+//	*  any letter, digit or symbol
+//
+// Every other character is copied literally, and a backslash copies the next
+// character literally (`\9` is a 9), so "Cvccvc-99-##" gives e.g.
+// "Lemrot-37-&!". Characters excluded with WithExcludeChars or
+// WithExcludeAmbiguous are not used, symbols are drawn from WithAllowedSymbols
+// if set, and WithRandSource applies. The password is not checked against the
+// policy; pass it to ValidateResult if it must also comply.
+func (v *PasswordValidator) GenerateFromTemplate(tpl string) (string, error) {
+	if tpl == "" {
+		return "", fmt.Errorf("empty template")
+	}
+	lower, upper, number, symbol := v.generationCharsets()
+	vowels := removeChars(vowelChars, v.excludeChars)
+	consonants := removeChars(consonantChars, v.excludeChars)
+	sets := map[rune]string{
+		'c': consonants, 'C': strings.ToUpper(consonants),
+		'v': vowels, 'V': strings.ToUpper(vowels),
+		'a': lower, 'A': upper,
+		'9': number, '#': symbol,
+		'*': lower + upper + number + symbol,
+	}
+
+	r := v.random()
+	var b strings.Builder
+	escaped := false
+	for i, c := range tpl {
+		set, placeholder := sets[c]
+		switch {
+		case escaped:
+			escaped = false
+			b.WriteRune(c)
+			continue
+		case c == '\\':
+			escaped = true
+			continue
+		case !placeholder:
+			b.WriteRune(c)
+			continue
+		case set == "":
+			return "", fmt.Errorf("template placeholder %q at byte %d has no characters left after exclusions", c, i)
+		}
+		chars := []rune(set)
+		n, err := randIndexFrom(r, len(chars))
+		if err != nil {
+			return "", fmt.Errorf("reading random source: %w", err)
+		}
+		b.WriteRune(chars[n])
+	}
+	if escaped {
+		return "", fmt.Errorf("template ends with an unfinished escape")
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,50 @@
+package passval
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGenerateFromTemplate(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 50)
+	tests := []struct {
+		tpl  string
+		want *regexp.Regexp
+	}{
+		{"Cvccvc-99-##", regexp.MustCompile(`^[BCDFGHJKLMNPQRSTVWXYZ][aeiou][bcdfghjklmnpqrstvwxyz]{2}[aeiou][bcdfghjklmnpqrstvwxyz]-[0-9]{2}-[^a-zA-Z0-9]{2}$`)},
+		{"aa999999#", regexp.MustCompile(`^[a-z]{2}[0-9]{6}[^a-zA-Z0-9]$`)},
+		{`ID\9\\A*`, regexp.MustCompile(`^ID9\\[A-Z].$`)},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			pwd, err := v.GenerateFromTemplate(tt.tpl)
+			if err != nil {
+				t.Fatalf("GenerateFromTemplate(%q): %v", tt.tpl, err)
+			}
+			if !tt.want.MatchString(pwd) {
+				t.Fatalf("GenerateFromTemplate(%q) = %q, want %s", tt.tpl, pwd, tt.want)
+			}
+		}
+	}
+
+	limited := v.Clone(WithAllowedSymbols("!"), WithExcludeAmbiguous())
+	for range 50 {
+		pwd, err := limited.GenerateFromTemplate("A#9A#9")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.ContainsAny(pwd, ambiguousChars) || strings.Count(pwd, "!") != 2 {
+			t.Fatalf("GenerateFromTemplate = %q, want no ambiguous characters and only '!' symbols", pwd)
+		}
+	}
+
+	for _, tpl := range []string{"", `aa\`} {
+		if _, err := v.GenerateFromTemplate(tpl); err == nil {
+			t.Errorf("GenerateFromTemplate(%q) should fail", tpl)
+		}
+	}
+	if _, err := v.Clone(WithExcludeChars("aeiou")).GenerateFromTemplate("cv"); err == nil {
+		t.Error("expected an error for a placeholder with no characters left")
+	}
+}
@@ -0,0 +1,82 @@
+package passval
+
+import (
+	"fmt"
+	"math"
+)
+
+// TokenMode selects the alphabet used by GenerateToken.
+type TokenMode int
+
+const (
+	// TokenHex produces lowercase hexadecimal tokens (4 bits per character).
+	TokenHex TokenMode = iota
+	// TokenBase64URL produces tokens from the URL-safe base64 alphabet (6 bits per character).
+	TokenBase64URL
+	// TokenAlphanumeric produces tokens from A-Z, a-z and 0-9 (~5.95 bits per character).
+	TokenAlphanumeric
+)
+
+const (
+	hexAlphabet          = "0123456789abcdef"
+	base64URLAlphabet    = upperChars + lowerChars + numberChars + "-_"
+	alphanumericAlphabet = upperChars + lowerChars + numberChars
+)
+
+func (m TokenMode) alphabet() (string, error) {
+	switch m {
+	case TokenHex:
+		return hexAlphabet, nil
+	case TokenBase64URL:
+		return base64URLAlphabet, nil
+	case TokenAlphanumeric:
+		return alphanumericAlphabet, nil
+	}
+	return "", fmt.Errorf("unknown token mode %d", m)
+}
+
+// String returns the name of the token mode.
+func (m TokenMode) String() string {
+	switch m {
+	case TokenHex:
+		return "hex"
+	case TokenBase64URL:
+		return "base64url"
+	case TokenAlphanumeric:
+		return "alphanumeric"
+	}
+	return fmt.Sprintf("TokenMode(%d)", int(m))
+}
+
+// GenerateToken creates a random machine secret (API key, reset token) of
+// length characters using crypto/rand. Every character is drawn uniformly from
+// the mode's alphabet, so the token carries length × log2(alphabet) bits.
+func GenerateToken(length int, mode TokenMode) (string, error) {
+	if length < 1 {
+		return "", fmt.Errorf("token length must be positive, got %d", length)
+	}
+	alphabet, err := mode.alphabet()
+	if err != nil {
+		return "", err
+	}
+
+	token := make([]byte, length)
+	for i := range token {
+		n, err := randIndex(len(alphabet))
+		if err != nil {
+			return "", err
+		}
+		token[i] = alphabet[n]
+	}
+	return string(token), nil
+}
+
+// TokenEntropy returns the entropy in bits of a token of length characters
+// generated with the given mode.
+func TokenEntropy(length int, mode TokenMode) float64 {
+	alphabet, err := mode.alphabet()
+	if err != nil || length < 1 {
+		return 0
+	}
+	return float64(length) * math.Log2(float64(len(alphabet)))
+}
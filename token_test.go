@@ -0,0 +1,44 @@
+package passval
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateToken(t *testing.T) {
+	tests := []struct {
+		mode     TokenMode
+		alphabet string
+	}{
+		{TokenHex, hexAlphabet},
+		{TokenBase64URL, base64URLAlphabet},
+		{TokenAlphanumeric, alphanumericAlphabet},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			token, err := GenerateToken(33, tt.mode)
+			if err != nil {
+				t.Fatalf("GenerateToken() error: %v", err)
+			}
+			if len(token) != 33 {
+				t.Errorf("expected 33 characters, got %d", len(token))
+			}
+			for _, r := range token {
+				if !strings.ContainsRune(tt.alphabet, r) {
+					t.Errorf("token %q contains %q outside the %s alphabet", token, r, tt.mode)
+				}
+			}
+		})
+	}
+
+	if _, err := GenerateToken(0, TokenHex); err == nil {
+		t.Error("expected error for zero length")
+	}
+	if _, err := GenerateToken(16, TokenMode(99)); err == nil {
+		t.Error("expected error for unknown mode")
+	}
+	if bits := TokenEntropy(32, TokenHex); bits != 128 {
+		t.Errorf("expected 128 bits for 32 hex chars, got %.1f", bits)
+	}
+}
@@ -0,0 +1,120 @@
+package passval
+
+import (
+	"embed"
+	"slices"
+	"strings"
+)
+
+// Topic names an embedded wordlist of popular names that users build
+// passwords on, such as "liverpool1!" or "lakers2024".
+type Topic string
+
+// Topics selectable with WithTopicalWordlists.
+const (
+	TopicFootballClubs Topic = "football"    // football (soccer) clubs
+	TopicUSSports      Topic = "us_sports"   // NBA and NFL teams
+	TopicCapitalCities Topic = "capitals"    // capital and major cities
+	TopicBands         Topic = "bands"       // bands and music artists
+	TopicSuperheroes   Topic = "superheroes" // superheroes and comic characters
+)
+
+var allTopics = []Topic{TopicFootballClubs, TopicUSSports, TopicCapitalCities, TopicBands, TopicSuperheroes}
+
+// Topics returns every embedded topic.
+func Topics() []Topic {
+	return slices.Clone(allTopics)
+}
+
+//go:embed data/topics/*.txt
+var topicFiles embed.FS
+
+// topicNouns names the kind of name each topic holds, for descriptions.
+var topicNouns = map[Topic]string{
+	TopicFootballClubs: "football club",
+	TopicUSSports:      "sports team",
+	TopicCapitalCities: "city",
+	TopicBands:         "band",
+	TopicSuperheroes:   "superhero",
+}
+
+// topicalWords matches the words of the selected topics.
+type topicalWords struct {
+	dict  *dictionary
+	topic map[string]Topic // first selected topic listing each word
+}
+
+// WithTopicalWordlists penalizes passwords built on the names of the given
+// topics, e.g. WithTopicalWordlists(TopicFootballClubs, TopicUSSports), or of
+// all topics if none are given. The names are matched like dictionary words,
+// leet-aware, with the "topical_word" penalty; names the common passwords
+// dictionary already lists are left to it. Unknown topics are ignored.
+func WithTopicalWordlists(topics ...Topic) Option {
+	return func(v *PasswordValidator) {
+		if len(topics) == 0 {
+			topics = allTopics
+		}
+		for _, t := range topics {
+			if slices.Contains(allTopics, t) && !slices.Contains(v.topics, t) {
+				v.topics = append(v.topics, t)
+			}
+		}
+		v.topical = loadTopics(v.topics)
+	}
+}
+
+// loadTopics builds the matcher of the embedded wordlists of topics.
+func loadTopics(topics []Topic) *topicalWords {
+	tw := &topicalWords{topic: make(map[string]Topic)}
+	var words []string
+	for _, t := range topics {
+		data, err := topicFiles.ReadFile("data/topics/" + string(t) + ".txt")
+		if err != nil {
+			continue
+		}
+		for line := range strings.SplitSeq(string(data), "\n") {
+			w := strings.TrimSpace(strings.ToLower(line))
+			if _, ok := tw.topic[w]; !ok && w != "" {
+				tw.topic[w] = t
+				words = append(words, w)
+			}
+		}
+	}
+	tw.dict = newDictionary(words)
+	return tw
+}
+
+// penaltyTopicalWord detects the longest name from the topical wordlists in
+// the password, weighted by how much of the password it makes up.
+func penaltyTopicalWord(a *analysis, tw *topicalWords, dict *dictionary, cfg penaltyConfig) *PenaltyDetail {
+	if tw == nil {
+		return nil
+	}
+	var best dictMatch
+	for _, m := range cfg.scanWords(a, "topical", tw.dict, a.forms()[0], 4) {
+		if len(m.word) > len(best.word) && (dict == nil || !dict.contains(m.word)) {
+			best = m
+		}
+	}
+	if best.word == "" {
+		return nil
+	}
+
+	var factor float64
+	switch ratio := float64(len(best.word)) / float64(len(a.lower)); {
+	case ratio >= 0.5:
+		factor = 0.3
+	case ratio >= 0.3:
+		factor = 0.6
+	default:
+		return nil
+	}
+	noun := topicNouns[tw.topic[best.word]]
+	return a.spanned(&PenaltyDetail{
+		Rule:   "topical_word",
+		Factor: factor,
+		Desc: cfg.describe(best.word, "password is built on the "+noun+" name '%s'",
+			"password is built on a "+noun+" name (%d chars)"),
+		Match: best.word,
+	}, best.start, best.end)
+}
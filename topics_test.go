@@ -0,0 +1,54 @@
+package passval
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTopicalWordlists(t *testing.T) {
+	tests := []struct {
+		password string
+		topics   []Topic
+		match    string // "" for no topical_word penalty
+	}{
+		{"liverpool1!", []Topic{TopicFootballClubs}, "liverpool"},
+		{"L1verp00l#9", []Topic{TopicFootballClubs}, "liverpool"},
+		{"Metallica77", nil, "metallica"},
+		{"Wolverine!x", []Topic{TopicSuperheroes}, "wolverine"},
+		{"Metallica77", []Topic{TopicFootballClubs}, ""},
+		{"lakers2024", []Topic{TopicUSSports}, ""}, // already in the common passwords list
+		{"Xq7#zR2mK9w!", nil, ""},
+	}
+	for _, tt := range tests {
+		if tt.password == "lakers2024" && !fullDictionary {
+			continue
+		}
+		var match string
+		for _, p := range DetectPenalties(tt.password, WithTopicalWordlists(tt.topics...)) {
+			if p.Rule == "topical_word" {
+				match = p.Match
+			}
+		}
+		if match != tt.match {
+			t.Errorf("%q with %v: topical match = %q, want %q", tt.password, tt.topics, match, tt.match)
+		}
+	}
+
+	// Without the option, names are not penalized as topical words.
+	for _, p := range DetectPenalties("liverpool1!") {
+		if p.Rule == "topical_word" {
+			t.Errorf("penalty without WithTopicalWordlists: %+v", p)
+		}
+	}
+
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0,
+		WithTopicalWordlists(TopicBands, "unknown", TopicBands))
+	if p := v.Policy(); !slices.Equal(p.Topics, []string{"bands"}) {
+		t.Errorf("Policy().Topics = %v, want [bands]", p.Topics)
+	}
+	for _, topic := range Topics() {
+		if tw := loadTopics([]Topic{topic}); len(tw.dict.words) == 0 {
+			t.Errorf("topic %q has no words", topic)
+		}
+	}
+}
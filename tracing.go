@@ -0,0 +1,64 @@
+package passval
+
+import (
+	"context"
+	"time"
+)
+
+// tracerName is the instrumentation name passed to TracerProvider.Tracer.
+const tracerName = "github.com/fernandezvara/passvalidator"
+
+// Span is the subset of a tracing span used to instrument external checks.
+// An OpenTelemetry span can be adapted by mapping SetAttribute to
+// span.SetAttributes(attribute.String/Bool/Int64(...)).
+type Span interface {
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts spans around external checks.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// TracerProvider hands out tracers, mirroring OpenTelemetry's trace.TracerProvider
+// so an adapter around otel.GetTracerProvider() is a few lines.
+type TracerProvider interface {
+	Tracer(name string) Tracer
+}
+
+// WithTracerProvider instruments external checks (breach lookups) with spans
+// from tp, recording the result, latency, any error and how the failure mode
+// handled it.
+func WithTracerProvider(tp TracerProvider) Option {
+	return func(v *PasswordValidator) {
+		if tp != nil {
+			v.tracer = tp.Tracer(tracerName)
+		}
+	}
+}
+
+// startSpan starts a span for an external check, or a no-op span when no
+// tracer is configured.
+func (v *PasswordValidator) startSpan(ctx context.Context, name string) (context.Context, Span) {
+	if v.tracer == nil {
+		return ctx, noopSpan{}
+	}
+	return v.tracer.Start(ctx, name)
+}
+
+// endSpan records the common attributes of an external check and ends the span.
+func endSpan(span Span, start time.Time, err error) {
+	span.SetAttribute("passval.latency_ms", time.Since(start).Milliseconds())
+	if err != nil {
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, any) {}
+func (noopSpan) RecordError(error)        {}
+func (noopSpan) End()                     {}
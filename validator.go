@@ -3,8 +3,14 @@ package passval
 import (
 	"crypto/rand"
 	"fmt"
-	"math/big"
+	"io"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
 	"strings"
+	"sync/atomic"
+	"time"
 	"unicode"
 )
 
@@ -13,18 +19,127 @@ type PenaltyDetail struct {
 	Rule   string  // e.g. "repeated_chars", "common_password", "keyboard_pattern"
 	Factor float64 // multiplicative factor applied (e.g. 0.5)
 	Desc   string  // human-readable description
+	Match  string  // matched common password or dictionary word, if any
+	// Start and End are the byte offsets of the offending part of the password,
+	// password[Start:End], for highlighting in UIs. Penalties about the password
+	// as a whole span all of it.
+	Start, End int
+	// Reference is the stable identifier of the penalty's explanation, e.g.
+	// "penalty/keyboard-pattern"; see PenaltyInfo.
+	Reference string
+}
+
+// Rule codes identify failed rules in a stable, machine-readable way.
+const (
+	RuleTooShort         = "too_short"
+	RuleTooLong          = "too_long"
+	RuleTooManyBytes     = "too_many_bytes"
+	RuleMissingLower     = "missing_lower"
+	RuleMissingUpper     = "missing_upper"
+	RuleMissingNumber    = "missing_number"
+	RuleMissingSymbol    = "missing_symbol"
+	RuleMinLower         = "min_lower"
+	RuleMinUpper         = "min_upper"
+	RuleMinDigits        = "min_digits"
+	RuleMinSymbols       = "min_symbols"
+	RuleMinCharClasses   = "min_char_classes"
+	RuleMinUniqueChars   = "min_unique_chars"
+	RuleStructure        = "structure"
+	RuleNonASCII         = "non_ascii"
+	RuleNonPrintable     = "non_printable"
+	RuleDisallowedSymbol = "disallowed_symbol"
+	RuleDisallowedChar   = "disallowed_char"
+	RuleBannedSubstring  = "banned_substring"
+	RuleSiteTerm         = "site_term"
+	RuleBannedPattern    = "banned_pattern"
+	RuleTooEasyToGuess   = "too_easy_to_guess"
+	RuleComplexity       = "complexity"
+	RuleBreached         = "breached"
+	RuleBreachUnchecked  = "breach_unchecked"
+	RuleDenylisted       = "denylisted"
+	RuleTooSimilar       = "too_similar"
+	RuleIncremented      = "incremented"
+	RulePINNotNumeric    = "pin_not_numeric"
+)
+
+// passwordRules lists the Rule* codes a password validator can report.
+var passwordRules = []string{
+	RuleTooShort, RuleTooLong, RuleTooManyBytes,
+	RuleMissingLower, RuleMissingUpper, RuleMissingNumber, RuleMissingSymbol,
+	RuleMinLower, RuleMinUpper, RuleMinDigits, RuleMinSymbols, RuleMinCharClasses,
+	RuleMinUniqueChars, RuleStructure, RuleNonASCII, RuleNonPrintable,
+	RuleDisallowedSymbol, RuleDisallowedChar, RuleBannedSubstring, RuleSiteTerm, RuleBannedPattern,
+	RuleTooEasyToGuess, RuleComplexity, RuleBreached, RuleBreachUnchecked, RuleDenylisted,
+	RuleTooSimilar, RuleIncremented,
+}
+
+// ReasonCodes returns every code Result.Codes can report for a password: the
+// Rule* codes of failed rules followed by the identifiers of penalties. Use it
+// to document APIs, e.g. as an enum in a schema.
+func ReasonCodes() []string {
+	return slices.Concat(passwordRules, penaltyRules)
+}
+
+// RuleFail is a failed rule: its Rule* code, the values the message was
+// built from, and the message in English. Params holds e.g. "min" and
+// "actual" for RuleTooShort, so callers can branch on Code and write their
+// own copy ("Use at least {min} characters") instead of showing Message.
+type RuleFail struct {
+	Code    string         `json:"code"`
+	Params  map[string]any `json:"params,omitempty"`
+	Message string         `json:"message"` // e.g. "too short: minimum 8 characters"
+}
+
+// String returns the message, so fails print as the plain descriptions they
+// replace.
+func (f RuleFail) String() string {
+	return f.Message
 }
 
 // ValidationError holds all penalty details when validation fails or penalties are applied.
 type ValidationError struct {
 	Penalties []PenaltyDetail
-	RuleFails []string // e.g. "missing uppercase", "too short"
+	RuleFails []RuleFail
+}
+
+// newRuleFail returns the failed rule with the given code, message and
+// params, given as alternating names and values.
+func newRuleFail(code, msg string, params ...any) RuleFail {
+	f := RuleFail{Code: code, Message: msg}
+	if len(params) > 0 {
+		f.Params = make(map[string]any, len(params)/2)
+		for i := 0; i+1 < len(params); i += 2 {
+			f.Params[params[i].(string)] = params[i+1]
+		}
+	}
+	return f
+}
+
+// fail records a failed rule with its reason code, message and params, given
+// as alternating names and values.
+func (e *ValidationError) fail(code, msg string, params ...any) {
+	e.RuleFails = append(e.RuleFails, newRuleFail(code, msg, params...))
+}
+
+// Codes returns the reason codes of the failed rules (see the Rule* constants)
+// followed by the rule identifiers of the applied penalties.
+func (e *ValidationError) Codes() []string {
+	codes := make([]string, 0, len(e.RuleFails)+len(e.Penalties))
+	for _, f := range e.RuleFails {
+		if f.Code != "" { // fails built outside passval may have none
+			codes = append(codes, f.Code)
+		}
+	}
+	for _, p := range e.Penalties {
+		codes = append(codes, p.Rule)
+	}
+	return codes
 }
 
 func (e *ValidationError) Error() string {
 	var parts []string
-	for _, r := range e.RuleFails {
-		parts = append(parts, fmt.Sprintf("rule: %s", r))
+	for _, f := range e.RuleFails {
+		parts = append(parts, fmt.Sprintf("rule: %s", f.Message))
 	}
 	for _, p := range e.Penalties {
 		parts = append(parts, fmt.Sprintf("penalty(%s, x%.2f): %s", p.Rule, p.Factor, p.Desc))
@@ -33,6 +148,12 @@ func (e *ValidationError) Error() string {
 }
 
 // PasswordValidator holds the configuration for password validation and generation.
+//
+// A validator is immutable once built and safe for concurrent use. Its
+// exported fields are for reading only: derive a modified policy with Clone,
+// WithComplexity or WithMinLength instead of assigning to them, which would
+// race with concurrent validations. The dictionary is the one exception: it
+// can be replaced at any time with SetDictionary.
 type PasswordValidator struct {
 	MinLength      int
 	MaxLength      int
@@ -41,20 +162,103 @@ type PasswordValidator struct {
 	RequireNumbers bool
 	RequireSymbols bool
 	Complexity     int // minimum complexity score 0-100
+	WarnThreshold  int // passing scores below this produce a warning (0 = disabled)
+
+	// Minimum number of characters required from each class (0 = no minimum).
+	// A minimum greater than zero implies the class is required.
+	MinLower   int
+	MinUpper   int
+	MinDigits  int
+	MinSymbols int
 
-	dict *dictionary
+	dict        *atomic.Pointer[dictionary] // swapped by SetDictionary
+	entropyMode EntropyMode
+	minGuesses  float64
+
+	passphraseMinWords   int
+	passphraseMinWordLen int
+	wordlist             Wordlist
+
+	excludeChars   string
+	genNoPenalties bool
+	allowedSymbols string
+	forbiddenChars string
+	minCharClasses int
+	minUnique      int
+	exemptLength   int
+	tokenBits      float64
+	changeDistance int
+	strengthLevels []StrengthLevel
+	bannedTerms    []bannedTerm
+	bannedIndex    *bannedIndex // built by Compile
+	siteTerms      []bannedTerm
+	bannedPatterns []*regexp.Regexp
+	structureRules []StructureRule
+	presets        []Preset
+	maxBytes       int
+	penaltyLimit   int
+	maxAnalyzed    int
+	maxDictWords   int
+	dictMinMatch   int
+	penaltyBudget  time.Duration
+	asciiOnly      bool
+	printableOnly  bool
+	warnOnly       map[string]bool
+	skipPenalties  map[string]bool
+	metrics        Metrics
+	tracer         Tracer
+	breachChecker  BreachChecker
+	breachMode     BreachFailureMode
+	breachTimeout  time.Duration
+	breaker        *circuitBreaker
+	randSource     io.Reader
+	profanity      []bannedTerm
+	leet           leetTable
+	redact         bool
+	singleClass    float64
+	segments       bool
+	debugTrace     bool
+	graphemes      bool
+	localeHints    []string
+	dateOrders     dateOrder
+	topics         []Topic
+	topical        *topicalWords
+	englishWords   bool
+	languageWords  []string
+	language       *dictionary
+	events         EventSink
+	policyVersion  string
+}
+
+// Validator validates passwords against a policy. *PasswordValidator
+// implements it, as do the All and Any combinators. Depend on Validator rather
+// than the concrete type to mock validation in tests or to wrap it with
+// logging or metrics decorators.
+type Validator interface {
+	Validate(password string) (bool, int)
+	ValidateVerbose(password string) (bool, int, error)
+	ValidateResult(password string) *Result
 }
 
+var _ Validator = (*PasswordValidator)(nil)
+
+// NoMax as the maximum length accepts passwords of any length, as NIST SP
+// 800-63B recommends; WithMaxBytes and WithAnalysisLimits still bound the
+// work and storage spent on very long input.
+const NoMax = 0
+
 // NewPasswordValidator creates a new validator with the given rules.
-// complexity is the minimum acceptable score on a 0-100 scale.
-func NewPasswordValidator(min, max int, lower, upper, numbers, symbols bool, complexity int) *PasswordValidator {
-	return NewPasswordValidatorWithDict(min, max, lower, upper, numbers, symbols, complexity, "")
+// complexity is the minimum acceptable score on a 0-100 scale. A max of NoMax
+// (or below) leaves the length unlimited.
+// Optional behaviour can be configured with opts.
+func NewPasswordValidator(min, max int, lower, upper, numbers, symbols bool, complexity int, opts ...Option) *PasswordValidator {
+	return NewPasswordValidatorWithDict(min, max, lower, upper, numbers, symbols, complexity, "", opts...)
 }
 
 // NewPasswordValidatorWithDict creates a new validator with custom dictionary data.
 // If customDict is empty, uses the embedded sample dictionary.
 // customDict should be a string with one password per line.
-func NewPasswordValidatorWithDict(min, max int, lower, upper, numbers, symbols bool, complexity int, customDict string) *PasswordValidator {
+func NewPasswordValidatorWithDict(min, max int, lower, upper, numbers, symbols bool, complexity int, customDict string, opts ...Option) *PasswordValidator {
 	if complexity < 0 {
 		complexity = 0
 	}
@@ -64,15 +268,17 @@ func NewPasswordValidatorWithDict(min, max int, lower, upper, numbers, symbols b
 	if min < 1 {
 		min = 1
 	}
-	if max < min {
+	if max <= NoMax {
+		max = NoMax
+	} else if max < min {
 		max = min
 	}
 
-	var dict *dictionary
+	dict := new(atomic.Pointer[dictionary])
 	if customDict != "" {
-		dict = loadDictionary(customDict)
+		dict.Store(loadDictionary(customDict))
 	} else {
-		dict = globalDict
+		dict.Store(globalDict)
 	}
 
 	v := &PasswordValidator{
@@ -84,59 +290,256 @@ func NewPasswordValidatorWithDict(min, max int, lower, upper, numbers, symbols b
 		RequireSymbols: symbols,
 		Complexity:     complexity,
 		dict:           dict,
+		profanity:      defaultProfanity,
+		leet:           leetMap,
+		changeDistance: defaultChangeDistance,
+	}
+	for _, opt := range opts {
+		opt(v)
 	}
 	return v
 }
 
+// Clone returns a copy of the validator with opts applied to the copy only,
+// leaving v unchanged.
+func (v *PasswordValidator) Clone(opts ...Option) *PasswordValidator {
+	c := *v
+	c.dict = new(atomic.Pointer[dictionary])
+	c.dict.Store(v.dictionary())
+	c.bannedTerms = append([]bannedTerm(nil), v.bannedTerms...)
+	c.siteTerms = append([]bannedTerm(nil), v.siteTerms...)
+	c.bannedPatterns = append([]*regexp.Regexp(nil), v.bannedPatterns...)
+	c.profanity = append([]bannedTerm(nil), v.profanity...)
+	c.localeHints = slices.Clone(v.localeHints)
+	c.topics = slices.Clone(v.topics)
+	c.languageWords = slices.Clone(v.languageWords)
+	c.strengthLevels = slices.Clone(v.strengthLevels)
+	c.structureRules = slices.Clone(v.structureRules)
+	c.presets = slices.Clone(v.presets)
+	if v.warnOnly != nil {
+		c.warnOnly = make(map[string]bool, len(v.warnOnly))
+		for code := range v.warnOnly {
+			c.warnOnly[code] = true
+		}
+	}
+	if v.skipPenalties != nil {
+		c.skipPenalties = maps.Clone(v.skipPenalties)
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return &c
+}
+
+// SetDictionary atomically replaces the common passwords dictionary with data,
+// one password per line; empty data restores the embedded dictionary. It is
+// safe to call while other goroutines validate, e.g. to hot-reload a list.
+func (v *PasswordValidator) SetDictionary(data string) {
+	if data == "" {
+		v.dict.Store(globalDict)
+		return
+	}
+	v.dict.Store(loadDictionary(data))
+}
+
+// dictionary returns the current dictionary, or nil for a validator not built
+// by a constructor.
+func (v *PasswordValidator) dictionary() *dictionary {
+	if v.dict == nil {
+		return nil
+	}
+	return v.dict.Load()
+}
+
+// WithComplexity returns a copy of the validator with the given minimum
+// complexity score, clamped to 0-100.
+func (v *PasswordValidator) WithComplexity(complexity int) *PasswordValidator {
+	c := v.Clone()
+	c.Complexity = min(max(complexity, 0), 100)
+	return c
+}
+
+// WithMinLength returns a copy of the validator with the given minimum length,
+// raising a finite MaxLength if needed.
+func (v *PasswordValidator) WithMinLength(n int) *PasswordValidator {
+	c := v.Clone()
+	c.MinLength = max(n, 1)
+	if c.MaxLength != NoMax && c.MaxLength < c.MinLength {
+		c.MaxLength = c.MinLength
+	}
+	return c
+}
+
 // Validate returns whether the password passes all rules and the computed complexity score (0-100).
 func (v *PasswordValidator) Validate(password string) (bool, int) {
-	pass, score, _ := v.validate(password)
-	return pass, score
+	r := v.observe(password)
+	return r.Pass, r.Score
 }
 
 // ValidateVerbose returns pass/fail, the complexity score, and a *ValidationError
 // detailing which rules failed and which penalties were applied.
 // error is nil only if the password passes all rules AND meets the complexity threshold.
 func (v *PasswordValidator) ValidateVerbose(password string) (bool, int, error) {
-	pass, score, vErr := v.validate(password)
-	if pass {
-		return true, score, nil
+	r := v.observe(password)
+	return r.Pass, r.Score, r.Err()
+}
+
+// ValidateResult returns the full validation outcome, including soft warnings
+// for passwords that pass but should be improved.
+func (v *PasswordValidator) ValidateResult(password string) *Result {
+	return v.observe(password)
+}
+
+// ValidateBytes is like ValidateResult for a password held in a byte slice, for
+// callers that zero their secrets after use. The password is read in place
+// rather than copied into a string, working copies made for analysis are
+// zeroed before returning, and penalties carry only redacted descriptions and
+// no Match. password must not be modified during the call.
+func (v *PasswordValidator) ValidateBytes(password []byte) *Result {
+	a := analyzeSecret(password, v.leet)
+	defer a.wipe()
+	if v.graphemes {
+		a.useGraphemes()
 	}
-	return false, score, vErr
+	r := v.validateAnalysis(a, true)
+	v.report(r)
+	return r
+}
+
+func (v *PasswordValidator) validate(password string) *Result {
+	return v.validateAnalysis(v.analyze(password), false)
 }
 
-func (v *PasswordValidator) validate(password string) (bool, int, *ValidationError) {
+// validateAnalysis validates the analysed password. For a secret password,
+// penalty descriptions are redacted and Match values dropped so that the
+// Result holds no part of it.
+func (v *PasswordValidator) validateAnalysis(a *analysis, secret bool) *Result {
+	var deadline time.Time
+	if v.penaltyBudget > 0 {
+		deadline = time.Now().Add(v.penaltyBudget)
+	}
 	vErr := &ValidationError{}
 
 	// --- Rule checks ---
-	if len(password) < v.MinLength {
-		vErr.RuleFails = append(vErr.RuleFails, fmt.Sprintf("too short: minimum %d characters", v.MinLength))
+	// Length rules apply to the full input, everything else to the analyzed part.
+	length, size := a.length(), len(a.password)
+	full := a
+	if v.maxAnalyzed > 0 && len(a.runes) > v.maxAnalyzed {
+		a = a.prefix(a.offsets[v.maxAnalyzed])
+		defer a.wipe()
+	}
+	password := a.password
+
+	if length < v.MinLength {
+		vErr.fail(RuleTooShort, fmt.Sprintf("too short: minimum %d characters", v.MinLength), "min", v.MinLength, "actual", length)
+	}
+	if v.MaxLength != NoMax && length > v.MaxLength {
+		vErr.fail(RuleTooLong, fmt.Sprintf("too long: maximum %d characters", v.MaxLength), "max", v.MaxLength, "actual", length)
 	}
-	if len(password) > v.MaxLength {
-		vErr.RuleFails = append(vErr.RuleFails, fmt.Sprintf("too long: maximum %d characters", v.MaxLength))
+	if v.maxBytes > 0 && size > v.maxBytes {
+		vErr.fail(RuleTooManyBytes, fmt.Sprintf("too long: %d bytes exceeds the %d-byte limit", size, v.maxBytes), "max", v.maxBytes, "actual", size)
 	}
 
-	hasLower, hasUpper, hasNumber, hasSymbol := charClasses(password)
+	lowerCount, upperCount, numberCount, symbolCount := a.lowerCount, a.upperCount, a.numberCount, a.symbolCount
 
-	if v.RequireLower && !hasLower {
-		vErr.RuleFails = append(vErr.RuleFails, "missing lowercase letter")
+	// Passphrases and long passwords are exempt from number, symbol and
+	// character class requirements, random tokens from all composition rules.
+	_, isPassphrase := v.passphraseWords(password)
+	exempt := isPassphrase || (v.exemptLength > 0 && length >= v.exemptLength)
+	var token bool
+	var tokenBits float64
+	if v.tokenBits > 0 {
+		tokenBits, token = randomTokenBits(password)
+		token = token && tokenBits >= v.tokenBits
+		exempt = exempt || token
 	}
-	if v.RequireUpper && !hasUpper {
-		vErr.RuleFails = append(vErr.RuleFails, "missing uppercase letter")
+
+	if f, failed := lowerClassRule.check(lowerCount, v.MinLower, v.RequireLower); failed && !token {
+		vErr.RuleFails = append(vErr.RuleFails, f)
+	}
+	if f, failed := upperClassRule.check(upperCount, v.MinUpper, v.RequireUpper); failed && !token {
+		vErr.RuleFails = append(vErr.RuleFails, f)
 	}
-	if v.RequireNumbers && !hasNumber {
-		vErr.RuleFails = append(vErr.RuleFails, "missing number")
+	if f, failed := numberClassRule.check(numberCount, v.MinDigits, v.RequireNumbers); failed && !exempt {
+		vErr.RuleFails = append(vErr.RuleFails, f)
 	}
-	if v.RequireSymbols && !hasSymbol {
-		vErr.RuleFails = append(vErr.RuleFails, "missing symbol")
+	if f, failed := symbolClassRule.check(symbolCount, v.MinSymbols, v.RequireSymbols); failed && !exempt {
+		vErr.RuleFails = append(vErr.RuleFails, f)
+	}
+	if v.minCharClasses > 0 && !exempt {
+		if n := classesPresent(lowerCount, upperCount, numberCount, symbolCount); n < v.minCharClasses {
+			vErr.fail(RuleMinCharClasses, fmt.Sprintf("too few character classes: %d of 4, minimum %d", n, v.minCharClasses), "min", v.minCharClasses, "actual", n)
+		}
+	}
+	if v.minUnique > 0 && a.uniqueRunes < v.minUnique {
+		vErr.fail(RuleMinUniqueChars, fmt.Sprintf("too few unique characters: %d, minimum %d", a.uniqueRunes, v.minUnique), "min", v.minUnique, "actual", a.uniqueRunes)
+	}
+	if v.asciiOnly {
+		if n, at := countRunes(password, isNonASCII); n > 0 {
+			vErr.fail(RuleNonASCII, fmt.Sprintf("%d non-ASCII characters not allowed (first at position %d)", n, at), "count", n, "position", at)
+		}
+	}
+	if v.printableOnly {
+		if n, at := countRunes(password, isNonPrintable); n > 0 {
+			vErr.fail(RuleNonPrintable, fmt.Sprintf("%d non-printable characters not allowed (first at position %d)", n, at), "count", n, "position", at)
+		}
+	}
+	// Secret passwords are described by count and position, never by the
+	// offending characters.
+	if v.allowedSymbols != "" {
+		if secret {
+			if n, at := countRunes(password, func(r rune) bool { return isSymbol(r) && !strings.ContainsRune(v.allowedSymbols, r) }); n > 0 {
+				vErr.fail(RuleDisallowedSymbol, fmt.Sprintf("%d symbols not allowed (first at position %d; allowed: %s)", n, at, v.allowedSymbols), "count", n, "position", at, "allowed", v.allowedSymbols)
+			}
+		} else if bad := disallowedSymbols(password, v.allowedSymbols); bad != "" {
+			vErr.fail(RuleDisallowedSymbol, fmt.Sprintf("symbols not allowed: %s (allowed: %s)", bad, v.allowedSymbols), "symbols", bad, "allowed", v.allowedSymbols)
+		}
+	}
+	if v.forbiddenChars != "" {
+		if secret {
+			if n, at := countRunes(password, func(r rune) bool { return strings.ContainsRune(v.forbiddenChars, r) }); n > 0 {
+				vErr.fail(RuleDisallowedChar, fmt.Sprintf("%d characters not allowed (first at position %d)", n, at), "count", n, "position", at, "disallowed", v.forbiddenChars)
+			}
+		} else if bad := forbiddenChars(password, v.forbiddenChars); bad != "" {
+			vErr.fail(RuleDisallowedChar, "characters not allowed: "+describeChars(bad), "chars", bad, "disallowed", v.forbiddenChars)
+		}
 	}
 
+	checkBannedTerms(vErr, a, v.bannedTerms, v.bannedIndex)
+	checkSiteTerms(vErr, a, v.siteTerms)
+	checkBannedPatterns(vErr, password, v.bannedPatterns)
+	checkStructureRules(vErr, password, v.structureRules)
+
 	// --- Entropy + penalties ---
-	entropy := calculateEntropy(password)
+	// Random tokens are scored on their measured randomness: the patterns
+	// detectors find in hex digits (low diversity, dates, digit runs) are
+	// chance.
+	entropy := v.entropy(a)
+	if token {
+		entropy = tokenBits
+	}
 	score := entropyToScore(entropy)
 
-	penalties := detectPenalties(password, v.dict)
+	scanned := a
+	if v.penaltyLimit > 0 && len(password) > v.penaltyLimit {
+		scanned = a.prefix(v.penaltyLimit)
+		defer scanned.wipe()
+	}
+	var trace *DebugTrace
+	if v.debugTrace {
+		trace = &DebugTrace{BaseScore: score}
+	}
+	var penalties []PenaltyDetail
+	complete := true
+	if !token {
+		cfg := v.penaltyConfig(isPassphrase, secret)
+		cfg.trace = trace
+		penalties, complete = detectPenaltiesUntil(scanned, v.dictionary(), cfg, deadline)
+	}
 	for _, p := range penalties {
+		if secret {
+			p.Match = ""
+		}
 		score = int(float64(score) * p.Factor)
 		vErr.Penalties = append(vErr.Penalties, p)
 	}
@@ -148,74 +551,410 @@ func (v *PasswordValidator) validate(password string) (bool, int, *ValidationErr
 		score = 100
 	}
 
-	rulesPass := len(vErr.RuleFails) == 0
-	complexityPass := score >= v.Complexity
-	pass := rulesPass && complexityPass
+	if v.minGuesses > 0 {
+		if guesses := estimateGuesses(entropy, score); guesses < v.minGuesses {
+			vErr.fail(RuleTooEasyToGuess, fmt.Sprintf("too easy to guess: estimated %.1e guesses, minimum %.1e", guesses, v.minGuesses), "min", v.minGuesses, "actual", guesses)
+		}
+	}
+
+	// Degenerate passwords, which no entropy estimate credits, are floored by
+	// length, after estimating guesses, which must not be inflated by it.
+	// Penalized passwords keep their score.
+	if entropy == 0 {
+		score = max(score, lengthScore(length))
+	}
 
-	if !complexityPass {
-		vErr.RuleFails = append(vErr.RuleFails, fmt.Sprintf("complexity %d below threshold %d", score, v.Complexity))
+	if score < v.Complexity {
+		vErr.fail(RuleComplexity, fmt.Sprintf("complexity %d below threshold %d", score, v.Complexity), "min", v.Complexity, "actual", score)
 	}
 
-	return pass, score, vErr
+	r := &Result{
+		Score:    score,
+		Strength: v.StrengthLabel(score),
+		Entropy:  entropy,
+		err:      vErr,
+	}
+	r.Warnings = v.demoteRuleFails(vErr)
+	r.Pass = len(vErr.RuleFails) == 0
+	r.RuleFails = vErr.RuleFails
+	r.Penalties = vErr.Penalties
+	r.Warnings = append(r.Warnings, v.warnings(password, length, r)...)
+	if a != full {
+		r.Warnings = append(r.Warnings, Warning{
+			Code:    WarnTruncated,
+			Message: fmt.Sprintf("analysis covered only the first %d of %d characters", len(a.runes), len(full.runes)),
+		})
+	}
+	if scanned != a {
+		r.Warnings = append(r.Warnings, Warning{
+			Code:    WarnPartialScan,
+			Message: fmt.Sprintf("pattern checks covered only the first %d of %d bytes", len(scanned.password), len(password)),
+		})
+	}
+	if !complete {
+		r.Warnings = append(r.Warnings, Warning{
+			Code:    WarnPartialScan,
+			Message: "pattern checks stopped early: time budget exceeded",
+		})
+	}
+	if v.segments {
+		r.Segments = segmentPassword(password, penalties, v.dictionary(), secret)
+	}
+	if trace != nil && secret {
+		trace.redact()
+	}
+	r.Debug = trace
+	return r
+}
+
+// entropy computes the raw entropy bits of password using the configured estimator.
+// Passphrases are measured in words rather than characters.
+func (v *PasswordValidator) entropy(a *analysis) float64 {
+	password := a.password
+	if words, ok := v.passphraseWords(password); ok {
+		return passphraseEntropy(words, v.passphraseListSize())
+	}
+	if a.clusters != nil {
+		return clusterEntropy(a, v.entropyMode)
+	}
+	if v.entropyMode == EntropyShannon {
+		return calculateShannonEntropy(password)
+	}
+	return calculateEntropy(password)
 }
 
 // Generate creates a random password that satisfies all configured rules and the complexity threshold.
 // It retries until a valid password is produced (max 1000 attempts).
 func (v *PasswordValidator) Generate() (string, error) {
-	const maxAttempts = 1000
+	pwd, _, err := v.generate(v.MinLength, v.generationMax())
+	return pwd, err
+}
+
+// MustGenerate is like Generate but panics if no password can be generated,
+// for tests and setup code where the policy is known to be satisfiable.
+func (v *PasswordValidator) MustGenerate() string {
+	pwd, err := v.Generate()
+	if err != nil {
+		panic("passval: MustGenerate: " + err.Error())
+	}
+	return pwd
+}
+
+// GeneratedPassword is a generated password together with its evaluation.
+type GeneratedPassword struct {
+	Password string
+	Score    int     // complexity score 0-100 after penalties
+	Entropy  float64 // raw entropy bits before penalties
+	Strength string  // label of Score on the validator's strength scale
+	// Warnings holds a WarnConfusable warning if the password contains
+	// characters easily confused when read, such as O and 0.
+	Warnings []Warning
+}
 
-	for i := 0; i < maxAttempts; i++ {
-		pwd := v.generateCandidate()
-		if pass, _ := v.Validate(pwd); pass {
-			return pwd, nil
+// GenerateWithInfo is like Generate but also returns the score, entropy and
+// strength label of the generated password.
+func (v *PasswordValidator) GenerateWithInfo() (*GeneratedPassword, error) {
+	pwd, res, err := v.generate(v.MinLength, v.generationMax())
+	if err != nil {
+		return nil, err
+	}
+	return &GeneratedPassword{
+		Password: pwd,
+		Score:    res.Score,
+		Entropy:  res.Entropy,
+		Strength: res.Strength,
+		Warnings: confusableWarnings(pwd),
+	}, nil
+}
+
+// GenerateN creates count passwords that satisfy the policy, stopping at the
+// first generation error.
+func (v *PasswordValidator) GenerateN(count int) ([]string, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("count must not be negative, got %d", count)
+	}
+	pwds := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		pwd, _, err := v.generate(v.MinLength, v.generationMax())
+		if err != nil {
+			return nil, err
 		}
+		pwds = append(pwds, pwd)
 	}
-	return "", fmt.Errorf("failed to generate a valid password after %d attempts", maxAttempts)
+	return pwds, nil
 }
 
-func (v *PasswordValidator) generateCandidate() string {
-	// Pick a length between min and max, biased toward longer for higher complexity
-	length := v.MinLength
-	if v.MaxLength > v.MinLength {
-		diff := v.MaxLength - v.MinLength
-		n, _ := rand.Int(rand.Reader, big.NewInt(int64(diff+1)))
-		length = v.MinLength + int(n.Int64())
+// GenerateWithEntropy creates a password with at least minBits of entropy,
+// choosing the length from the size of the generation charset rather than
+// from MinLength and MaxLength. It fails if the required length exceeds MaxLength.
+func (v *PasswordValidator) GenerateWithEntropy(minBits float64) (string, error) {
+	if err := v.checkGenerationCharsets(); err != nil {
+		return "", err
+	}
+	charset, _ := v.generationPlan()
+	if len(charset) < 2 {
+		return "", fmt.Errorf("generation charset has %d characters, cannot reach %.0f bits", len(charset), minBits)
+	}
+	length := int(math.Ceil(minBits / math.Log2(float64(len(charset)))))
+	if length < v.MinLength {
+		length = v.MinLength
+	}
+	if v.MaxLength != NoMax && length > v.MaxLength {
+		return "", fmt.Errorf("%.0f bits need %d characters, more than maximum length %d", minBits, length, v.MaxLength)
+	}
+	pwd, _, err := v.generate(length, length)
+	return pwd, err
+}
+
+// generate returns a random password of minLen to maxLen characters that
+// passes validation, free of penalties with WithGenerateNoPenalties, and its
+// Result.
+func (v *PasswordValidator) generate(minLen, maxLen int) (string, *Result, error) {
+	if err := v.checkGenerationCharsets(); err != nil {
+		return "", nil, err
+	}
+
+	for i := 0; i < maxGenerateAttempts; i++ {
+		pwd, err := v.generateCandidate(minLen, maxLen)
+		if err != nil {
+			err = fmt.Errorf("reading random source: %w", err)
+			v.observeGeneration(i+1, err)
+			return "", nil, err
+		}
+		if findBannedTerm(pwd, v.profanity, v.leet) != "" {
+			continue
+		}
+		if res := v.validate(pwd); v.acceptGenerated(res) {
+			v.observeGeneration(i+1, nil)
+			return pwd, res, nil
+		}
+	}
+	err := fmt.Errorf("failed to generate a valid password after %d attempts", maxGenerateAttempts)
+	v.observeGeneration(maxGenerateAttempts, err)
+	return "", nil, err
+}
+
+// GenerateInto fills buf with a random password of len(buf) characters that
+// satisfies the policy, for callers that zero their secrets after use. No
+// string copy of the password is made, and rejected candidates and working
+// copies are overwritten or zeroed; on error buf is zeroed.
+func (v *PasswordValidator) GenerateInto(buf []byte) error {
+	if len(buf) < v.MinLength {
+		return fmt.Errorf("buffer length %d below minimum length %d", len(buf), v.MinLength)
+	}
+	if v.MaxLength != NoMax && len(buf) > v.MaxLength {
+		return fmt.Errorf("buffer length %d outside allowed length %d-%d", len(buf), v.MinLength, v.MaxLength)
+	}
+	if err := v.checkGenerationCharsets(); err != nil {
+		return err
+	}
+	if _, required := v.generationPlan(); len(required) > len(buf) {
+		return fmt.Errorf("buffer length %d too short for %d required character classes", len(buf), len(required))
 	}
 
-	// Build the charset
-	const (
-		lowerChars  = "abcdefghijklmnopqrstuvwxyz"
-		upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
-		numberChars = "0123456789"
-		symbolChars = "!@#$%^&*()-_=+[]{}|;:',.<>?/`~"
-	)
+	for i := 0; i < maxGenerateAttempts; i++ {
+		if err := v.fillCandidate(v.random(), buf); err != nil {
+			clear(buf)
+			err = fmt.Errorf("reading random source: %w", err)
+			v.observeGeneration(i+1, err)
+			return err
+		}
+		if findBannedTerm(bytesView(buf), v.profanity, v.leet) != "" {
+			continue
+		}
+		a := analyzeSecret(buf, v.leet)
+		res := v.validateAnalysis(a, true)
+		a.wipe()
+		if v.acceptGenerated(res) {
+			v.observeGeneration(i+1, nil)
+			return nil
+		}
+	}
+	clear(buf)
+	err := fmt.Errorf("failed to generate a valid password after %d attempts", maxGenerateAttempts)
+	v.observeGeneration(maxGenerateAttempts, err)
+	return err
+}
+
+// acceptGenerated reports whether a candidate with result res can be
+// returned: it passes the policy and, with WithGenerateNoPenalties, no
+// penalty applies to it.
+func (v *PasswordValidator) acceptGenerated(res *Result) bool {
+	return res.Pass && (!v.genNoPenalties || len(res.Penalties) == 0)
+}
+
+// maxGenerateAttempts bounds the candidates tried by a single generation.
+const maxGenerateAttempts = 1000
+
+// noMaxGenerateLength is the longest password generated when the maximum
+// length is NoMax.
+const noMaxGenerateLength = 64
+
+// generationMax returns the longest password to generate: MaxLength, or with
+// NoMax the longest of noMaxGenerateLength, MinLength and the length the
+// per-class and unique-character minimums need.
+func (v *PasswordValidator) generationMax() int {
+	if v.MaxLength != NoMax {
+		return v.MaxLength
+	}
+	classes := minClassCount(v.MinLower, v.RequireLower) + minClassCount(v.MinUpper, v.RequireUpper) +
+		minClassCount(v.MinDigits, v.RequireNumbers) + minClassCount(v.MinSymbols, v.RequireSymbols)
+	return max(noMaxGenerateLength, v.MinLength, v.minUnique, classes)
+}
+
+// Character sets used for generation.
+const (
+	lowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	numberChars = "0123456789"
+	symbolChars = "!@#$%^&*()-_=+[]{}|;:',.<>?/`~"
+)
+
+// generationCharsets returns the per-class character sets used by the generator,
+// with any excluded characters removed.
+func (v *PasswordValidator) generationCharsets() (lower, upper, number, symbol string) {
+	symbols := symbolChars
+	if v.allowedSymbols != "" {
+		symbols = v.allowedSymbols
+	}
+	return removeChars(lowerChars, v.excludeChars),
+		removeChars(upperChars, v.excludeChars),
+		removeChars(numberChars, v.excludeChars),
+		removeChars(symbols, v.excludeChars)
+}
+
+// checkGenerationCharsets reports an error if exclusions leave a required
+// character class, or the whole charset, empty.
+func (v *PasswordValidator) checkGenerationCharsets() error {
+	lowerSet, upperSet, numberSet, symbolSet := v.generationCharsets()
+	lowerMin := minClassCount(v.MinLower, v.RequireLower)
+	upperMin := minClassCount(v.MinUpper, v.RequireUpper)
+	numberMin := minClassCount(v.MinDigits, v.RequireNumbers)
+	symbolMin := minClassCount(v.MinSymbols, v.RequireSymbols)
+
+	switch {
+	case lowerMin > 0 && lowerSet == "":
+		return fmt.Errorf("no lowercase characters left to generate after exclusions")
+	case upperMin > 0 && upperSet == "":
+		return fmt.Errorf("no uppercase characters left to generate after exclusions")
+	case numberMin > 0 && numberSet == "":
+		return fmt.Errorf("no numbers left to generate after exclusions")
+	case symbolMin > 0 && symbolSet == "":
+		return fmt.Errorf("no symbols left to generate after exclusions")
+	case lowerSet+upperSet+numberSet+symbolSet == "":
+		return fmt.Errorf("no characters left to generate after exclusions")
+	}
+	if v.minCharClasses > 0 {
+		available := 0
+		for _, set := range []string{lowerSet, upperSet, numberSet, symbolSet} {
+			if set != "" {
+				available++
+			}
+		}
+		if available < v.minCharClasses {
+			return fmt.Errorf("only %d character classes left to generate, policy requires %d", available, v.minCharClasses)
+		}
+	}
+	if v.MaxLength == NoMax {
+		return nil
+	}
+	if v.minUnique > v.MaxLength {
+		return fmt.Errorf("%d unique characters required, more than maximum length %d", v.minUnique, v.MaxLength)
+	}
+	if total := lowerMin + upperMin + numberMin + symbolMin; total > v.MaxLength {
+		return fmt.Errorf("per-class minimums need %d characters, more than maximum length %d", total, v.MaxLength)
+	}
+	return nil
+}
+
+// random returns the source of randomness used for generation.
+func (v *PasswordValidator) random() io.Reader {
+	if v.randSource != nil {
+		return v.randSource
+	}
+	return rand.Reader
+}
 
-	var charset string
-	var required []string
+// generationPlan returns the charset the generator draws from and one entry
+// per character that must come from a specific class set.
+func (v *PasswordValidator) generationPlan() (charset string, required []string) {
+	lowerSet, upperSet, numberSet, symbolSet := v.generationCharsets()
 
-	if v.RequireLower {
-		charset += lowerChars
-		required = append(required, lowerChars)
+	classes := []struct {
+		set string
+		n   int
+	}{
+		{lowerSet, minClassCount(v.MinLower, v.RequireLower)},
+		{upperSet, minClassCount(v.MinUpper, v.RequireUpper)},
+		{numberSet, minClassCount(v.MinDigits, v.RequireNumbers)},
+		{symbolSet, minClassCount(v.MinSymbols, v.RequireSymbols)},
 	}
-	if v.RequireUpper {
-		charset += upperChars
-		required = append(required, upperChars)
+
+	// Pull in extra classes until the "N of 4 classes" rule is satisfied
+	used := 0
+	for _, c := range classes {
+		if c.n > 0 {
+			used++
+		}
 	}
-	if v.RequireNumbers {
-		charset += numberChars
-		required = append(required, numberChars)
+	for i := range classes {
+		if used >= v.minCharClasses {
+			break
+		}
+		if classes[i].n == 0 && classes[i].set != "" {
+			classes[i].n = 1
+			used++
+		}
 	}
-	if v.RequireSymbols {
-		charset += symbolChars
-		required = append(required, symbolChars)
+
+	for _, c := range classes {
+		if c.n == 0 {
+			continue
+		}
+		charset += c.set
+		for i := 0; i < c.n; i++ {
+			required = append(required, c.set)
+		}
 	}
 
 	// If no requirements, use all
 	if charset == "" {
-		charset = lowerChars + upperChars + numberChars + symbolChars
+		charset = lowerSet + upperSet + numberSet + symbolSet
+	}
+	return charset, required
+}
+
+func (v *PasswordValidator) generateCandidate(minLen, maxLen int) (string, error) {
+	r := v.random()
+
+	// Pick a length between min and max, biased toward longer for higher complexity
+	length := minLen
+	if maxLen > minLen {
+		n, err := randIndexFrom(r, maxLen-minLen+1)
+		if err != nil {
+			return "", err
+		}
+		length = minLen + n
+	}
+
+	_, required := v.generationPlan()
+	if len(required) > length {
+		length = len(required)
 	}
 
 	pwd := make([]byte, length)
+	if err := v.fillCandidate(r, pwd); err != nil {
+		return "", err
+	}
+	return string(pwd), nil
+}
+
+// fillCandidate fills pwd with random characters from the generation charset,
+// including one from each required class. pwd must have room for every
+// required class.
+func (v *PasswordValidator) fillCandidate(r io.Reader, pwd []byte) error {
+	length := len(pwd)
+	charset, required := v.generationPlan()
 
 	// Fill required characters first at random positions
 	positions := make([]int, length)
@@ -224,25 +963,226 @@ func (v *PasswordValidator) generateCandidate() string {
 	}
 	// Shuffle positions
 	for i := len(positions) - 1; i > 0; i-- {
-		n, _ := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
-		j := int(n.Int64())
+		j, err := randIndexFrom(r, i+1)
+		if err != nil {
+			return err
+		}
 		positions[i], positions[j] = positions[j], positions[i]
 	}
 
 	pos := 0
 	for _, req := range required {
-		n, _ := rand.Int(rand.Reader, big.NewInt(int64(len(req))))
-		pwd[positions[pos]] = req[int(n.Int64())]
+		n, err := randIndexFrom(r, len(req))
+		if err != nil {
+			return err
+		}
+		pwd[positions[pos]] = req[n]
 		pos++
 	}
 
 	// Fill remaining positions
 	for ; pos < length; pos++ {
-		n, _ := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
-		pwd[positions[pos]] = charset[int(n.Int64())]
+		n, err := randIndexFrom(r, len(charset))
+		if err != nil {
+			return err
+		}
+		pwd[positions[pos]] = charset[n]
 	}
 
-	return string(pwd)
+	return nil
+}
+
+// minClassCount returns how many characters of a class are required,
+// combining a per-class minimum with the Require* flag.
+func minClassCount(min int, required bool) int {
+	if min < 1 && required {
+		return 1
+	}
+	if min < 0 {
+		return 0
+	}
+	return min
+}
+
+// classRule describes the requirement messages and codes of a character class.
+type classRule struct {
+	name        string
+	plural      string
+	missingCode string
+	minCode     string
+}
+
+var (
+	lowerClassRule  = classRule{"lowercase letter", "lowercase letters", RuleMissingLower, RuleMinLower}
+	upperClassRule  = classRule{"uppercase letter", "uppercase letters", RuleMissingUpper, RuleMinUpper}
+	numberClassRule = classRule{"number", "numbers", RuleMissingNumber, RuleMinDigits}
+	symbolClassRule = classRule{"symbol", "symbols", RuleMissingSymbol, RuleMinSymbols}
+)
+
+// check returns the failed rule for a class with count occurrences, and
+// whether the class requirement failed.
+func (c classRule) check(count, min int, required bool) (RuleFail, bool) {
+	need := minClassCount(min, required)
+	if count >= need {
+		return RuleFail{}, false
+	}
+	params := map[string]any{"min": need, "actual": count}
+	if need > 1 {
+		return RuleFail{c.minCode, params, fmt.Sprintf("too few %s: minimum %d", c.plural, need)}, true
+	}
+	return RuleFail{c.missingCode, params, "missing " + c.name}, true
+}
+
+// requirement returns the client-side requirement for a class with the given
+// minimum, or false if the class is not required.
+func (c classRule) requirement(min int, required bool) (ClientRequirement, bool) {
+	need := minClassCount(min, required)
+	switch {
+	case need == 0:
+		return ClientRequirement{}, false
+	case need == 1:
+		return ClientRequirement{Code: c.missingCode, Min: 1, Message: "at least 1 " + c.name}, true
+	}
+	return ClientRequirement{Code: c.minCode, Min: need, Message: fmt.Sprintf("at least %d %s", need, c.plural)}, true
+}
+
+// classesPresent returns how many character classes have at least one character.
+func classesPresent(counts ...int) int {
+	n := 0
+	for _, c := range counts {
+		if c > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// removeChars returns s without any of the characters in exclude.
+func removeChars(s, exclude string) string {
+	if exclude == "" {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if !strings.ContainsRune(exclude, r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// disallowedSymbols returns the distinct symbols in password that are not in allowed.
+func disallowedSymbols(password, allowed string) string {
+	var bad []rune
+	for _, r := range password {
+		if isSymbol(r) && !strings.ContainsRune(allowed, r) && !containsRune(bad, r) {
+			bad = append(bad, r)
+		}
+	}
+	return string(bad)
+}
+
+// forbiddenChars returns the distinct characters of password that are in
+// forbidden, in order of appearance.
+func forbiddenChars(password, forbidden string) string {
+	var bad []rune
+	for _, r := range password {
+		if strings.ContainsRune(forbidden, r) && !containsRune(bad, r) {
+			bad = append(bad, r)
+		}
+	}
+	return string(bad)
+}
+
+// describeChars lists the characters of chars separated by spaces, naming
+// whitespace, which would otherwise be invisible in a message.
+func describeChars(chars string) string {
+	names := make([]string, 0, len(chars))
+	for _, r := range chars {
+		switch r {
+		case ' ':
+			names = append(names, "space")
+		case '\t':
+			names = append(names, "tab")
+		default:
+			names = append(names, string(r))
+		}
+	}
+	return strings.Join(names, " ")
+}
+
+// countRunes returns the number of runes in password for which match is true,
+// and the 1-based position of the first of them.
+func countRunes(password string, match func(rune) bool) (n, first int) {
+	pos := 0
+	for _, r := range password {
+		pos++
+		if match(r) {
+			if n == 0 {
+				first = pos
+			}
+			n++
+		}
+	}
+	return n, first
+}
+
+func isNonASCII(r rune) bool {
+	return r > unicode.MaxASCII
+}
+
+// isNonPrintable reports whether r is a control, format or unassigned
+// character, or a space other than U+0020.
+func isNonPrintable(r rune) bool {
+	return !unicode.IsPrint(r)
+}
+
+func containsRune(runes []rune, r rune) bool {
+	for _, c := range runes {
+		if c == r {
+			return true
+		}
+	}
+	return false
+}
+
+func isSymbol(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// charClassCounts counts the characters of each class in password.
+func charClassCounts(password string) (lower, upper, number, symbol int) {
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower++
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsDigit(r):
+			number++
+		case isSymbol(r):
+			symbol++
+		}
+	}
+	return
+}
+
+// singleClass names the character class of a password whose n characters all
+// belong to one class, or returns "".
+func singleClass(n, lower, upper, number, symbol int) string {
+	switch {
+	case n == 0:
+		return ""
+	case lower == n:
+		return "lowercase letters"
+	case upper == n:
+		return "uppercase letters"
+	case number == n:
+		return "digits"
+	case symbol == n:
+		return "symbols"
+	}
+	return ""
 }
 
 func charClasses(password string) (lower, upper, number, symbol bool) {
@@ -254,7 +1194,7 @@ func charClasses(password string) (lower, upper, number, symbol bool) {
 			upper = true
 		case unicode.IsDigit(r):
 			number = true
-		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+		case isSymbol(r):
 			symbol = true
 		}
 	}
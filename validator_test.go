@@ -1,7 +1,17 @@
 package passval
 
 import (
+	"encoding/json"
+	"math"
+	mrand "math/rand"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode"
 )
 
 func TestNewPasswordValidator(t *testing.T) {
@@ -172,22 +182,35 @@ func TestLeetNormalize(t *testing.T) {
 	}
 }
 
-func TestLeetVariants(t *testing.T) {
-	// "p@ss1" has '1' which maps to both 'i' and 'l'
-	variants := leetVariants("p@ss1")
-	if len(variants) < 2 {
-		t.Errorf("expected at least 2 variants, got %d: %v", len(variants), variants)
+func TestLeetContains(t *testing.T) {
+	// '1' and '|' stand for both 'i' and 'l'
+	for _, term := range []string{"passi", "passl"} {
+		if !leetContains("p@ss1", term, leetMap) {
+			t.Errorf("leetContains(%q, %q) = false, want true", "p@ss1", term)
+		}
+	}
+	if leetContains("p@ss1", "passo", leetMap) {
+		t.Error("'1' should not stand for 'o'")
 	}
+}
 
-	found := make(map[string]bool)
-	for _, v := range variants {
-		found[v] = true
+func TestDictionaryLeetMatch(t *testing.T) {
+	d := loadDictionary("speaking\nlittle\npassword")
+	tests := []struct {
+		input, want string
+	}{
+		{"p@ssw0rd", "password"},
+		{"sp34k1ng", "speaking"},
+		{"|1tt|3", "little"}, // more ambiguous characters than variant enumeration handled
+		{"p@ssw0rdx", ""},
 	}
-	if !found["passi"] {
-		t.Error("expected variant 'passi'")
+	for _, tt := range tests {
+		if got := d.leetMatch(tt.input, leetMap); got != tt.want {
+			t.Errorf("leetMatch(%q) = %q, want %q", tt.input, got, tt.want)
+		}
 	}
-	if !found["passl"] {
-		t.Error("expected variant 'passl'")
+	if m, _ := d.longestLeetMatch("l33t$p34k1ng!", 4, leetMap); m.word != "speaking" || m.start != 4 || m.end != 12 {
+		t.Errorf("longestLeetMatch = %+v, want speaking at [4, 12)", m)
 	}
 }
 
@@ -260,12 +283,1582 @@ func TestComplexityThreshold(t *testing.T) {
 	}
 }
 
-// Benchmarks
-func BenchmarkValidate(b *testing.B) {
-	v := NewPasswordValidator(8, 64, true, true, true, true, 50)
-	for i := 0; i < b.N; i++ {
-		v.Validate("MyP@ssw0rd!23")
+func TestShannonEntropy(t *testing.T) {
+	skewed := calculateShannonEntropy("aaaaaaA1!")
+	spread := calculateShannonEntropy("xK9$mP2!v")
+	if skewed >= spread {
+		t.Errorf("skewed password should have lower entropy: %.1f vs %.1f", skewed, spread)
+	}
+	if got := calculateShannonEntropy("aaaa"); got != 0 {
+		t.Errorf("single repeated character should have zero entropy, got %.1f", got)
+	}
+
+	vPool := NewPasswordValidator(4, 64, false, false, false, false, 0)
+	vShannon := NewPasswordValidator(4, 64, false, false, false, false, 0, WithEntropyMode(EntropyShannon))
+	_, poolScore := vPool.Validate("aaaaaaA1!")
+	_, shannonScore := vShannon.Validate("aaaaaaA1!")
+	if shannonScore >= poolScore {
+		t.Errorf("Shannon mode should score 'aaaaaaA1!' lower: %d vs %d", shannonScore, poolScore)
+	}
+}
+
+func TestDegenerateScores(t *testing.T) {
+	v := NewPasswordValidator(1, 64, false, false, false, false, 0, WithEntropyMode(EntropyShannon))
+	if _, score := v.Validate(""); score != 0 {
+		t.Errorf("empty password scored %d, want 0", score)
+	}
+	prev := 0
+	for n := 1; n <= 32; n++ {
+		_, score := v.Validate(strings.Repeat("a", n))
+		if score < 1 || score < prev || score > maxLengthScore {
+			t.Fatalf("%d repeated characters scored %d after %d, want a small non-decreasing score", n, score, prev)
+		}
+		prev = score
+	}
+	if prev != maxLengthScore {
+		t.Errorf("32 repeated characters scored %d, want %d", prev, maxLengthScore)
+	}
+
+	// Common passwords keep the score their penalties leave.
+	v = NewPasswordValidator(8, 64, true, true, true, true, 50)
+	for pwd, want := range map[string]int{"password": 1, "P@ssw0rd": 2} {
+		if _, score := v.Validate(pwd); score != want {
+			t.Errorf("%q scored %d, want %d", pwd, score, want)
+		}
+	}
+	v = NewPasswordValidator(8, 64, false, false, false, false, 4)
+	if pass, score := v.Validate("password"); pass {
+		t.Errorf("common password passes a complexity of 4 with score %d", score)
+	}
+}
+
+func TestMinGuesses(t *testing.T) {
+	v := NewPasswordValidator(4, 64, false, false, false, false, 0, WithMinGuesses(1e10))
+
+	if pass, score := v.Validate("password"); pass {
+		t.Errorf("'password' should not survive 1e10 guesses, score=%d", score)
+	}
+	if pass, score := v.Validate("Xk9$mP2!vLq"); !pass {
+		t.Errorf("'Xk9$mP2!vLq' should survive 1e10 guesses, score=%d", score)
+	}
+
+	vTime := NewPasswordValidator(4, 64, false, false, false, false, 0,
+		WithMinCrackTime(365*24*time.Hour, AttackOfflineFastHash))
+	if pass, _ := vTime.Validate("qwerty"); pass {
+		t.Error("'qwerty' should not survive a year of fast offline guessing")
+	}
+	if d := vTime.CrackTime("Xk9$mP2!vLq", AttackOfflineSlowHash); d < 24*time.Hour {
+		t.Errorf("expected long crack time for strong password, got %s", d)
+	}
+}
+
+func TestPassphrasePolicy(t *testing.T) {
+	v := NewPasswordValidator(12, 64, true, false, true, true, 50)
+	if pass, _ := v.Validate("correct horse battery staple"); pass {
+		t.Error("passphrase should not pass without passphrase policy")
+	}
+
+	v = NewPasswordValidator(12, 64, true, false, true, true, 50, WithPassphrasePolicy(4, 4))
+	pass, score, err := v.ValidateVerbose("correct horse battery staple")
+	if !pass {
+		t.Errorf("'correct horse battery staple' should pass passphrase policy (score=%d): %v", score, err)
+	}
+
+	// Too few words: normal rules apply
+	if pass, _ := v.Validate("correct horse battery"); pass {
+		t.Error("three-word phrase should not qualify as a passphrase")
+	}
+
+	// Repeated words add no entropy
+	if pass, score := v.Validate("staple staple staple staple"); pass {
+		t.Errorf("repeated words should not pass, score=%d", score)
+	}
+
+	// Any non-letter separates words; leet-speak fragments are too short to
+	// count as words under a sensible minimum word length.
+	v = NewPasswordValidator(12, 64, true, false, false, false, 0, WithPassphrasePolicy(3, 4))
+	for _, tc := range []struct {
+		pwd  string
+		want bool
+	}{
+		{"maple7orbit!canyon", true},
+		{"maple.orbit_canyon", true},
+		{"P4ssw0rd", false},
+		{"Tr0ub4dor&3P4ssw0rd", false},
+	} {
+		if _, got := v.passphraseWords(tc.pwd); got != tc.want {
+			t.Errorf("passphraseWords(%q) qualifies = %v, want %v", tc.pwd, got, tc.want)
+		}
+	}
+	if words := splitPassphrase("P4ssw0rd"); !slices.Equal(words, []string{"P", "ssw", "rd"}) {
+		t.Errorf("splitPassphrase(%q) = %q", "P4ssw0rd", words)
+	}
+
+	// Words are credited with the size of the embedded wordlist
+	if got, want := v.ValidateResult("correct horse battery staple").Entropy, 4*math.Log2(float64(DefaultWordlist().Len())); math.Abs(got-want) > 1e-9 {
+		t.Errorf("passphrase entropy = %.2f, want %.2f", got, want)
+	}
+}
+
+func TestGeneratePassphrase(t *testing.T) {
+	phrase, entropy, err := GeneratePassphrase(5, "-")
+	if err != nil {
+		t.Fatalf("GeneratePassphrase() error: %v", err)
+	}
+	if words := strings.Split(phrase, "-"); len(words) != 5 {
+		t.Errorf("expected 5 words, got %d: %q", len(words), phrase)
+	}
+	if entropy < 50 {
+		t.Errorf("expected at least 50 bits for 5 words, got %.1f", entropy)
+	}
+
+	phrase, entropyExtra, err := GeneratePassphrase(5, " ",
+		WithPassphraseCapitalize(), WithPassphraseDigits(1), WithPassphraseSymbols(1))
+	if err != nil {
+		t.Fatalf("GeneratePassphrase() error: %v", err)
+	}
+	lower, upper, number, symbol := charClasses(phrase)
+	if !lower || !upper || !number || !symbol {
+		t.Errorf("passphrase %q missing char classes", phrase)
+	}
+	if entropyExtra <= entropy {
+		t.Errorf("injected digits/symbols should add entropy: %.1f vs %.1f", entropyExtra, entropy)
+	}
+
+	if _, _, err := GeneratePassphrase(0, " "); err == nil {
+		t.Error("expected error for zero words")
+	}
+	t.Logf("Generated passphrase: %q (%.1f bits)", phrase, entropyExtra)
+}
+
+func TestGeneratePassphraseWithInfo(t *testing.T) {
+	g, err := GeneratePassphraseWithInfo(6, " ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Words != 6 || g.WordlistSize != len(defaultWordlist) || len(strings.Fields(g.Passphrase)) != 6 {
+		t.Errorf("GeneratePassphraseWithInfo = %+v", g)
+	}
+	if want := 6 * math.Log2(float64(len(defaultWordlist))); math.Abs(g.Entropy-want) > 1e-9 {
+		t.Errorf("Entropy = %.2f, want %.2f from the wordlist", g.Entropy, want)
+	}
+	if g.CharacterEntropy <= g.Entropy {
+		t.Errorf("CharacterEntropy = %.1f, want the character estimate above %.1f", g.CharacterEntropy, g.Entropy)
+	}
+}
+
+func TestPassphraseInjection(t *testing.T) {
+	opts := []PassphraseOption{WithPassphraseDigits(2), WithPassphraseSymbols(1)}
+	for i := 0; i < 20; i++ {
+		phrase, _, err := GeneratePassphrase(4, " ", append(opts, WithPassphraseInjection(InjectSuffix))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts := strings.Split(phrase, " ")
+		if len(parts) != 5 || len([]rune(parts[4])) != 3 || strings.IndexFunc(strings.Join(parts[:4], ""), func(r rune) bool { return !unicode.IsLetter(r) }) >= 0 {
+			t.Fatalf("InjectSuffix gave %q, want 4 plain words and a 3-character block", phrase)
+		}
+
+		phrase, _, err = GeneratePassphrase(4, "-", append(opts, WithPassphraseInjection(InjectSeparators))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if words := splitPassphrase(phrase); len(words) != 4 {
+			t.Fatalf("InjectSeparators gave %q, want 4 intact words", phrase)
+		}
+		if _, _, number, symbol := charClasses(phrase); !number || !symbol {
+			t.Fatalf("InjectSeparators gave %q without digits and symbols", phrase)
+		}
+	}
+
+	_, plain, _ := GeneratePassphrase(4, "-")
+	_, suffix, _ := GeneratePassphrase(4, "-", append(opts, WithPassphraseInjection(InjectSuffix))...)
+	_, between, _ := GeneratePassphrase(4, "-", append(opts, WithPassphraseInjection(InjectSeparators))...)
+	if !(plain < suffix && suffix < between) {
+		t.Errorf("entropy plain %.1f, suffix %.1f, separators %.1f: want increasing", plain, suffix, between)
+	}
+
+	// 4 words of 6 and 2 digits: the two placements are credited less the
+	// log2(2!) bit lost to their order.
+	wl, err := NewWordlist([]string{"alpha", "bravo", "delta", "hotel", "oscar", "tango"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := 4*math.Log2(6) + 2*math.Log2(10)
+	for mode, want := range map[PassphraseInjection]float64{
+		InjectSuffix:     base,
+		InjectSeparators: base + 2*math.Log2(3) - 1,
+		InjectWordEnd:    base + 2*math.Log2(4) - 1,
+	} {
+		_, got, err := GeneratePassphrase(4, "-", WithPassphraseWordlist(wl), WithPassphraseDigits(2), WithPassphraseInjection(mode))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("injection %v: entropy %.3f, want %.3f", mode, got, want)
+		}
+	}
+}
+
+func TestValidatorGeneratePassphrase(t *testing.T) {
+	v := NewPasswordValidator(16, 64, true, true, true, true, 50,
+		WithPassphrasePolicy(4, 4), WithAllowedSymbols("!#"), WithExcludeChars("23456789"))
+	for i := 0; i < 20; i++ {
+		phrase, _, err := v.GeneratePassphrase(4, " ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !v.ValidateResult(phrase).Pass {
+			t.Fatalf("v.GeneratePassphrase() = %q, which fails the policy", phrase)
+		}
+		if len(splitPassphrase(phrase)) != 4 || strings.ContainsAny(phrase, "23456789") {
+			t.Fatalf("v.GeneratePassphrase() = %q, want 4 words with digits 0-1 between them", phrase)
+		}
+		if _, upper, number, symbol := charClasses(phrase); !upper || !number || !symbol {
+			t.Fatalf("v.GeneratePassphrase() = %q, missing a required class", phrase)
+		}
+	}
+
+	v = NewPasswordValidator(12, 64, true, false, true, false, 0, WithExcludeChars("0123456789"))
+	if _, _, err := v.GeneratePassphrase(4, "-"); err == nil {
+		t.Error("expected an error when every digit is excluded")
+	}
+}
+
+func TestGenerate_ExcludeAmbiguous(t *testing.T) {
+	v := NewPasswordValidator(16, 24, true, true, true, true, 40, WithExcludeAmbiguous())
+
+	for i := 0; i < 20; i++ {
+		pwd, err := v.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error: %v", err)
+		}
+		if strings.ContainsAny(pwd, ambiguousChars) {
+			t.Errorf("generated password %q contains ambiguous characters", pwd)
+		}
+	}
+
+	v = NewPasswordValidator(8, 16, false, false, true, false, 0, WithExcludeChars(numberChars))
+	if _, err := v.Generate(); err == nil {
+		t.Error("expected error when every number is excluded but numbers are required")
+	}
+}
+
+func TestAllowedSymbols(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 0, WithAllowedSymbols("!@#$%"))
+
+	if pass, _ := v.Validate("Abcdefg1!"); !pass {
+		t.Error("'Abcdefg1!' uses an allowed symbol and should pass")
+	}
+	_, _, err := v.ValidateVerbose("Abcdefg1~")
+	if err == nil {
+		t.Fatal("'Abcdefg1~' uses a disallowed symbol and should fail")
+	}
+	if !strings.Contains(err.Error(), "symbols not allowed: ~") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		pwd, err := v.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error: %v", err)
+		}
+		if bad := disallowedSymbols(pwd, "!@#$%"); bad != "" {
+			t.Errorf("generated password %q contains disallowed symbols %q", pwd, bad)
+		}
+	}
+}
+
+func TestDisallowedChars(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 0, WithDisallowedChars(`"'\`+" "))
+
+	if pass, _ := v.Validate("Abcdefg1!"); !pass {
+		t.Error("'Abcdefg1!' has no disallowed characters and should pass")
+	}
+	_, _, err := v.ValidateVerbose(`Abc de"fg1!"`)
+	vErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	if !slices.Contains(vErr.Codes(), RuleDisallowedChar) {
+		t.Errorf("codes = %v, want %s", vErr.Codes(), RuleDisallowedChar)
+	}
+	if !strings.Contains(err.Error(), `characters not allowed: space "`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		pwd, err := v.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error: %v", err)
+		}
+		if strings.ContainsAny(pwd, `"'\ `) {
+			t.Errorf("generated password %q contains disallowed characters", pwd)
+		}
+	}
+}
+
+func TestMinClassCounts(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 0, WithMinClassCounts(1, 1, 2, 2))
+
+	tests := []struct {
+		password string
+		wantPass bool
+	}{
+		{"Abcdefg1!", false},
+		{"Abcdef12!", false},
+		{"Abcdef1!?", false},
+		{"Abcdef12!?", true},
+	}
+	for _, tt := range tests {
+		pass, _, err := v.ValidateVerbose(tt.password)
+		if pass != tt.wantPass {
+			t.Errorf("Validate(%q) = %v (%v), want %v", tt.password, pass, err, tt.wantPass)
+		}
+	}
+
+	_, _, err := v.ValidateVerbose("Abcdefgh1!")
+	if err == nil || !strings.Contains(err.Error(), "too few numbers: minimum 2") {
+		t.Errorf("expected 'too few numbers' failure, got %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		pwd, err := v.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error: %v", err)
+		}
+		if _, _, number, symbol := charClassCounts(pwd); number < 2 || symbol < 2 {
+			t.Errorf("generated password %q has %d digits and %d symbols, want at least 2 each", pwd, number, symbol)
+		}
+	}
+
+	v = NewPasswordValidator(4, 6, false, false, false, false, 0, WithMinClassCounts(2, 2, 2, 2))
+	if _, err := v.Generate(); err == nil {
+		t.Error("expected error when class minimums exceed maximum length")
+	}
+}
+
+func TestMinCharClasses(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0, WithMinCharClasses(3))
+
+	tests := []struct {
+		password string
+		wantPass bool
+	}{
+		{"abcdefgh", false},
+		{"abcdEFGH", false},
+		{"abcdEF12", true},
+		{"abcd12!?", true},
+		{"aB3!xY7$", true},
+	}
+	for _, tt := range tests {
+		pass, _, err := v.ValidateVerbose(tt.password)
+		if pass != tt.wantPass {
+			t.Errorf("Validate(%q) = %v (%v), want %v", tt.password, pass, err, tt.wantPass)
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		pwd, err := v.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error: %v", err)
+		}
+		if n := classesPresent(charClassCounts(pwd)); n < 3 {
+			t.Errorf("generated password %q has %d classes, want at least 3", pwd, n)
+		}
+	}
+}
+
+func TestMinUniqueChars(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0, WithMinUniqueChars(5))
+
+	tests := []struct {
+		password string
+		wantPass bool
+	}{
+		{"aaaaaaaa", false},
+		{"abababab", false},
+		{"aAbBcCdD", false}, // case does not make characters distinct
+		{"abcdabcd", false},
+		{"abcdeabc", true},
+		{"a1!b2?c3", true},
+	}
+	for _, tt := range tests {
+		res := v.ValidateResult(tt.password)
+		if res.Pass != tt.wantPass {
+			t.Errorf("Validate(%q) = %v (%v), want %v", tt.password, res.Pass, res.RuleFails, tt.wantPass)
+		}
+		if !tt.wantPass && !slices.Contains(res.Codes(), RuleMinUniqueChars) {
+			t.Errorf("Validate(%q) codes = %v, want %s", tt.password, res.Codes(), RuleMinUniqueChars)
+		}
+	}
+
+	pwd, err := v.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if pass, _ := v.Validate(pwd); !pass {
+		t.Errorf("generated password %q fails the policy", pwd)
+	}
+
+	if _, err := NewPasswordValidator(4, 6, false, false, false, false, 0, WithMinUniqueChars(8)).Generate(); err == nil {
+		t.Error("expected error when unique characters exceed maximum length")
+	}
+}
+
+func TestLengthExemption(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, false, true, true, 0, WithMinCharClasses(3), WithLengthExemption(20))
+
+	if pass, _ := v.Validate("shortpassword"); pass {
+		t.Error("short password without digits/symbols should fail")
+	}
+	if pass, _, err := v.ValidateVerbose("mylongsentencewithoutdigits"); !pass {
+		t.Errorf("20+ character password should be exempt from composition rules: %v", err)
+	}
+}
+
+func TestBannedSubstrings(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0, WithBannedSubstrings("Acme", "acmecorp"))
+
+	tests := []struct {
+		password string
+		wantPass bool
+	}{
+		{"ACMErocks2024!", false},
+		{"i<3@cm3!!", false},
+		{"Zx9!kQ2#rT", true},
+	}
+	for _, tt := range tests {
+		pass, _, err := v.ValidateVerbose(tt.password)
+		if pass != tt.wantPass {
+			t.Errorf("Validate(%q) = %v (%v), want %v", tt.password, pass, err, tt.wantPass)
+		}
+	}
+
+	_, _, err := v.ValidateVerbose("ACMErocks2024!")
+	vErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatal("expected *ValidationError")
+	}
+	found := false
+	for _, code := range vErr.Codes() {
+		if code == RuleBannedSubstring {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected reason code %q in %v", RuleBannedSubstring, vErr.Codes())
+	}
+}
+
+func TestSiteTerms(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0, WithSiteTerms("acme", "AcmeBank", " "))
+	for _, pw := range []string{"acme2024!", "Acm3Bank#1", "my@cmeapp99"} {
+		r := v.ValidateResult(pw)
+		if r.Pass || !slices.Contains(r.Codes(), RuleSiteTerm) {
+			t.Errorf("ValidateResult(%q) = %v %v, want a %s failure", pw, r.Pass, r.Codes(), RuleSiteTerm)
+		}
+	}
+	if r := v.ValidateResult("Zx9!kQ2#rT"); !r.Pass {
+		t.Errorf("unrelated password failed: %v", r.RuleFails)
+	}
+	if got := v.Policy().SiteTerms; !slices.Equal(got, []string{"acme", "acmebank"}) {
+		t.Errorf("Policy().SiteTerms = %q", got)
+	}
+	if c := v.Clone(WithSiteTerms("acmeapp")); len(v.siteTerms) != 2 || len(c.siteTerms) != 3 {
+		t.Errorf("Clone shares site terms: %d and %d", len(v.siteTerms), len(c.siteTerms))
+	}
+}
+
+func TestRuleFailParams(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 50, WithMinClassCounts(0, 0, 2, 0))
+	r := v.ValidateResult("abc1")
+	fails := make(map[string]RuleFail)
+	for _, f := range r.RuleFails {
+		fails[f.Code] = f
+	}
+	if f := fails[RuleTooShort]; f.Params["min"] != 8 || f.Params["actual"] != 4 || f.Message != "too short: minimum 8 characters" {
+		t.Errorf("too_short fail = %+v", f)
+	}
+	if f := fails[RuleMinDigits]; f.Params["min"] != 2 || f.Params["actual"] != 1 {
+		t.Errorf("min_digits fail = %+v", f)
+	}
+	if f, ok := fails[RuleMissingUpper]; !ok || f.Params["min"] != 1 || f.Params["actual"] != 0 {
+		t.Errorf("missing_upper fail = %+v", f)
+	}
+	if f := fails[RuleComplexity]; f.Params["min"] != 50 || f.Params["actual"] != r.Score {
+		t.Errorf("complexity fail = %+v, score %d", f, r.Score)
+	}
+
+	if got := r.Err().Error(); !strings.HasPrefix(got, "rule: too short: minimum 8 characters; ") {
+		t.Errorf("Error() = %q, want the messages as before", got)
+	}
+	if !slices.Equal(r.FailMessages()[:1], []string{"too short: minimum 8 characters"}) {
+		t.Errorf("FailMessages() = %q", r.FailMessages())
+	}
+	b, err := json.Marshal(fails[RuleTooShort])
+	if err != nil || string(b) != `{"code":"too_short","params":{"actual":4,"min":8},"message":"too short: minimum 8 characters"}` {
+		t.Errorf("json.Marshal = %s, %v", b, err)
+	}
+}
+
+func TestBannedPatterns(t *testing.T) {
+	employeeID := regexp.MustCompile(`(?i)emp\d{5}`)
+	ticket := regexp.MustCompile(`[A-Z]{3,5}-\d+`)
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0, WithBannedPatterns(employeeID, ticket))
+
+	tests := []struct {
+		password string
+		wantPass bool
+	}{
+		{"Emp12345!x", false},
+		{"fixes OPS-1234", false},
+		{"Zx9!kQ2#rT", true},
+	}
+	for _, tt := range tests {
+		pass, _, err := v.ValidateVerbose(tt.password)
+		if pass != tt.wantPass {
+			t.Errorf("Validate(%q) = %v (%v), want %v", tt.password, pass, err, tt.wantPass)
+		}
+	}
+}
+
+func TestMaxBytes(t *testing.T) {
+	v := NewPasswordValidator(8, 128, false, false, false, false, 0, WithMaxBytes(BcryptMaxBytes))
+
+	if pass, _, err := v.ValidateVerbose(strings.Repeat("xK9$", 18)); !pass {
+		t.Errorf("72-byte password should pass: %v", err)
+	}
+	// 40 characters, but 80 bytes in UTF-8
+	_, _, err := v.ValidateVerbose(strings.Repeat("ñÇ", 20))
+	if err == nil || !strings.Contains(err.Error(), "80 bytes exceeds the 72-byte limit") {
+		t.Errorf("expected byte limit failure, got %v", err)
+	}
+}
+
+func TestASCIIAndPrintableOnly(t *testing.T) {
+	v := NewPasswordValidator(4, 64, false, false, false, false, 0, WithASCIIOnly(), WithPrintableOnly())
+
+	tests := []struct {
+		password string
+		want     []string
+	}{
+		{"Tr0ub4dor &3", nil},
+		{"contraseña1", []string{RuleNonASCII}},
+		{"xq7\tzqk", []string{RuleNonPrintable}},
+		{"xq7\u00a0zçk", []string{RuleNonASCII, RuleNonPrintable}},
+		{"zero\u200bwidth", []string{RuleNonASCII, RuleNonPrintable}},
+	}
+	for _, tt := range tests {
+		res := v.ValidateResult(tt.password)
+		if got := res.Codes(); !slices.Equal(got, tt.want) {
+			t.Errorf("Validate(%q) codes = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+
+	res := v.ValidateResult("añño")
+	if len(res.RuleFails) != 1 || res.RuleFails[0].Message != "2 non-ASCII characters not allowed (first at position 2)" {
+		t.Errorf("RuleFails = %v", res.RuleFails)
+	}
+
+	if codes := NewPasswordValidator(4, 64, false, false, false, false, 0, WithPrintableOnly()).ValidateResult("contraseña").Codes(); len(codes) != 0 {
+		t.Errorf("printable-only should accept non-ASCII letters, got %v", codes)
+	}
+}
+
+func TestGenerate_RandSource(t *testing.T) {
+	gen := func(seed int64) string {
+		v := NewPasswordValidator(12, 20, true, true, true, true, 50, WithRandSource(mrand.New(mrand.NewSource(seed))))
+		pwd, err := v.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error: %v", err)
+		}
+		return pwd
+	}
+	if a, b := gen(1), gen(1); a != b {
+		t.Errorf("same seed produced %q and %q", a, b)
+	}
+	if a, b := gen(1), gen(2); a == b {
+		t.Errorf("different seeds both produced %q", a)
+	}
+
+	v := NewPasswordValidator(12, 20, true, true, true, true, 50, WithRandSource(strings.NewReader("")))
+	if _, err := v.Generate(); err == nil {
+		t.Error("expected an error from an exhausted rand source")
+	}
+}
+
+func TestGenerateN(t *testing.T) {
+	v := NewPasswordValidator(12, 20, true, true, true, true, 50)
+	pwds, err := v.GenerateN(5)
+	if err != nil {
+		t.Fatalf("GenerateN() error: %v", err)
+	}
+	if len(pwds) != 5 {
+		t.Fatalf("got %d passwords, want 5", len(pwds))
+	}
+	for _, pwd := range pwds {
+		if ok, _ := v.Validate(pwd); !ok {
+			t.Errorf("generated password %q does not validate", pwd)
+		}
+	}
+	if _, err := v.GenerateN(-1); err == nil {
+		t.Error("expected an error for a negative count")
+	}
+}
+
+func TestGenerateWithInfo(t *testing.T) {
+	v := NewPasswordValidator(12, 20, true, true, true, true, 50)
+	info, err := v.GenerateWithInfo()
+	if err != nil {
+		t.Fatalf("GenerateWithInfo() error: %v", err)
+	}
+	res := v.ValidateResult(info.Password)
+	if info.Score != res.Score || info.Entropy != res.Entropy {
+		t.Errorf("info %+v does not match validation score %d, entropy %.1f", info, res.Score, res.Entropy)
+	}
+	if info.Strength != StrengthLabel(info.Score) {
+		t.Errorf("Strength = %q, want %q", info.Strength, StrengthLabel(info.Score))
+	}
+}
+
+func TestGenerateWithEntropy(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 50)
+	pwd, err := v.GenerateWithEntropy(80)
+	if err != nil {
+		t.Fatalf("GenerateWithEntropy() error: %v", err)
+	}
+	// 92 characters give ~6.52 bits each, so 80 bits need 13 characters.
+	if len(pwd) != 13 {
+		t.Errorf("len(%q) = %d, want 13", pwd, len(pwd))
+	}
+	if ok, _ := v.Validate(pwd); !ok {
+		t.Errorf("generated password %q does not validate", pwd)
+	}
+
+	short := NewPasswordValidator(8, 12, true, true, true, true, 50)
+	if _, err := short.GenerateWithEntropy(128); err == nil {
+		t.Error("expected an error when the required length exceeds MaxLength")
+	}
+}
+
+func TestGenerate_ProfanityFilter(t *testing.T) {
+	v := NewPasswordValidator(12, 12, true, true, true, true, 0, WithProfanityList("a", "b", "c"))
+	for i := 0; i < 20; i++ {
+		pwd, err := v.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error: %v", err)
+		}
+		if term := findBannedTerm(pwd, v.profanity, v.leet); term != "" {
+			t.Errorf("generated %q contains screened term %q", pwd, term)
+		}
+	}
+
+	if v := NewPasswordValidator(8, 8, true, true, true, true, 0, WithProfanityList()); v.profanity != nil {
+		t.Errorf("WithProfanityList() left %d terms, want screening disabled", len(v.profanity))
+	}
+}
+
+func TestOffensivePassphrase(t *testing.T) {
+	terms := newBannedTerms([]string{"ass", "hell"})
+	tests := []struct {
+		parts []string
+		want  bool
+	}{
+		{[]string{"grass", "shell"}, false}, // inside longer words
+		{[]string{"hell", "river"}, true},   // whole word
+		{[]string{"tuba", "ssoon"}, true},   // across a boundary
+		{[]string{"Hell", "river"}, true},   // capitalized
+		{[]string{"tuba", "river"}, false},
+	}
+	for _, tt := range tests {
+		if got := offensivePassphrase(tt.parts, terms); got != tt.want {
+			t.Errorf("offensivePassphrase(%q) = %v, want %v", tt.parts, got, tt.want)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 50, WithBannedSubstrings("acme"))
+	c := v.Clone(WithBannedSubstrings("globex"), WithWarnOnly(RuleMissingSymbol))
+
+	if len(v.bannedTerms) != 1 || v.warnOnly != nil {
+		t.Errorf("Clone options leaked into the original: %d banned terms, warnOnly %v", len(v.bannedTerms), v.warnOnly)
+	}
+	if ok, _ := c.Validate("Globex#Xk9$mP2"); ok {
+		t.Error("clone should ban 'globex'")
+	}
+	if ok, _ := v.Validate("Globex#Xk9$mP2"); !ok {
+		t.Error("original should still accept 'globex'")
+	}
+
+	stricter := v.WithComplexity(150).WithMinLength(80)
+	if stricter.Complexity != 100 || stricter.MinLength != 80 || stricter.MaxLength != 80 {
+		t.Errorf("got Complexity=%d MinLength=%d MaxLength=%d, want 100, 80, 80",
+			stricter.Complexity, stricter.MinLength, stricter.MaxLength)
+	}
+	if v.Complexity != 50 || v.MinLength != 8 {
+		t.Error("With* helpers modified the original validator")
+	}
+}
+
+func TestConcurrentValidateAndSetDictionary(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 50)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 50; i++ {
+			v.SetDictionary("hunter2\ntr0ub4dor")
+			v.SetDictionary("")
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				v.ValidateResult("MyP@ssw0rd!23")
+			}
+		}()
+	}
+	wg.Wait()
+	<-done
+
+	v.SetDictionary("zebracorn")
+	if r := v.ValidateResult("zebracorn"); !hasPenalty(r.Penalties, "common_password") {
+		t.Errorf("SetDictionary did not take effect, penalties %v", r.Penalties)
+	}
+	if c := v.Clone(); c.dictionary() != v.dictionary() {
+		t.Error("Clone should start with the same dictionary")
+	} else if c.SetDictionary(""); v.dictionary() == globalDict {
+		t.Error("SetDictionary on a clone replaced the original's dictionary")
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	a := analyze("P@sss1234!", leetMap)
+	if a.lower != "p@sss1234!" {
+		t.Errorf("lower = %q", a.lower)
+	}
+	if a.lowerCount != 3 || a.upperCount != 1 || a.numberCount != 4 || a.symbolCount != 2 {
+		t.Errorf("class counts = %d/%d/%d/%d, want 3/1/4/2", a.lowerCount, a.upperCount, a.numberCount, a.symbolCount)
+	}
+	if a.maxRepeat != 3 || a.maxSequence != 4 || a.uniqueRunes != 8 {
+		t.Errorf("maxRepeat = %d, maxSequence = %d, uniqueRunes = %d, want 3, 4, 8", a.maxRepeat, a.maxSequence, a.uniqueRunes)
+	}
+}
+
+func TestUnicodeLeet(t *testing.T) {
+	v := NewPasswordValidator(6, 64, false, false, false, false, 0)
+	for _, pwd := range []string{"paßword", "pаssword", "l€tmein", "£etmein"} { // "pаssword" has a Cyrillic 'а'
+		if r := v.ValidateResult(pwd); !hasPenalty(r.Penalties, "common_password_leet") {
+			t.Errorf("%q: expected common_password_leet, got %v", pwd, r.Penalties)
+		}
+	}
+
+	v = NewPasswordValidator(6, 64, false, false, false, false, 0,
+		WithBannedSubstrings("yahoo"), WithLeetMap(map[rune][]string{'¥': {"y"}}))
+	if ok, _ := v.Validate("¥ahoo-rocks"); ok {
+		t.Error("WithLeetMap mapping should match the banned term")
+	}
+	if leetMap['¥'] != nil {
+		t.Error("WithLeetMap modified the default table")
+	}
+
+	// Entries without letters remove a mapping; keys and letters are lowercased.
+	v = NewPasswordValidator(6, 64, false, false, false, false, 0,
+		WithLeetMap(map[rune][]string{'x': {}, '$': nil, 'Ж': {"ZH"}}))
+	if got := v.LeetNormalize("xylophone"); got != "xylophone" {
+		t.Errorf("LeetNormalize(%q) = %q", "xylophone", got)
+	}
+	if got := v.LeetNormalize("pa$$Ж"); got != "pa$$zh" {
+		t.Errorf("LeetNormalize(%q) = %q, want %q", "pa$$Ж", got, "pa$$zh")
+	}
+	if got := v.LeetVariants("x$", 4); len(got) != 1 || got[0] != "x$" {
+		t.Errorf("LeetVariants(%q) = %q", "x$", got)
+	}
+}
+
+func TestDictionaryConcatenation(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0)
+	for _, pwd := range []string{"qwertydragon", "letmeinmonkey", "Sunshine!Dragon7"} {
+		r := v.ValidateResult(pwd)
+		if !hasPenalty(r.Penalties, "dictionary_concatenation") {
+			t.Errorf("%q: expected dictionary_concatenation, got %v", pwd, r.Penalties)
+		}
+		if hasPenalty(r.Penalties, "dictionary_substring") {
+			t.Errorf("%q: concatenation should replace the single-word penalty", pwd)
+		}
+	}
+	if r := v.ValidateResult("dragonXk9$mP2!vLq"); hasPenalty(r.Penalties, "dictionary_concatenation") {
+		t.Errorf("one word in a random string is not a concatenation: %v", r.Penalties)
+	}
+}
+
+func TestDictionaryCoverage(t *testing.T) {
+	dict := "monkey\ndragon\nstar\ncons\nside\nsider\nration\nratio\nions\n"
+	v := NewPasswordValidatorWithDict(8, 64, false, false, false, false, 0, dict)
+
+	// Two full words: covered almost entirely.
+	if p := findPenalty(v.ValidateResult("monkeydragon1").Penalties, "dictionary_concatenation"); p == nil || p.Factor > 0.3 {
+		t.Errorf("monkeydragon1: expected a strong concatenation penalty, got %+v", p)
+	}
+	// Overlapping fragments of one word count once: cons+ration, not all of it.
+	if p := findPenalty(v.ValidateResult("considerations").Penalties, "dictionary_concatenation"); p == nil || p.Factor < 0.4 || !strings.Contains(p.Desc, "71% coverage") {
+		t.Errorf("considerations: expected a concatenation penalty on 71%% coverage, got %+v", p)
+	}
+	// Words apart from each other add up too.
+	p := penaltyDictionarySubstring(analyze("Xk9dragonQ7star", leetMap), loadDictionary(dict), penaltyConfig{})
+	if p == nil || p.Factor != 0.5 || p.Match != "dragon+star" || p.Start != 3 || p.End != 15 {
+		t.Errorf("Xk9dragonQ7star: expected dragon+star covering 67%%, got %+v", p)
+	}
+}
+
+func TestDictTiling(t *testing.T) {
+	matches := []dictMatch{
+		{"cons", 0, 4}, {"side", 3, 7}, {"sider", 3, 8}, {"ration", 7, 13}, {"ratio", 7, 12}, {"ions", 10, 14},
+	}
+	tiling, covered := dictTiling(matches)
+	if covered != 10 || len(tiling) != 2 || tiling[0].word != "cons" || tiling[1].word != "ration" {
+		t.Errorf("dictTiling = %v, %d; want cons+ration covering 10", tiling, covered)
+	}
+	if tiling, covered := dictTiling(nil); tiling != nil || covered != 0 {
+		t.Errorf("dictTiling(nil) = %v, %d", tiling, covered)
+	}
+}
+
+func TestDictMinMatchLen(t *testing.T) {
+	dict := "love\nstar\ndragon\nmonkey\n"
+	v := NewPasswordValidatorWithDict(8, 64, false, false, false, false, 0, dict)
+	long := NewPasswordValidatorWithDict(8, 64, false, false, false, false, 0, dict, WithDictMinMatchLen(5))
+
+	for _, pwd := range []string{"Xk9$loveQ7", "lovestar"} {
+		if r := v.ValidateResult(pwd); !hasPenalty(r.Penalties, "dictionary_substring") && !hasPenalty(r.Penalties, "dictionary_concatenation") {
+			t.Errorf("%q: expected a dictionary penalty by default, got %v", pwd, r.Penalties)
+		}
+		if r := long.ValidateResult(pwd); hasPenalty(r.Penalties, "dictionary_substring") || hasPenalty(r.Penalties, "dictionary_concatenation") {
+			t.Errorf("%q: 4-character words should not count with a minimum of 5, got %v", pwd, r.Penalties)
+		}
+	}
+	if r := long.ValidateResult("dragonmonkey"); !hasPenalty(r.Penalties, "dictionary_concatenation") {
+		t.Errorf("longer words should still count, got %v", r.Penalties)
+	}
+
+	if _, err := NewValidatorStrict(8, 64, true, true, true, true, 0, WithDictMinMatchLen(-1)); err == nil {
+		t.Error("expected an error for a negative minimum match length")
+	}
+}
+
+func TestReversedDictionaryWords(t *testing.T) {
+	v := NewPasswordValidator(6, 64, false, false, false, false, 0)
+	tests := []struct {
+		password, rule string
+	}{
+		{"drowssap", "common_password"},
+		{"dr0wss@p", "common_password_leet"},
+		{"nogard123", "dictionary_substring"},
+	}
+	for _, tt := range tests {
+		r := v.ValidateResult(tt.password)
+		found := false
+		for _, p := range r.Penalties {
+			if p.Rule == tt.rule && strings.Contains(p.Desc, "reversed") {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%q: expected reversed %s penalty, got %v", tt.password, tt.rule, r.Penalties)
+		}
+	}
+}
+
+func TestPenaltySpans(t *testing.T) {
+	v := NewPasswordValidator(6, 64, false, false, false, false, 0)
+	tests := []struct {
+		password, rule, span string
+	}{
+		{"Xk9dragonQ7", "dictionary_substring", "dragon"},
+		{"Xk9nogardQ7", "dictionary_substring", "nogard"},
+		{"Zq!aaaaW7", "repeated_chars", "aaaa"},
+		{"Zq!1234W7", "sequential_chars", "1234"},
+		{"Zq!asdfgW7", "keyboard_pattern", "asdfg"},
+		{"ÄÖdragonÜ7", "dictionary_substring", "dragon"}, // byte offsets past multi-byte runes
+		{"password", "common_password", "password"},
+	}
+	for _, tt := range tests {
+		r := v.ValidateResult(tt.password)
+		found := false
+		for _, p := range r.Penalties {
+			if p.Rule != tt.rule {
+				continue
+			}
+			found = true
+			if got := tt.password[p.Start:p.End]; got != tt.span {
+				t.Errorf("%q %s: span %q, want %q", tt.password, tt.rule, got, tt.span)
+			}
+		}
+		if !found {
+			t.Errorf("%q: expected %s, got %v", tt.password, tt.rule, r.Penalties)
+		}
+	}
+}
+
+func TestRedactedMessages(t *testing.T) {
+	v := NewPasswordValidator(6, 64, false, false, false, false, 100, WithRedactedMessages())
+	for _, pwd := range []string{"Xk9dragonQ7", "p@ssw0rd", "qwertydragon"} {
+		_, _, err := v.ValidateVerbose(pwd)
+		if err == nil {
+			t.Fatalf("%q: expected penalties", pwd)
+		}
+		if msg := err.Error(); strings.Contains(msg, "dragon") || strings.Contains(msg, "passw0rd") {
+			t.Errorf("%q: message leaks the matched word: %s", pwd, msg)
+		}
+	}
+
+	r := v.ValidateResult("Xk9dragonQ7")
+	if len(r.Penalties) == 0 || r.Penalties[0].Match != "dragon" || !strings.Contains(r.Penalties[0].Desc, "(6 chars)") {
+		t.Errorf("expected a redacted description with structured match data, got %+v", r.Penalties)
+	}
+}
+
+func TestValidateBytes(t *testing.T) {
+	v := NewPasswordValidator(6, 64, false, false, false, false, 50)
+	for _, pwd := range []string{"Xk9dragonQ7", "p@ssw0rd", "ÄÖdragonÜ7", "Tr0ub4dor&3xyz!"} {
+		buf := []byte(pwd)
+		got, want := v.ValidateBytes(buf), v.ValidateResult(pwd)
+		if got.Pass != want.Pass || got.Score != want.Score || len(got.Penalties) != len(want.Penalties) {
+			t.Errorf("%q: ValidateBytes = %v/%d, ValidateResult = %v/%d", pwd, got.Pass, got.Score, want.Pass, want.Score)
+		}
+		for _, p := range got.Penalties {
+			if p.Match != "" || strings.Contains(p.Desc, "dragon") {
+				t.Errorf("%q: penalty exposes the password: %+v", pwd, p)
+			}
+		}
+		if string(buf) != pwd {
+			t.Errorf("%q: buffer modified to %q", pwd, buf)
+		}
+	}
+
+	// Disallowed characters are reported by count and position only.
+	v = NewPasswordValidator(6, 64, false, false, false, false, 0, WithAllowedSymbols("!"), WithDisallowedChars("'\\"))
+	r := v.ValidateBytes([]byte(`Xk9'mP%\\q`))
+	if codes := r.Codes(); !slices.Contains(codes, RuleDisallowedSymbol) || !slices.Contains(codes, RuleDisallowedChar) {
+		t.Fatalf("expected %s and %s, got %v", RuleDisallowedSymbol, RuleDisallowedChar, codes)
+	}
+	for _, f := range r.RuleFails {
+		if f.Code != RuleDisallowedSymbol && f.Code != RuleDisallowedChar {
+			continue
+		}
+		if strings.ContainsAny(f.Message, `'%\`) || f.Params["symbols"] != nil || f.Params["chars"] != nil || f.Params["count"] == nil || f.Params["position"] != 4 {
+			t.Errorf("%s exposes the password: %q %v", f.Code, f.Message, f.Params)
+		}
+	}
+}
+
+func TestAnalysisWipe(t *testing.T) {
+	a := analyzeSecret([]byte("Dragon42"), leetMap)
+	if a.lower != "dragon42" || a.reversed != "24nogard" {
+		t.Fatalf("unexpected forms %q, %q", a.lower, a.reversed)
+	}
+	a.wipe()
+	if strings.Trim(a.lower, "\x00") != "" || strings.Trim(a.reversed, "\x00") != "" {
+		t.Errorf("forms not zeroed: %q, %q", a.lower, a.reversed)
+	}
+	for _, r := range a.runes {
+		if r != 0 {
+			t.Fatalf("runes not zeroed: %q", a.runes)
+		}
+	}
+}
+
+func TestGenerateInto(t *testing.T) {
+	v := NewPasswordValidator(12, 20, true, true, true, true, 50)
+	buf := make([]byte, 16)
+	if err := v.GenerateInto(buf); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := v.Validate(string(buf)); !ok {
+		t.Errorf("generated password %q does not validate", buf)
+	}
+
+	for _, n := range []int{8, 32} {
+		if err := v.GenerateInto(make([]byte, n)); err == nil {
+			t.Errorf("expected an error for a %d-byte buffer", n)
+		}
+	}
+
+	failing := NewPasswordValidator(12, 20, true, true, true, true, 50, WithRandSource(strings.NewReader("")))
+	buf = []byte("leftover-secret!")
+	if err := failing.GenerateInto(buf); err == nil {
+		t.Fatal("expected a random source error")
+	}
+	if strings.Trim(string(buf), "\x00") != "" {
+		t.Errorf("buffer not zeroed on error: %q", buf)
+	}
+}
+
+func TestGenerateNoPenalties(t *testing.T) {
+	// A small charset makes runs and sequences likely in random passwords.
+	exclude := WithExcludeChars(removeChars(lowerChars+upperChars+numberChars+symbolChars, "abcdAB1234!"))
+	v := NewPasswordValidator(10, 10, true, true, true, true, 0, exclude, WithRandSource(mrand.New(mrand.NewSource(1))))
+	strict := v.Clone(WithGenerateNoPenalties())
+
+	penalized := 0
+	for i := 0; i < 50; i++ {
+		pwd := v.MustGenerate()
+		if len(v.ValidateResult(pwd).Penalties) > 0 {
+			penalized++
+		}
+		pwd = strict.MustGenerate()
+		if p := strict.ValidateResult(pwd).Penalties; len(p) > 0 {
+			t.Errorf("generated %q has penalties %+v", pwd, p)
+		}
+		buf := make([]byte, 10)
+		if err := strict.GenerateInto(buf); err != nil {
+			t.Fatal(err)
+		}
+		if p := strict.ValidateResult(string(buf)).Penalties; len(p) > 0 {
+			t.Errorf("GenerateInto produced %q with penalties %+v", buf, p)
+		}
+	}
+	if penalized == 0 {
+		t.Error("expected some penalized passwords without WithGenerateNoPenalties")
+	}
+}
+
+func TestDigitRunPenalty(t *testing.T) {
+	for _, tc := range []struct {
+		pwd, rule, match string
+	}{
+		{"6915553412aB!", "phone_number", "6915553412"},
+		{"(691) 555-3412x", "phone_number", "(691) 555-3412"},
+		{"+44 20 7946 0958", "phone_number", "+44 20 7946 0958"},
+		{"83920174xY", "digit_run", "83920174"},
+		{"Kq7vLp2wTz9", "", ""},
+		{"0123456789abcdefghij", "", ""}, // digits cover half the password
+	} {
+		a := analyze(tc.pwd, leetMap)
+		p := penaltyDigitRun(a)
+		if tc.rule == "" {
+			if p != nil {
+				t.Errorf("%q: unexpected penalty %+v", tc.pwd, p)
+			}
+			continue
+		}
+		if p == nil || p.Rule != tc.rule || p.Match != tc.match || tc.pwd[p.Start:p.End] != tc.match {
+			t.Errorf("%q: expected %s on %q, got %+v", tc.pwd, tc.rule, tc.match, p)
+		}
+	}
+
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0)
+	if _, score := v.Validate("6915553412aB!"); score >= 50 {
+		t.Errorf("phone number password should score low, got %d", score)
+	}
+}
+
+func TestAddressPenalty(t *testing.T) {
+	for _, tc := range []struct {
+		pwd, rule, match string
+	}{
+		{"john.doe@gmail.com", "email_address", "john.doe@gmail.com"},
+		{"Mar1a_R@Outlook.es!", "email_address", "Mar1a_R@Outlook.es"},
+		{"www.facebook.com", "url", "www.facebook.com"},
+		{"Facebook.com1", "url", "Facebook.com"},
+		{"https://example.org/login", "url", "https://example.org/login"},
+		{"correct.horse.battery", "", ""},
+		{"xK9#mail@x.io-Tq2$vLp8", "", ""}, // the address is a small part
+	} {
+		a := analyze(tc.pwd, leetMap)
+		p := penaltyAddress(a)
+		if tc.rule == "" {
+			if p != nil {
+				t.Errorf("%q: unexpected penalty %+v", tc.pwd, p)
+			}
+			continue
+		}
+		if p == nil || p.Rule != tc.rule || p.Match != tc.match || tc.pwd[p.Start:p.End] != tc.match {
+			t.Errorf("%q: expected %s on %q, got %+v", tc.pwd, tc.rule, tc.match, p)
+		}
+	}
+
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0)
+	if _, score := v.Validate("Zq.Vantrell@Kolvex.net"); score >= 50 {
+		t.Errorf("email address password should score low, got %d", score)
+	}
+}
+
+func TestSingleClass(t *testing.T) {
+	plain := NewPasswordValidator(8, 64, false, false, false, false, 0)
+	penalized := NewPasswordValidator(8, 64, false, false, false, false, 0, WithSingleClassPenalty(0.5))
+
+	for _, pwd := range []string{"asdkjhqwelkjzxc", "849302184093", "QWPZMXNVBCLK"} {
+		r := plain.ValidateResult(pwd)
+		if !slices.ContainsFunc(r.Warnings, func(w Warning) bool { return w.Code == WarnSingleClass }) {
+			t.Errorf("%q: expected a single class warning, got %v", pwd, r.Warnings)
+		}
+		if hasPenalty(r.Penalties, "single_class") {
+			t.Errorf("%q: penalty applied without WithSingleClassPenalty", pwd)
+		}
+
+		p := penalized.ValidateResult(pwd)
+		if !hasPenalty(p.Penalties, "single_class") || p.Score >= r.Score {
+			t.Errorf("%q: expected a single class penalty, got score %d (was %d)", pwd, p.Score, r.Score)
+		}
+	}
+
+	if r := penalized.ValidateResult("asdkjh7qwelkj"); hasPenalty(r.Penalties, "single_class") {
+		t.Error("mixed-class password should not be penalized")
+	}
+}
+
+func TestRepeatedPattern(t *testing.T) {
+	for _, tc := range []struct {
+		pwd, match string
+		factor     float64
+	}{
+		{"abababab", "ab", 0.3},
+		{"xyxyxyxy", "xy", 0.3},
+		{"Q7abcabcabc!", "abc", 0.5},
+		{"Xk9$mP2!vLq", "", 0},
+		{"aaaaaaaa", "", 0},  // left to the repeated character check
+		{"ab12ab3Zq", "", 0}, // a bigram must occur three times
+	} {
+		p := penaltyRepeatedPattern(analyze(tc.pwd, leetMap))
+		if tc.match == "" {
+			if p != nil {
+				t.Errorf("%q: unexpected penalty %+v", tc.pwd, p)
+			}
+			continue
+		}
+		if p == nil || p.Match != tc.match || p.Factor != tc.factor {
+			t.Errorf("%q: expected pattern %q with factor %.1f, got %+v", tc.pwd, tc.match, tc.factor, p)
+		}
+	}
+
+	p := penaltyRepeatedPattern(analyze("Q7abcabcabc!", leetMap))
+	if p == nil || p.Start != 2 || p.End != 11 {
+		t.Errorf("expected span [2, 11), got %+v", p)
+	}
+}
+
+func TestSeasonYear(t *testing.T) {
+	for _, tc := range []struct {
+		pwd, match string
+	}{
+		{"Summer2024$", "Summer2024"},
+		{"January2023!", "January2023"},
+		{"!Invierno-23", "Invierno-23"},
+		{"2024Herbst#", "2024Herbst"},
+		{"Xq!5umm3r24", "5umm3r24"},
+		{"Omar1990!x", ""},
+		{"Summer123!", ""},
+		{"Kq7vLp2wTz9", ""},
+	} {
+		p := penaltySeasonYear(analyze(tc.pwd, leetMap), penaltyConfig{})
+		if tc.match == "" {
+			if p != nil {
+				t.Errorf("%q: unexpected penalty %+v", tc.pwd, p)
+			}
+			continue
+		}
+		if p == nil || p.Match != tc.match || tc.pwd[p.Start:p.End] != tc.match {
+			t.Errorf("%q: expected match %q, got %+v", tc.pwd, tc.match, p)
+		}
+	}
+
+	v := NewPasswordValidator(8, 64, true, true, true, true, 0)
+	if _, score := v.Validate("Summer2024$"); score >= 20 {
+		t.Errorf("Summer2024$ should score very low, got %d", score)
+	}
+}
+
+func TestDetectPenalties(t *testing.T) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 50)
+	for _, pwd := range []string{"p@ssw0rd", "Summer2024$", "qwertydragon", "Xk9$mP2!vLq"} {
+		got, want := DetectPenalties(pwd), v.ValidateResult(pwd).Penalties
+		if len(got) != len(want) {
+			t.Fatalf("%q: DetectPenalties = %+v, ValidateResult = %+v", pwd, got, want)
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("%q: penalty %d = %+v, want %+v", pwd, i, got[i], want[i])
+			}
+		}
+	}
+
+	if p := DetectPenalties("asdkjhqwelkjzxc", WithSingleClassPenalty(0.5)); !hasPenalty(p, "single_class") {
+		t.Errorf("expected options to configure the detectors, got %+v", p)
+	}
+}
+
+func TestDisabledPenalties(t *testing.T) {
+	v := NewPasswordValidator(4, 64, false, false, false, false, 0)
+	kiosk := v.Clone(WithDisabledPenalties("keyboard_pattern", "sequential_chars"))
+
+	r, k := v.ValidateResult("qwerty1234"), kiosk.ValidateResult("qwerty1234")
+	if !hasPenalty(r.Penalties, "keyboard_pattern") {
+		t.Fatalf("expected a keyboard_pattern penalty, got %+v", r.Penalties)
+	}
+	if hasPenalty(k.Penalties, "keyboard_pattern") || hasPenalty(k.Penalties, "sequential_chars") {
+		t.Errorf("disabled penalties were applied: %+v", k.Penalties)
+	}
+	if k.Score <= r.Score {
+		t.Errorf("score with penalties disabled = %d, want above %d", k.Score, r.Score)
+	}
+	if got := kiosk.Policy().DisabledPenalties; !slices.Equal(got, []string{"keyboard_pattern", "sequential_chars"}) {
+		t.Errorf("Policy().DisabledPenalties = %q", got)
+	}
+
+	// Without the concatenation penalty, joined words still count as words.
+	p := DetectPenalties("dragonmonkey", WithDisabledPenalties("dictionary_concatenation"))
+	if hasPenalty(p, "dictionary_concatenation") || !hasPenalty(p, "dictionary_substring") {
+		t.Errorf("expected dictionary_substring instead of dictionary_concatenation, got %+v", p)
+	}
+
+	if _, err := NewValidatorStrict(8, 64, true, true, true, true, 0, WithDisabledPenalties("keyboard")); err == nil || !strings.Contains(err.Error(), `unknown penalty "keyboard"`) {
+		t.Errorf("expected an unknown penalty error, got %v", err)
+	}
+}
+
+func TestLeetHelpers(t *testing.T) {
+	if got := LeetNormalize("P@$$w0rd"); got != "password" {
+		t.Errorf("LeetNormalize = %q, want password", got)
+	}
+	if got := LeetVariants("H1", 10); !slices.Equal(got, []string{"hi", "hl", "h1"}) {
+		t.Errorf("LeetVariants = %q", got)
+	}
+	if got := LeetVariants("1111", 5); len(got) != 5 || got[0] != "iiii" {
+		t.Errorf("expected 5 variants starting with iiii, got %q", got)
+	}
+	if got := LeetVariants("h1", 0); got != nil {
+		t.Errorf("expected no variants for max 0, got %q", got)
+	}
+
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0, WithLeetMap(map[rune][]string{'¥': {"y"}}))
+	if got := v.LeetNormalize("M0n€¥"); got != "money" {
+		t.Errorf("validator LeetNormalize = %q, want money", got)
+	}
+	if got := v.LeetVariants("¥", 5); !slices.Equal(got, []string{"y", "¥"}) {
+		t.Errorf("validator LeetVariants = %q", got)
+	}
+}
+
+func TestExportClientPolicy(t *testing.T) {
+	v := NewPasswordValidator(10, 64, true, true, false, false, 60,
+		WithMinClassCounts(0, 0, 2, 0), WithBannedSubstrings("Acme"), WithMinCharClasses(3))
+
+	data, err := v.ExportClientPolicy()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p ClientPolicy
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.MinLength != 10 || p.MaxLength != 64 || p.MinScore != 60 || !slices.Equal(p.BannedSubstrings, []string{"acme"}) {
+		t.Errorf("unexpected policy %+v", p)
+	}
+
+	var codes []string
+	for _, r := range p.Requirements {
+		codes = append(codes, r.Code)
+	}
+	want := []string{RuleTooShort, RuleMissingLower, RuleMissingUpper, RuleMinDigits, RuleMinCharClasses}
+	if !slices.Equal(codes, want) {
+		t.Errorf("requirement codes = %v, want %v", codes, want)
+	}
+
+	// the codes mirror what the server reports for a failing password
+	r := v.ValidateResult("abc")
+	for _, code := range []string{RuleTooShort, RuleMissingUpper, RuleMinDigits} {
+		if !slices.Contains(r.Codes(), code) {
+			t.Errorf("server did not report %s: %v", code, r.Codes())
+		}
+	}
+}
+
+func TestReasonCodes(t *testing.T) {
+	codes := ReasonCodes()
+	v := NewPasswordValidator(8, 64, true, true, true, true, 50, WithSingleClassPenalty(0.5))
+	for _, pwd := range []string{"password", "p@ssw0rd", "aaaaaaaa", "abcdefgh", "qwertyui", "abababab",
+		"qwertydragon", "Xk9dragonQ7", "Summer2024$", "83920174xY", "6915553412aB!"} {
+		for _, code := range v.ValidateResult(pwd).Codes() {
+			if !slices.Contains(codes, code) {
+				t.Errorf("%q: code %s missing from ReasonCodes", pwd, code)
+			}
+		}
+	}
+}
+
+func TestDictionaryWordBoundaries(t *testing.T) {
+	for _, tc := range []struct {
+		pwd    string
+		factor float64 // 0 for no penalty
+	}{
+		{"Xk9dragonQ7", 0.5},          // stands on its own
+		{"dragon123", 0.5},            // at a class transition
+		{"mydragonisred", 0.85},       // runs into other letters
+		{"abcpassword", 0.75},         // one end aligned
+		{"StrangersInParadise42!", 0}, // "rangers" is a fragment of "Strangers"
+		{"Dragonfly99", 0},            // "dragon" is a fragment of "Dragonfly"
+		{"Dragonz", 0.2},              // mostly the word: context does not matter
+	} {
+		p := penaltyDictionarySubstring(analyze(tc.pwd, leetMap), globalDict, penaltyConfig{})
+		switch {
+		case tc.factor == 0 && p != nil:
+			t.Errorf("%q: unexpected penalty %+v", tc.pwd, p)
+		case tc.factor != 0 && (p == nil || p.Factor != tc.factor):
+			t.Errorf("%q: expected factor %.2f, got %+v", tc.pwd, tc.factor, p)
+		}
+	}
+}
+
+func TestKeyboardShiftVariations(t *testing.T) {
+	tests := []struct {
+		password string
+		match    int // keyboard run length, 0 for none
+	}{
+		{"!@#$%^Tz", 6},
+		{"1@3$5^Tz", 6},
+		{"QwErTy!23456", 12},
+		{"zX!9@vB(", 0},
+	}
+	for _, tt := range tests {
+		var got *PenaltyDetail
+		for _, p := range DetectPenalties(tt.password) {
+			if p.Rule == "keyboard_pattern" {
+				got = &p
+			}
+		}
+		switch {
+		case tt.match == 0 && got != nil:
+			t.Errorf("%q: unexpected keyboard pattern %+v", tt.password, *got)
+		case tt.match > 0 && (got == nil || got.End-got.Start != tt.match):
+			t.Errorf("%q: keyboard pattern = %+v, want %d chars", tt.password, got, tt.match)
+		case tt.match > 0 && strings.ContainsAny(tt.password, "!@#$%^") != strings.Contains(got.Desc, "shifted symbols"):
+			t.Errorf("%q: description %q should mention shifted symbols only when used", tt.password, got.Desc)
+		}
+	}
+}
+
+func TestPenaltyInputLimit(t *testing.T) {
+	v := NewPasswordValidator(8, 20000, false, false, false, false, 0, WithPenaltyInputLimit(256))
+	long := "é" + strings.Repeat("a", 10000)
+	r := v.ValidateResult(long)
+	if !hasWarning(r, WarnPartialScan) {
+		t.Fatalf("Warnings = %+v, want %s", r.Warnings, WarnPartialScan)
+	}
+	if !hasPenalty(r.Penalties, "repeated_chars") {
+		t.Errorf("Penalties = %+v, want repeated_chars from the scanned prefix", r.Penalties)
+	}
+	for _, p := range r.Penalties {
+		if p.End > 256 {
+			t.Errorf("%s spans [%d, %d), want within the first 256 bytes", p.Rule, p.Start, p.End)
+		}
+	}
+	if b := v.ValidateBytes([]byte(long)); !hasWarning(b, WarnPartialScan) || b.Score != r.Score {
+		t.Errorf("ValidateBytes score %d, warnings %+v; want score %d and %s", b.Score, b.Warnings, r.Score, WarnPartialScan)
+	}
+
+	// Short passwords are scanned in full without a warning.
+	if r := v.ValidateResult("qwerty123"); hasWarning(r, WarnPartialScan) || !hasPenalty(r.Penalties, "keyboard_pattern") {
+		t.Errorf("ValidateResult(qwerty123) = %+v, want a full scan", r)
+	}
+}
+
+func TestPenaltyTimeBudget(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0, WithPenaltyTimeBudget(time.Nanosecond))
+	r := v.ValidateResult("qwerty123")
+	if !hasWarning(r, WarnPartialScan) {
+		t.Errorf("Warnings = %+v, want %s", r.Warnings, WarnPartialScan)
+	}
+
+	v = NewPasswordValidator(8, 64, false, false, false, false, 0, WithPenaltyTimeBudget(time.Minute))
+	if r := v.ValidateResult("qwerty123"); hasWarning(r, WarnPartialScan) || !hasPenalty(r.Penalties, "keyboard_pattern") {
+		t.Errorf("ValidateResult(qwerty123) = %+v, want a full scan", r)
+	}
+}
+
+func TestAnalysisLimits(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, true, 0, WithAnalysisLimits(16, 0), WithMaxBytes(BcryptMaxBytes))
+	long := "Xq7zR2mK9wT4vB8n" + strings.Repeat("!", 10000)
+	r := v.ValidateResult(long)
+	if !hasWarning(r, WarnTruncated) {
+		t.Errorf("Warnings = %+v, want %s", r.Warnings, WarnTruncated)
+	}
+	// Length rules see the whole input; the symbols past the limit are not analyzed.
+	for _, code := range []string{RuleTooLong, RuleTooManyBytes, RuleMissingSymbol} {
+		if !slices.Contains(r.Codes(), code) {
+			t.Errorf("Codes() = %v, want %s", r.Codes(), code)
+		}
+	}
+	if hasPenalty(r.Penalties, "repeated_chars") {
+		t.Errorf("Penalties = %+v, want none from past the limit", r.Penalties)
+	}
+	if r := v.ValidateResult("Xq7zR2mK9w!"); hasWarning(r, WarnTruncated) || !r.Pass {
+		t.Errorf("ValidateResult(short) = %+v, want a full analysis that passes", r)
+	}
+	if p := v.Policy(); p.MaxAnalyzedRunes != 16 || p.MaxDictScanWords != 0 {
+		t.Errorf("Policy() limits = %d, %d; want 16, 0", p.MaxAnalyzedRunes, p.MaxDictScanWords)
+	}
+
+	d := loadDictionary("pass\npassword\nword")
+	if n := len(d.leetMatches("passwordpassword", 4, leetMap)); n != 6 {
+		t.Fatalf("leetMatches found %d words, want 6", n)
+	}
+	if n := len(d.leetMatchesN("passwordpassword", 4, leetMap, 2)); n != 2 {
+		t.Errorf("leetMatchesN(2) found %d words, want 2", n)
+	}
+}
+
+func TestNoMaxLength(t *testing.T) {
+	v := NewPasswordValidator(12, NoMax, true, false, false, false, 0)
+	if v.MaxLength != NoMax {
+		t.Fatalf("MaxLength = %d, want NoMax", v.MaxLength)
+	}
+	long := strings.Repeat("correct horse battery staple ", 20)
+	if r := v.ValidateResult(long); slices.Contains(r.Codes(), RuleTooLong) {
+		t.Errorf("Codes() = %v, want no %s without a maximum", r.Codes(), RuleTooLong)
+	}
+	if v := NewPasswordValidator(12, -1, true, false, false, false, 0); v.MaxLength != NoMax {
+		t.Errorf("negative max gave MaxLength %d, want NoMax", v.MaxLength)
+	}
+	if c := v.WithMinLength(80); c.MaxLength != NoMax {
+		t.Errorf("WithMinLength(80).MaxLength = %d, want NoMax", c.MaxLength)
+	}
+
+	pwd, err := v.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len([]rune(pwd)); n < 12 || n > noMaxGenerateLength {
+		t.Errorf("Generate() length %d, want 12-%d", n, noMaxGenerateLength)
+	}
+	if _, err := v.GenerateWithEntropy(1000); err != nil {
+		t.Errorf("GenerateWithEntropy(1000) = %v, want no length limit", err)
+	}
+	if err := v.GenerateInto(make([]byte, 200)); err != nil {
+		t.Errorf("GenerateInto(200 bytes) = %v", err)
+	}
+	if err := v.GenerateInto(make([]byte, 4)); err == nil {
+		t.Error("GenerateInto(4 bytes) should fail below MinLength")
+	}
+}
+
+func hasPenalty(penalties []PenaltyDetail, rule string) bool {
+	for _, p := range penalties {
+		if p.Rule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func findPenalty(penalties []PenaltyDetail, rule string) *PenaltyDetail {
+	for i := range penalties {
+		if penalties[i].Rule == rule {
+			return &penalties[i]
+		}
+	}
+	return nil
+}
+
+// Benchmarks
+func BenchmarkValidate(b *testing.B) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 50)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		v.Validate("MyP@ssw0rd!23")
+	}
+}
+
+func BenchmarkValidateParallel(b *testing.B) {
+	v := NewPasswordValidator(8, 64, true, true, true, true, 50)
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			v.Validate("MyP@ssw0rd!23")
+		}
+	})
+}
+
+// benchmarkPasswords are realistic inputs for the validation benchmarks: the
+// substring and leet-speak scans grow with the length of the password and the
+// number of dictionary words it contains.
+var benchmarkPasswords = []struct {
+	name, password string
+}{
+	{"short", "MyP@ssw0rd!23"},
+	{"passphrase", "correct horse battery staple under the old stone bridge at dawn"},
+	{"passphrase_leet", "c0rr3ct-h0rs3-b4tt3ry-st4pl3-und3r-th3-0ld-st0n3-br1dg3-@t-d4wn!"},
+	{"unicode", "Contraseña-Ωmega-密码安全-Straße-🔑🔒-パスワード-2024"},
+	{"dictionary", "dragonmonkeysunshinepasswordfootballbaseball"},
+	{"dictionary_leet", "Dr4g0nM0nk3ySunsh1n3P@ssw0rdF00tb@llB4s3b4ll"},
+}
+
+func BenchmarkValidateCorpus(b *testing.B) {
+	v := NewPasswordValidator(8, NoMax, false, false, false, false, 50)
+	for _, bc := range benchmarkPasswords {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				v.Validate(bc.password)
+			}
+		})
+	}
+}
+
+// BenchmarkValidateLargeDictionary validates the benchmark passwords against
+// the embedded dictionary extended with 1,000,000 synthetic words.
+func BenchmarkValidateLargeDictionary(b *testing.B) {
+	data := commonPasswordsData + "\n" + strings.Join(syntheticWords(1000000), "\n")
+	v := NewPasswordValidatorWithDict(8, NoMax, false, false, false, false, 50, data)
+	for _, bc := range benchmarkPasswords {
+		b.Run(bc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				v.Validate(bc.password)
+			}
+		})
+	}
+}
+
+// syntheticWords returns n random lowercase words of 6 to 11 letters, the
+// same for every run.
+func syntheticWords(n int) []string {
+	rng := mrand.New(mrand.NewSource(1))
+	words := make([]string, n)
+	for i := range words {
+		w := make([]byte, 6+rng.Intn(6))
+		for j := range w {
+			w[j] = byte('a' + rng.Intn(26))
+		}
+		words[i] = string(w)
+	}
+	return words
+}
+
+// BenchmarkLoadDictionary loads a synthetic 100,000-word list and reports the
+// heap the loaded dictionary retains.
+func BenchmarkLoadDictionary(b *testing.B) {
+	data := strings.Join(syntheticWords(100000), "\n")
+
+	var before, after runtime.MemStats
+	var d *dictionary
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		d = nil
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		d = loadDictionary(data)
+		runtime.GC()
+		runtime.ReadMemStats(&after)
 	}
+	b.ReportMetric(float64(after.HeapAlloc-before.HeapAlloc), "heap-B")
+	runtime.KeepAlive(d)
 }
 
 func BenchmarkGenerate(b *testing.B) {
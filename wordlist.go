@@ -0,0 +1,133 @@
+package passval
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Wordlist is a list of words to build passphrases from, such as a diceware
+// list. GeneratePassphrase draws words from it with WithPassphraseWordlist,
+// and validators given one with WithWordlist score passphrases by its size
+// and recognize passphrases written without separators. Implement it to
+// serve an approved list from elsewhere; Len must be constant and WordAt
+// valid for 0 <= i < Len.
+type Wordlist interface {
+	Len() int
+	WordAt(i int) string
+	Contains(word string) bool
+}
+
+// wordlist is a sorted list of unique lowercase words.
+type wordlist []string
+
+func (w wordlist) Len() int            { return len(w) }
+func (w wordlist) WordAt(i int) string { return w[i] }
+
+// Contains reports whether word, in any case, is in the list.
+func (w wordlist) Contains(word string) bool {
+	_, found := slices.BinarySearch(w, strings.ToLower(word))
+	return found
+}
+
+// minWordlistLen is the fewest words a wordlist may have: a passphrase from
+// fewer has no entropy.
+const minWordlistLen = 2
+
+// NewWordlist returns a wordlist of words, lowercased, with blanks and
+// duplicates removed.
+func NewWordlist(words []string) (Wordlist, error) {
+	w := newWordlist(words)
+	if len(w) < minWordlistLen {
+		return nil, fmt.Errorf("wordlist has %d words, need at least %d", len(w), minWordlistLen)
+	}
+	return w, nil
+}
+
+func newWordlist(words []string) wordlist {
+	w := make(wordlist, 0, len(words))
+	for _, word := range words {
+		if word = strings.ToLower(strings.TrimSpace(word)); word != "" {
+			w = append(w, word)
+		}
+	}
+	slices.Sort(w)
+	return slices.Compact(w)
+}
+
+// LoadWordlist reads a wordlist of one word per line, skipping blank lines
+// and lines starting with '#'. Lines of several fields keep the last one, so
+// diceware files such as the EFF lists ("11111	abacus") load as they are
+// published.
+func LoadWordlist(r io.Reader) (Wordlist, error) {
+	var words []string
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		words = append(words, fields[len(fields)-1])
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading wordlist: %w", err)
+	}
+	return NewWordlist(words)
+}
+
+// DefaultWordlist returns the embedded wordlist GeneratePassphrase uses by
+// default, a 1,292-word sample worth about 10.3 bits per word.
+func DefaultWordlist() Wordlist {
+	return defaultWordlist
+}
+
+// WithWordlist sets the wordlist passphrases are assumed to come from: with a
+// passphrase policy, passphrases are scored as unique words × log2(wl.Len())
+// instead of the size of the embedded wordlist, and a password made entirely
+// of list words without separators ("correcthorsebatterystaple") counts as a
+// passphrase too. SuggestStronger also draws its words from wl.
+func WithWordlist(wl Wordlist) Option {
+	return func(v *PasswordValidator) {
+		v.wordlist = wl
+	}
+}
+
+// maxSegmentWord bounds the length of the words segmentWords looks up, which
+// keeps segmentation linear in the password length.
+const maxSegmentWord = 24
+
+// segmentWords splits s, made only of letters, into words of wl of at least
+// minLen runes, preferring the split with the fewest words, and reports
+// whether there is one.
+func segmentWords(s string, wl Wordlist, minLen int) ([]string, bool) {
+	runes := []rune(s)
+	if len(runes) == 0 || slices.ContainsFunc(runes, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		return nil, false
+	}
+	// best[i] is the fewest words covering runes[:i], or -1; from[i] is where
+	// the last of those words starts.
+	best, from := make([]int, len(runes)+1), make([]int, len(runes)+1)
+	for i := 1; i <= len(runes); i++ {
+		best[i] = -1
+		for j := max(0, i-maxSegmentWord); j <= i-max(minLen, 1); j++ {
+			if best[j] < 0 || best[i] >= 0 && best[j]+1 >= best[i] {
+				continue
+			}
+			if wl.Contains(string(runes[j:i])) {
+				best[i], from[i] = best[j]+1, j
+			}
+		}
+	}
+	if best[len(runes)] < 0 {
+		return nil, false
+	}
+	words := make([]string, 0, best[len(runes)])
+	for i := len(runes); i > 0; i = from[i] {
+		words = append(words, string(runes[from[i]:i]))
+	}
+	slices.Reverse(words)
+	return words, true
+}
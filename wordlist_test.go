@@ -0,0 +1,109 @@
+package passval
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func TestLoadWordlist(t *testing.T) {
+	wl, err := LoadWordlist(strings.NewReader("# approved words\n11111\tAbacus\n11112\tabdomen\n\nzebra\nabacus\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wl.Len() != 3 || wl.WordAt(0) != "abacus" || !wl.Contains("ZEBRA") || wl.Contains("11111") {
+		t.Errorf("LoadWordlist = %v", wl)
+	}
+	if _, err := LoadWordlist(strings.NewReader("one\n")); err == nil {
+		t.Error("expected an error for a one-word list")
+	}
+	if DefaultWordlist().Len() != len(defaultWordlist) {
+		t.Errorf("DefaultWordlist().Len() = %d", DefaultWordlist().Len())
+	}
+}
+
+// TestLoadDicewareLayouts loads files laid out like the EFF long (five dice,
+// 7776 words) and short (four dice, 1296 words) lists.
+func TestLoadDicewareLayouts(t *testing.T) {
+	for _, dice := range []int{5, 4} {
+		var b strings.Builder
+		var rolls func(prefix string, n int)
+		rolls = func(prefix string, n int) {
+			if n == 0 {
+				fmt.Fprintf(&b, "%s\tword%s\n", prefix, prefix)
+				return
+			}
+			for d := '1'; d <= '6'; d++ {
+				rolls(prefix+string(d), n-1)
+			}
+		}
+		rolls("", dice)
+		wl, err := LoadWordlist(strings.NewReader(b.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := int(math.Pow(6, float64(dice))); wl.Len() != want {
+			t.Errorf("%d-dice list: Len() = %d, want %d", dice, wl.Len(), want)
+		}
+	}
+}
+
+func TestPassphraseWordlist(t *testing.T) {
+	wl, err := NewWordlist([]string{"correct", "horse", "battery", "staple", "orange", "nimbus"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := GeneratePassphraseWithInfo(4, "-", WithPassphraseWordlist(wl))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range strings.Split(g.Passphrase, "-") {
+		if !wl.Contains(w) {
+			t.Errorf("word %q of %q is not from the wordlist", w, g.Passphrase)
+		}
+	}
+	if g.WordlistSize != 6 || math.Abs(g.Entropy-4*math.Log2(6)) > 1e-9 {
+		t.Errorf("GeneratePassphraseWithInfo = %+v, want entropy from 6 words", g)
+	}
+
+	v := NewPasswordValidator(12, 64, true, false, true, true, 0, WithPassphrasePolicy(4, 5), WithWordlist(wl))
+	words, ok := v.passphraseWords("correcthorsebatterystaple")
+	if !ok || !slices.Equal(words, []string{"correct", "horse", "battery", "staple"}) {
+		t.Errorf("passphraseWords = %v, %v; want the four list words", words, ok)
+	}
+	if r := v.ValidateResult("correcthorsebatterystaple"); slices.Contains(r.Codes(), RuleMissingNumber) {
+		t.Errorf("Codes() = %v, want an unseparated passphrase exempt from composition rules", r.Codes())
+	}
+	if got, want := v.entropy(analyze("orange nimbus horse staple", leetMap)), 4*math.Log2(6); math.Abs(got-want) > 1e-9 {
+		t.Errorf("entropy = %.2f, want %.2f from the wordlist size", got, want)
+	}
+	if _, ok := v.passphraseWords("correcthorsebatterystapler"); ok {
+		t.Error("a password that does not split into list words should not be a passphrase")
+	}
+	if p := v.Policy(); p.WordlistSize != 6 {
+		t.Errorf("Policy().WordlistSize = %d, want 6", p.WordlistSize)
+	}
+}
+
+func TestPassphraseCapitalizeNonASCII(t *testing.T) {
+	wl, err := NewWordlist([]string{"éclair", "ñandú", "øresund"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	phrase, _, err := GeneratePassphrase(6, " ", WithPassphraseWordlist(wl), WithPassphraseCapitalize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !utf8.ValidString(phrase) || strings.ContainsRune(phrase, utf8.RuneError) {
+		t.Fatalf("GeneratePassphrase() = %q, want valid UTF-8", phrase)
+	}
+	for _, w := range strings.Fields(phrase) {
+		if r, _ := utf8.DecodeRuneInString(w); !unicode.IsUpper(r) || !wl.Contains(strings.ToLower(w)) {
+			t.Errorf("word %q of %q is not a capitalized list word", w, phrase)
+		}
+	}
+}
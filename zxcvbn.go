@@ -0,0 +1,159 @@
+package passval
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// ZxcvbnResult mirrors the result object of the zxcvbn JavaScript library,
+// so that frontends built around it can be served by this library unchanged.
+type ZxcvbnResult struct {
+	Score             int                `json:"score"` // 0-4
+	Guesses           float64            `json:"guesses"`
+	GuessesLog10      float64            `json:"guesses_log10"`
+	CrackTimesSeconds map[string]float64 `json:"crack_times_seconds"`
+	CrackTimesDisplay map[string]string  `json:"crack_times_display"`
+	Feedback          ZxcvbnFeedback     `json:"feedback"`
+}
+
+// ZxcvbnFeedback mirrors the feedback object of zxcvbn.
+type ZxcvbnFeedback struct {
+	Warning     string   `json:"warning"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// zxcvbnAttacks maps zxcvbn's crack time scenarios to attack models.
+var zxcvbnAttacks = []struct {
+	key   string
+	model AttackModel
+}{
+	{"online_throttling_100_per_hour", AttackOnlineThrottled},
+	{"online_no_throttling_10_per_second", AttackOnlineUnthrottled},
+	{"offline_slow_hashing_1e4_per_second", AttackOfflineSlowHash},
+	{"offline_fast_hashing_1e10_per_second", AttackOfflineFastHash},
+}
+
+// zxcvbnFeedback holds the warning and suggestion zxcvbn-style feedback gives
+// for each penalty.
+var zxcvbnFeedback = map[string]ZxcvbnFeedback{
+	"common_password":          {"This is a very common password.", nil},
+	"common_password_leet":     {"This is similar to a commonly used password.", []string{"Predictable substitutions like '@' instead of 'a' don't help very much."}},
+	"repeated_chars":           {`Repeats like "aaa" are easy to guess.`, []string{"Avoid repeated words and characters."}},
+	"repeated_pattern":         {`Repeats like "abcabcabc" are only slightly harder to guess than "abc".`, []string{"Avoid repeated words and characters."}},
+	"sequential_chars":         {"Sequences like abc or 6543 are easy to guess.", []string{"Avoid sequences."}},
+	"keyboard_pattern":         {"Straight rows of keys are easy to guess.", []string{"Avoid keyboard patterns."}},
+	"dictionary_substring":     {"A word by itself is easy to guess.", nil},
+	"dictionary_concatenation": {"Common passwords joined together are easy to guess.", nil},
+	"topical_word":             {"Names of teams, places and celebrities are easy to guess.", nil},
+	"natural_word":             {"Common words are easy to guess.", []string{"Add another word or two. Uncommon words are better."}},
+	"season_year":              {"Dates are often easy to guess.", []string{"Avoid dates and years that are associated with you."}},
+	"date":                     {"Dates are often easy to guess.", []string{"Avoid dates and years that are associated with you."}},
+	"digit_run":                {"Long numbers like ID numbers are easy to guess.", []string{"Avoid numbers that are associated with you."}},
+	"phone_number":             {"Phone numbers are easy to guess.", []string{"Avoid numbers that are associated with you."}},
+	"email_address":            {"Email addresses are easy to guess.", []string{"Avoid addresses that are associated with you."}},
+	"url":                      {"Web addresses are easy to guess.", []string{"Avoid addresses that are associated with you."}},
+	"single_class":             {"", []string{"Mix letters with numbers or symbols."}},
+}
+
+// ZxcvbnCompat evaluates password and returns the result in the shape zxcvbn
+// produces, so that a JavaScript strength meter can be replaced server-side
+// without changing client contracts. The 0-4 score uses zxcvbn's guess
+// thresholds (10^3, 10^6, 10^8 and 10^10) on EstimateGuesses, and feedback is
+// only given for scores up to 2, as in zxcvbn. Policy rules are not part of
+// the zxcvbn shape; use ValidateResult to enforce them.
+func (v *PasswordValidator) ZxcvbnCompat(password string) *ZxcvbnResult {
+	r := v.validate(password)
+	guesses := max(estimateGuesses(r.Entropy, r.Score), 1)
+
+	res := &ZxcvbnResult{
+		Score:             zxcvbnScore(guesses),
+		Guesses:           guesses,
+		GuessesLog10:      math.Log10(guesses),
+		CrackTimesSeconds: make(map[string]float64, len(zxcvbnAttacks)),
+		CrackTimesDisplay: make(map[string]string, len(zxcvbnAttacks)),
+		Feedback:          ZxcvbnFeedback{Suggestions: []string{}},
+	}
+	for _, a := range zxcvbnAttacks {
+		seconds := guesses / a.model.GuessesPerSecond()
+		res.CrackTimesSeconds[a.key] = seconds
+		res.CrackTimesDisplay[a.key] = displayTime(seconds)
+	}
+	if res.Score <= 2 {
+		res.Feedback = zxcvbnFeedbackFor(r.Penalties)
+	}
+	return res
+}
+
+// zxcvbnScore maps a guess count to zxcvbn's 0-4 score.
+func zxcvbnScore(guesses float64) int {
+	const delta = 5
+	switch {
+	case guesses < 1e3+delta:
+		return 0
+	case guesses < 1e6+delta:
+		return 1
+	case guesses < 1e8+delta:
+		return 2
+	case guesses < 1e10+delta:
+		return 3
+	}
+	return 4
+}
+
+// zxcvbnFeedbackFor returns the warning of the strongest penalty and the
+// suggestions of all penalties, after zxcvbn's general advice.
+func zxcvbnFeedbackFor(penalties []PenaltyDetail) ZxcvbnFeedback {
+	fb := ZxcvbnFeedback{Suggestions: []string{"Add another word or two. Uncommon words are better."}}
+	strongest := 1.0
+	for _, p := range penalties {
+		f := zxcvbnFeedback[p.Rule]
+		if f.Warning != "" && p.Factor < strongest {
+			fb.Warning, strongest = f.Warning, p.Factor
+		}
+		for _, s := range f.Suggestions {
+			if !slices.Contains(fb.Suggestions, s) {
+				fb.Suggestions = append(fb.Suggestions, s)
+			}
+		}
+	}
+	return fb
+}
+
+// displayTime renders seconds the way zxcvbn does, e.g. "3 hours" or
+// "centuries".
+func displayTime(seconds float64) string {
+	const (
+		minute  = 60
+		hour    = minute * 60
+		day     = hour * 24
+		month   = day * 31
+		year    = month * 12
+		century = year * 100
+	)
+	units := []struct {
+		name string
+		size float64
+		next float64
+	}{
+		{"second", 1, minute},
+		{"minute", minute, hour},
+		{"hour", hour, day},
+		{"day", day, month},
+		{"month", month, year},
+		{"year", year, century},
+	}
+	if seconds < 1 {
+		return "less than a second"
+	}
+	for _, u := range units {
+		if seconds < u.next {
+			n := int(math.Round(seconds / u.size))
+			if n == 1 {
+				return fmt.Sprintf("1 %s", u.name)
+			}
+			return fmt.Sprintf("%d %ss", n, u.name)
+		}
+	}
+	return "centuries"
+}
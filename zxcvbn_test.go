@@ -0,0 +1,56 @@
+package passval
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestZxcvbnCompat(t *testing.T) {
+	v := NewPasswordValidator(8, 64, false, false, false, false, 0)
+
+	weak := v.ZxcvbnCompat("password")
+	if weak.Score != 0 {
+		t.Errorf("ZxcvbnCompat(password).Score = %d, want 0", weak.Score)
+	}
+	if weak.Feedback.Warning != "This is a very common password." || len(weak.Feedback.Suggestions) == 0 {
+		t.Errorf("ZxcvbnCompat(password).Feedback = %+v", weak.Feedback)
+	}
+
+	strong := v.ZxcvbnCompat("Xk9$mP2!vLq#Rw7z")
+	if strong.Score != 4 {
+		t.Errorf("ZxcvbnCompat(random).Score = %d (guesses 10^%.1f), want 4", strong.Score, strong.GuessesLog10)
+	}
+	if strong.Feedback.Warning != "" || len(strong.Feedback.Suggestions) != 0 {
+		t.Errorf("strong passwords should get no feedback, got %+v", strong.Feedback)
+	}
+	if got := strong.CrackTimesDisplay["online_throttling_100_per_hour"]; got != "centuries" {
+		t.Errorf("throttled crack time = %q, want centuries", got)
+	}
+
+	b, err := json.Marshal(v.ZxcvbnCompat("dragon2024"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{`"score":`, `"guesses_log10":`, `"offline_fast_hashing_1e10_per_second":`, `"feedback":{"warning":`, `"suggestions":[`} {
+		if !strings.Contains(string(b), key) {
+			t.Errorf("JSON missing %s: %s", key, b)
+		}
+	}
+}
+
+func TestZxcvbnScoreAndDisplay(t *testing.T) {
+	for guesses, want := range map[float64]int{1: 0, 1e3: 0, 1e4: 1, 1e7: 2, 1e9: 3, 1e11: 4} {
+		if got := zxcvbnScore(guesses); got != want {
+			t.Errorf("zxcvbnScore(%g) = %d, want %d", guesses, got, want)
+		}
+	}
+	for seconds, want := range map[float64]string{
+		0.5: "less than a second", 1: "1 second", 150: "3 minutes",
+		7200: "2 hours", 86400 * 40: "1 month", 86400 * 31 * 12 * 5: "5 years", 1e12: "centuries",
+	} {
+		if got := displayTime(seconds); got != want {
+			t.Errorf("displayTime(%g) = %q, want %q", seconds, got, want)
+		}
+	}
+}